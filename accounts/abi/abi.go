@@ -21,8 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
 )
 
 // The ABI holds information about a contract's context and available
@@ -46,6 +51,31 @@ func JSON(reader io.Reader) (ABI, error) {
 	return abi, nil
 }
 
+// JSONStrict returns a parsed ABI interface like JSON, but fails if any method
+// name is declared more than once instead of silently renaming the duplicates.
+// Note, this also rejects overloaded methods, since they share the same name.
+func JSONStrict(reader io.Reader) (ABI, error) {
+	abi, err := JSON(reader)
+	if err != nil {
+		return ABI{}, err
+	}
+	var (
+		seen       = make(map[string]bool)
+		duplicates []string
+	)
+	for _, method := range abi.Methods {
+		if method.RawName != "" && method.Name != method.RawName && !seen[method.RawName] {
+			seen[method.RawName] = true
+			duplicates = append(duplicates, method.RawName)
+		}
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return ABI{}, fmt.Errorf("abi: duplicate method names: %s", strings.Join(duplicates, ", "))
+	}
+	return abi, nil
+}
+
 // Pack the given method name to conform the ABI. Method call's data
 // will consist of method_id, args0, arg1, ... argN. Method id consists
 // of 4 bytes and arguments are all 32 bytes.
@@ -73,6 +103,22 @@ func (abi ABI) Pack(name string, args ...interface{}) ([]byte, error) {
 	return append(method.Id(), arguments...), nil
 }
 
+// PackConstructorWithCode validates the given arguments against the constructor
+// inputs, encodes them and appends them to the contract bytecode, returning the
+// deployment-ready calldata. The bytecode itself is not modified.
+func (abi ABI) PackConstructorWithCode(bytecode []byte, args ...interface{}) ([]byte, error) {
+	if err := abi.Constructor.Inputs.ValidateArgs(args...); err != nil {
+		return nil, fmt.Errorf("abi: constructor: %v", err)
+	}
+	arguments, err := abi.Constructor.Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, len(bytecode)+len(arguments))
+	data = append(data, bytecode...)
+	return append(data, arguments...), nil
+}
+
 // Unpack output in v according to the abi specification
 func (abi ABI) Unpack(v interface{}, name string, data []byte) (err error) {
 	if len(data) == 0 {
@@ -92,6 +138,24 @@ func (abi ABI) Unpack(v interface{}, name string, data []byte) (err error) {
 	return fmt.Errorf("abi: could not locate named method or event")
 }
 
+// UnpackReuse unpacks the output of a method or event like Unpack, reusing the
+// memory already held by v. See Arguments.UnpackReuse for the aliasing caveats.
+func (abi ABI) UnpackReuse(v interface{}, name string, data []byte) (err error) {
+	if len(data) == 0 {
+		return fmt.Errorf("abi: unmarshalling empty output")
+	}
+	if method, ok := abi.Methods[name]; ok {
+		if len(data)%32 != 0 {
+			return fmt.Errorf("abi: improperly formatted output")
+		}
+		return method.Outputs.UnpackReuse(v, data)
+	}
+	if event, ok := abi.Events[name]; ok {
+		return event.Inputs.UnpackReuse(v, data)
+	}
+	return fmt.Errorf("abi: could not locate named method or event")
+}
+
 // UnpackIntoMap unpacks a log into the provided map[string]interface{}
 func (abi ABI) UnpackIntoMap(v map[string]interface{}, name string, data []byte) (err error) {
 	if len(data) == 0 {
@@ -111,15 +175,164 @@ func (abi ABI) UnpackIntoMap(v map[string]interface{}, name string, data []byte)
 	return fmt.Errorf("abi: could not locate named method or event")
 }
 
+// UnpackIntoMapHex unpacks a log or method output into the provided map, encoding
+// integers wider than 64 bits as hex strings for safe JSON transport.
+func (abi ABI) UnpackIntoMapHex(v map[string]interface{}, name string, data []byte) (err error) {
+	if len(data) == 0 {
+		return fmt.Errorf("abi: unmarshalling empty output")
+	}
+	if method, ok := abi.Methods[name]; ok {
+		if len(data)%32 != 0 {
+			return fmt.Errorf("abi: improperly formatted output")
+		}
+		return method.Outputs.UnpackIntoMapHex(v, data)
+	}
+	if event, ok := abi.Events[name]; ok {
+		return event.Inputs.UnpackIntoMapHex(v, data)
+	}
+	return fmt.Errorf("abi: could not locate named method or event")
+}
+
+// UnpackOrdered unpacks a log or method output into a list of name and value
+// pairs, in the order the arguments are declared in the ABI.
+func (abi ABI) UnpackOrdered(name string, data []byte) ([]NamedValue, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("abi: unmarshalling empty output")
+	}
+	if method, ok := abi.Methods[name]; ok {
+		if len(data)%32 != 0 {
+			return nil, fmt.Errorf("abi: improperly formatted output")
+		}
+		return method.Outputs.UnpackOrdered(data)
+	}
+	if event, ok := abi.Events[name]; ok {
+		return event.Inputs.UnpackOrdered(data)
+	}
+	return nil, fmt.Errorf("abi: could not locate named method or event")
+}
+
+// ResolveCall identifies the method invoked by the given calldata via its leading
+// selector and decodes the remaining bytes into the method's input arguments, in
+// the order they are declared. The recipient of the call is needed to tell
+// contract creations (nil recipient) apart from regular calls.
+//
+// ErrConstructorCall is returned for contract creations and ErrEmptyCallData for
+// empty calldata.
+func (abi ABI) ResolveCall(to *common.Address, data []byte) (*Method, []interface{}, error) {
+	if to == nil {
+		return nil, nil, ErrConstructorCall
+	}
+	if len(data) == 0 {
+		return nil, nil, ErrEmptyCallData
+	}
+	method, err := abi.MethodById(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return method, args, nil
+}
+
+// DecodeCall identifies the method invoked by the given calldata via its leading
+// selector and unpacks the remaining bytes into a map of the method's named input
+// arguments.
+func (abi ABI) DecodeCall(data []byte) (string, map[string]interface{}, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("abi: calldata too short (%d bytes) to contain a method id", len(data))
+	}
+	method, err := abi.MethodById(data[:4])
+	if err != nil {
+		return "", nil, err
+	}
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return "", nil, err
+	}
+	return method.Name, args, nil
+}
+
+// FormatCall renders the given calldata as a single line for human display,
+// e.g. `transfer(0x1234...abcd, 1000000000000000000)`. Addresses are shortened,
+// integers printed in decimal, byte values in hex and strings quoted. Calldata
+// with an unknown selector is rendered as the raw selector followed by the 32
+// byte words of its arguments in hex.
+func (abi ABI) FormatCall(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", fmt.Errorf("abi: calldata too short (%d bytes) to contain a method id", len(data))
+	}
+	method, err := abi.MethodById(data[:4])
+	if err != nil {
+		words := make([]string, 0, (len(data)-4+31)/32)
+		for i := 4; i < len(data); i += 32 {
+			end := i + 32
+			if end > len(data) {
+				end = len(data)
+			}
+			words = append(words, hexutil.Encode(data[i:end]))
+		}
+		return fmt.Sprintf("%s(%s)", hexutil.Encode(data[:4]), strings.Join(words, ", ")), nil
+	}
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return "", err
+	}
+	args := make([]string, len(values))
+	for i, value := range values {
+		args[i] = formatValue(method.Inputs[i].Type, reflect.ValueOf(value))
+	}
+	name := method.RawName
+	if name == "" {
+		name = method.Name
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")), nil
+}
+
+// formatValue renders a single unpacked value of the given type for display.
+func formatValue(t Type, v reflect.Value) string {
+	v = indirect(v)
+	switch t.T {
+	case AddressTy:
+		hex := v.Interface().(common.Address).Hex()
+		return hex[:6] + "..." + hex[len(hex)-4:]
+	case StringTy:
+		return strconv.Quote(v.String())
+	case BytesTy:
+		return hexutil.Encode(v.Bytes())
+	case FixedBytesTy, FunctionTy:
+		blob := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(blob), v)
+		return hexutil.Encode(blob)
+	case SliceTy, ArrayTy:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(*t.Elem, v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case TupleTy:
+		fields := make([]string, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			fields[i] = formatValue(*elem, v.Field(i))
+		}
+		return "(" + strings.Join(fields, ", ") + ")"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface
 func (abi *ABI) UnmarshalJSON(data []byte) error {
 	var fields []struct {
-		Type      string
-		Name      string
-		Constant  bool
-		Anonymous bool
-		Inputs    []Argument
-		Outputs   []Argument
+		Type            string
+		Name            string
+		Constant        bool
+		Payable         bool
+		StateMutability string
+		Anonymous       bool
+		Inputs          []Argument
+		Outputs         []Argument
 	}
 
 	if err := json.Unmarshal(data, &fields); err != nil {
@@ -132,7 +345,8 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 		switch field.Type {
 		case "constructor":
 			abi.Constructor = Method{
-				Inputs: field.Inputs,
+				Payable: field.Payable || field.StateMutability == "payable",
+				Inputs:  field.Inputs,
 			}
 		// empty defaults to function according to the abi spec
 		case "function", "":
@@ -144,7 +358,9 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 			}
 			abi.Methods[name] = Method{
 				Name:    name,
-				Const:   field.Constant,
+				RawName: field.Name,
+				Const:   field.Constant || field.StateMutability == "view" || field.StateMutability == "pure",
+				Payable: field.Payable || field.StateMutability == "payable",
 				Inputs:  field.Inputs,
 				Outputs: field.Outputs,
 			}
@@ -166,18 +382,116 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ConstructorPayable reports whether the contract's constructor accepts value,
+// meaning that ether may be sent along with the deployment transaction.
+func (abi ABI) ConstructorPayable() bool {
+	return abi.Constructor.Payable
+}
+
 // MethodById looks up a method by the 4-byte id
 // returns nil if none found
+//
+// Overloaded methods have distinct ids, so the exact selector is matched. Should
+// an ABI nonetheless declare the same signature multiple times, the method with
+// the lowest name is returned to keep the lookup independent of map ordering.
 func (abi *ABI) MethodById(sigdata []byte) (*Method, error) {
 	if len(sigdata) < 4 {
 		return nil, fmt.Errorf("data too short (%d bytes) for abi method lookup", len(sigdata))
 	}
-	for _, method := range abi.Methods {
-		if bytes.Equal(method.Id(), sigdata[:4]) {
-			return &method, nil
+	var found *Method
+	for name, method := range abi.Methods {
+		if bytes.Equal(method.Id(), sigdata[:4]) && (found == nil || name < found.Name) {
+			method := method
+			found = &method
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no method with id: %#x", sigdata[:4])
+	}
+	return found, nil
+}
+
+// Selectors returns the 4 byte selector of every method in the ABI, mapped to the
+// name the method is stored under (i.e. including any overload suffix).
+func (abi *ABI) Selectors() map[[4]byte]string {
+	selectors := make(map[[4]byte]string, len(abi.Methods))
+	for name, method := range abi.Methods {
+		var id [4]byte
+		copy(id[:], method.Id())
+		selectors[id] = name
+	}
+	return selectors
+}
+
+// EventTopics returns the topic hash of every event in the ABI, mapped to the
+// name the event is stored under.
+func (abi *ABI) EventTopics() map[common.Hash]string {
+	topics := make(map[common.Hash]string, len(abi.Events))
+	for name, event := range abi.Events {
+		topics[event.Id()] = name
+	}
+	return topics
+}
+
+// ImplementsInterface reports whether every method and event of the given
+// interface ABI has its selector or topic present in this ABI. Names and argument
+// names are irrelevant, only the signatures are compared. The signatures missing
+// from this ABI are returned in sorted order, methods prefixed with "function "
+// and events with "event ".
+func (abi ABI) ImplementsInterface(iface ABI) (bool, []string) {
+	selectors, topics := abi.Selectors(), abi.EventTopics()
+
+	var missing []string
+	for _, method := range iface.Methods {
+		var id [4]byte
+		copy(id[:], method.Id())
+		if _, ok := selectors[id]; !ok {
+			missing = append(missing, "function "+method.Sig())
+		}
+	}
+	for _, event := range iface.Events {
+		if _, ok := topics[event.Id()]; !ok {
+			missing = append(missing, "event "+event.sig())
+		}
+	}
+	sort.Strings(missing)
+	return len(missing) == 0, missing
+}
+
+// EventFilter builds the topics filter matching the logs of the named event. The
+// first topic is the event signature (omitted for anonymous events), followed by
+// one position per indexed argument, in order. Each position matches any of the
+// given values, while an empty or missing value set matches anything.
+func (abi ABI) EventFilter(name string, indexedArgs ...[]interface{}) ([][]common.Hash, error) {
+	event, exist := abi.Events[name]
+	if !exist {
+		return nil, fmt.Errorf("event '%s' not found", name)
+	}
+	var indexed Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexedArgs) > len(indexed) {
+		return nil, fmt.Errorf("abi: too many indexed argument filters: %d for %d", len(indexedArgs), len(indexed))
+	}
+	var topics [][]common.Hash
+	if !event.Anonymous {
+		topics = append(topics, []common.Hash{event.Id()})
+	}
+	for i, values := range indexedArgs {
+		var position []common.Hash
+		for _, value := range values {
+			topic, err := makeTopic(indexed[i].Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("abi: indexed argument %d (%s): %v", i, indexed[i].Name, err)
+			}
+			position = append(position, topic)
 		}
+		topics = append(topics, position)
 	}
-	return nil, fmt.Errorf("no method with id: %#x", sigdata[:4])
+	return topics, nil
 }
 
 // EventByID looks an event up by its topic hash in the
@@ -61,12 +61,12 @@ func TestReader(t *testing.T) {
 	exp := ABI{
 		Methods: map[string]Method{
 			"balance": {
-				"balance", true, nil, nil,
+				"balance", true, nil, nil, false, "balance",
 			},
 			"send": {
 				"send", false, []Argument{
 					{"amount", Uint256, false},
-				}, nil,
+				}, nil, false, "send",
 			},
 		},
 	}
@@ -175,7 +175,7 @@ func TestTestSlice(t *testing.T) {
 
 func TestMethodSignature(t *testing.T) {
 	String, _ := NewType("string", nil)
-	m := Method{"foo", false, []Argument{{"bar", String, false}, {"baz", String, false}}, nil}
+	m := Method{"foo", false, []Argument{{"bar", String, false}, {"baz", String, false}}, nil, false, "foo"}
 	exp := "foo(string,string)"
 	if m.Sig() != exp {
 		t.Error("signature mismatch", exp, "!=", m.Sig())
@@ -187,7 +187,7 @@ func TestMethodSignature(t *testing.T) {
 	}
 
 	uintt, _ := NewType("uint256", nil)
-	m = Method{"foo", false, []Argument{{"bar", uintt, false}}, nil}
+	m = Method{"foo", false, []Argument{{"bar", uintt, false}}, nil, false, "foo"}
 	exp = "foo(uint256)"
 	if m.Sig() != exp {
 		t.Error("signature mismatch", exp, "!=", m.Sig())
@@ -206,7 +206,7 @@ func TestMethodSignature(t *testing.T) {
 			{Name: "y", Type: "int256"},
 		}},
 	})
-	m = Method{"foo", false, []Argument{{"s", s, false}, {"bar", String, false}}, nil}
+	m = Method{"foo", false, []Argument{{"s", s, false}, {"bar", String, false}}, nil, false, "foo"}
 	exp = "foo((int256,int256[],(int256,int256)[],(int256,int256)[2]),string)"
 	if m.Sig() != exp {
 		t.Error("signature mismatch", exp, "!=", m.Sig())
@@ -573,6 +573,56 @@ func TestDefaultFunctionParsing(t *testing.T) {
 	}
 }
 
+func TestConstructorPayable(t *testing.T) {
+	tests := []struct {
+		definition string
+		payable    bool
+	}{
+		{`[{ "type" : "constructor", "inputs" : [{ "name" : "owner", "type" : "address" }] }]`, false},
+		{`[{ "type" : "constructor", "payable" : true, "inputs" : [] }]`, true},
+		{`[{ "type" : "constructor", "stateMutability" : "payable", "inputs" : [] }]`, true},
+		{`[{ "type" : "constructor", "stateMutability" : "nonpayable", "inputs" : [] }]`, false},
+		{`[{ "type" : "function", "name" : "balance", "constant" : true }]`, false},
+	}
+	for i, test := range tests {
+		abi, err := JSON(strings.NewReader(test.definition))
+		if err != nil {
+			t.Fatalf("test %d: failed to parse ABI: %v", i, err)
+		}
+		if payable := abi.ConstructorPayable(); payable != test.payable {
+			t.Errorf("test %d: payable mismatch: have %v, want %v", i, payable, test.payable)
+		}
+	}
+}
+
+func TestPackConstructorWithCode(t *testing.T) {
+	abi, err := JSON(strings.NewReader(`[{ "type" : "constructor", "inputs" : [{ "name" : "owner", "type" : "address" }, { "name" : "supply", "type" : "uint256" }] }]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := common.Hex2Bytes("6060604052")
+	owner := common.HexToAddress("0x0102030405060708091011121314151617181920")
+
+	data, err := abi.PackConstructorWithCode(code, owner, big.NewInt(42))
+	if err != nil {
+		t.Fatalf("failed to pack constructor: %v", err)
+	}
+	want := append(common.CopyBytes(code), common.LeftPadBytes(owner.Bytes(), 32)...)
+	want = append(want, common.LeftPadBytes([]byte{42}, 32)...)
+	if !bytes.Equal(data, want) {
+		t.Errorf("calldata mismatch: have %x, want %x", data, want)
+	}
+	if !bytes.Equal(code, common.Hex2Bytes("6060604052")) {
+		t.Errorf("bytecode modified: %x", code)
+	}
+	if _, err := abi.PackConstructorWithCode(code, owner); err == nil {
+		t.Errorf("expected error for missing argument")
+	}
+	if _, err := abi.PackConstructorWithCode(code, big.NewInt(1), owner); err == nil {
+		t.Errorf("expected error for mistyped arguments")
+	}
+}
+
 func TestBareEvents(t *testing.T) {
 	const definition = `[
 	{ "type" : "event", "name" : "balance" },
@@ -921,6 +971,213 @@ func TestABI_MethodById(t *testing.T) {
 	}
 }
 
+func TestABI_MethodByIdOverloaded(t *testing.T) {
+	const abiJSON = `[
+		{"type":"function","name":"foo","constant":false,"inputs":[{"name":"a","type":"uint256"}]},
+		{"type":"function","name":"foo","constant":false,"inputs":[{"name":"s","type":"string"}]}
+	]`
+	abi, err := JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sig := range []string{"foo(uint256)", "foo(string)"} {
+		// Run the lookup multiple times to catch map iteration order dependencies
+		for i := 0; i < 16; i++ {
+			method, err := abi.MethodById(crypto.Keccak256([]byte(sig))[:4])
+			if err != nil {
+				t.Fatalf("failed to look up %s: %v", sig, err)
+			}
+			if method.Sig() != sig {
+				t.Fatalf("selector of %s resolved to %s", sig, method.Sig())
+			}
+		}
+	}
+}
+
+func TestABI_DecodeCall(t *testing.T) {
+	abi, err := JSON(strings.NewReader(jsondata2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := abi.Pack("sliceAddress", []common.Address{{1}, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, args, err := abi.DecodeCall(input)
+	if err != nil {
+		t.Fatalf("failed to decode call: %v", err)
+	}
+	if name != "sliceAddress" {
+		t.Errorf("method mismatch: have %s, want sliceAddress", name)
+	}
+	if want := []common.Address{{1}, {2}}; !reflect.DeepEqual(args["inputs"], want) {
+		t.Errorf("argument mismatch: have %v, want %v", args["inputs"], want)
+	}
+	// Methods without inputs should decode from the bare selector
+	input, _ = abi.Pack("balance")
+	if name, args, err = abi.DecodeCall(input); err != nil || name != "balance" || len(args) != 0 {
+		t.Errorf("bare selector decode mismatch: have %s %v %v", name, args, err)
+	}
+	// Empty, truncated and unknown calldata should be rejected
+	for _, data := range [][]byte{nil, {0x01, 0x02}, {0xde, 0xad, 0xbe, 0xef}} {
+		if _, _, err := abi.DecodeCall(data); err == nil {
+			t.Errorf("decoded invalid calldata %x", data)
+		}
+	}
+}
+
+func TestABI_FormatCall(t *testing.T) {
+	const definition = `[
+		{ "type" : "function", "name" : "transfer", "inputs" : [ { "name" : "to", "type" : "address" }, { "name" : "value", "type" : "uint256" } ] },
+		{ "type" : "function", "name" : "misc", "inputs" : [ { "name" : "s", "type" : "string" }, { "name" : "b", "type" : "bytes" }, { "name" : "f", "type" : "bytes2" }, { "name" : "ok", "type" : "bool" }, { "name" : "n", "type" : "int8[]" } ] },
+		{ "type" : "function", "name" : "pair", "inputs" : [ { "name" : "p", "type" : "tuple", "components" : [ { "name" : "a", "type" : "uint64" }, { "name" : "b", "type" : "address" } ] } ] }
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+	value, _ := new(big.Int).SetString("1000000000000000000", 10)
+
+	transfer, _ := abi.Pack("transfer", addr, value)
+	misc, _ := abi.Pack("misc", "hi \"there\"", []byte{0xde, 0xad}, [2]byte{0xbe, 0xef}, true, []int8{-1, 2})
+	pair, _ := abi.Pack("pair", struct {
+		A uint64
+		B common.Address
+	}{7, addr})
+
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{transfer, "transfer(0x1234...5678, 1000000000000000000)"},
+		{misc, `misc("hi \"there\"", 0xdead, 0xbeef, true, [-1, 2])`},
+		{pair, "pair((7, 0x1234...5678))"},
+		{append(common.FromHex("0xdeadbeef"), common.LeftPadBytes([]byte{1}, 32)...), "0xdeadbeef(0x" + strings.Repeat("00", 31) + "01)"},
+		{common.FromHex("0xdeadbeef"), "0xdeadbeef()"},
+	}
+	for i, tt := range tests {
+		have, err := abi.FormatCall(tt.data)
+		if err != nil {
+			t.Errorf("test %d: failed to format call: %v", i, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("test %d: formatted call mismatch: have %s, want %s", i, have, tt.want)
+		}
+	}
+	// Truncated calldata and undecodable arguments should be rejected
+	for _, data := range [][]byte{nil, {0x01, 0x02}, transfer[:20]} {
+		if _, err := abi.FormatCall(data); err == nil {
+			t.Errorf("formatted invalid calldata %x", data)
+		}
+	}
+}
+
+func TestABI_ResolveCall(t *testing.T) {
+	const definition = `[
+		{ "type" : "constructor", "inputs" : [ { "name" : "owner", "type" : "address" } ] },
+		{ "type" : "function", "name" : "transfer", "inputs" : [ { "name" : "to", "type" : "address" }, { "name" : "value", "type" : "uint256" } ] }
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+	data, _ := abi.Pack("transfer", addr, big.NewInt(42))
+
+	method, args, err := abi.ResolveCall(&addr, data)
+	if err != nil {
+		t.Fatalf("failed to resolve call: %v", err)
+	}
+	if method.Name != "transfer" {
+		t.Errorf("method mismatch: have %s, want transfer", method.Name)
+	}
+	if want := []interface{}{addr, big.NewInt(42)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments mismatch: have %v, want %v", args, want)
+	}
+	// Empty calldata and creation code must be reported distinctly
+	if _, _, err := abi.ResolveCall(&addr, nil); err != ErrEmptyCallData {
+		t.Errorf("empty calldata error mismatch: have %v, want %v", err, ErrEmptyCallData)
+	}
+	// Creations are detected by the missing recipient, whatever the init code
+	for _, code := range []string{"0x608060405234801561001057600080fd5b50", "0x6060604052341561000f57600080fd5b", "0x00"} {
+		creation, _ := abi.PackConstructorWithCode(common.FromHex(code), addr)
+		if _, _, err := abi.ResolveCall(nil, creation); err != ErrConstructorCall {
+			t.Errorf("creation code %s error mismatch: have %v, want %v", code, err, ErrConstructorCall)
+		}
+	}
+	// Unknown selectors and truncated arguments must fail
+	for _, data := range [][]byte{{0x01, 0x02}, common.FromHex("0xdeadbeef"), data[:20]} {
+		if _, _, err := abi.ResolveCall(&addr, data); err == nil || err == ErrEmptyCallData || err == ErrConstructorCall {
+			t.Errorf("calldata %x: unexpected error %v", data, err)
+		}
+	}
+}
+
+func TestABI_Selectors(t *testing.T) {
+	abi, err := JSON(strings.NewReader(jsondata2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectors := abi.Selectors()
+	if len(selectors) != len(abi.Methods) {
+		t.Fatalf("selector count mismatch: have %d, want %d", len(selectors), len(abi.Methods))
+	}
+	for name, method := range abi.Methods {
+		var id [4]byte
+		copy(id[:], method.Id())
+		if selectors[id] != name {
+			t.Errorf("selector %x mismatch: have %s, want %s", id, selectors[id], name)
+		}
+	}
+	topics := abi.EventTopics()
+	if len(topics) != len(abi.Events) {
+		t.Fatalf("topic count mismatch: have %d, want %d", len(topics), len(abi.Events))
+	}
+	for name, event := range abi.Events {
+		if topics[event.Id()] != name {
+			t.Errorf("topic %x mismatch: have %s, want %s", event.Id(), topics[event.Id()], name)
+		}
+	}
+}
+
+func TestABI_ImplementsInterface(t *testing.T) {
+	const token = `[
+		{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+		{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+	]`
+	const iface = `[
+		{"type":"function","name":"transfer","inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+		{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+		{"type":"event","name":"Transfer","inputs":[{"name":"src","type":"address","indexed":true},{"name":"dst","type":"address","indexed":true},{"name":"wad","type":"uint256","indexed":false}]},
+		{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+	]`
+	tokenABI, err := JSON(strings.NewReader(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifaceABI, err := JSON(strings.NewReader(iface))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, missing := tokenABI.ImplementsInterface(ifaceABI)
+	if ok {
+		t.Errorf("interface reported as implemented")
+	}
+	want := []string{"event Approval(address,address,uint256)", "function approve(address,uint256)"}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing signatures mismatch: have %v, want %v", missing, want)
+	}
+	if ok, missing := ifaceABI.ImplementsInterface(ifaceABI); !ok || len(missing) != 0 {
+		t.Errorf("interface not implemented by itself: missing %v", missing)
+	}
+	if ok, missing := tokenABI.ImplementsInterface(ABI{}); !ok || len(missing) != 0 {
+		t.Errorf("empty interface not implemented: missing %v", missing)
+	}
+}
+
 func TestABI_EventById(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1030,3 +1287,22 @@ func TestDoubleDuplicateMethodNames(t *testing.T) {
 		t.Fatalf("Should not have found extra method")
 	}
 }
+
+// TestJSONStrictDuplicateMethodNames checks that the strict parser rejects overloaded
+// method names instead of renaming them.
+func TestJSONStrictDuplicateMethodNames(t *testing.T) {
+	const duplicated = `[{"type":"function","name":"send","inputs":[{"name":"a","type":"uint256"}]},{"type":"function","name":"send","inputs":[{"name":"a","type":"uint256"}]},{"type":"function","name":"get"},{"type":"function","name":"get"}]`
+	if _, err := JSONStrict(strings.NewReader(duplicated)); err == nil {
+		t.Fatal("expected duplicate method names to be rejected")
+	} else if want := "abi: duplicate method names: get, send"; err.Error() != want {
+		t.Fatalf("error mismatch: have %q, want %q", err, want)
+	}
+	const unique = `[{"type":"function","name":"send","inputs":[{"name":"a","type":"uint256"}]},{"type":"function","name":"send0"}]`
+	abi, err := JSONStrict(strings.NewReader(unique))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(abi.Methods) != 2 {
+		t.Fatalf("method count mismatch: have %d, want 2", len(abi.Methods))
+	}
+}
@@ -19,8 +19,12 @@ package abi
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
 )
 
 // Argument holds the name of the argument and the corresponding type.
@@ -33,6 +37,12 @@ type Argument struct {
 
 type Arguments []Argument
 
+// NamedValue is a single unpacked argument along with its declared name.
+type NamedValue struct {
+	Name  string
+	Value interface{}
+}
+
 type ArgumentMarshaling struct {
 	Name       string
 	Type       string
@@ -102,6 +112,63 @@ func (arguments Arguments) Unpack(v interface{}, data []byte) error {
 	return arguments.unpackAtomic(v, marshalledValues[0])
 }
 
+// UnpackReuse performs the same operation as Unpack, but decodes straight into
+// the memory already held by v instead of building fresh values, so that a tight
+// loop decoding many logs into the same destination produces little garbage.
+// Slices are truncated and refilled in place if their capacity suffices, non-nil
+// *big.Int values are overwritten and bytes are copied into the existing buffer.
+//
+// Since the destination is reused, every slice, byte slice and big integer held
+// by v is overwritten by the next call. Callers that need to retain a decoded
+// value past the next call must copy it first. Unlike Unpack, decoded bytes never
+// alias data, so the input may be reused as soon as the call returns.
+func (arguments Arguments) UnpackReuse(v interface{}, data []byte) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("abi: UnpackReuse(non-pointer %T)", v)
+	}
+	value = value.Elem()
+	nonIndexed := arguments.NonIndexed()
+	if len(nonIndexed) == 0 {
+		return nil
+	}
+	// Resolve the destination of every argument, mapping onto struct fields by name
+	dsts := make([]reflect.Value, len(nonIndexed))
+	switch {
+	case len(nonIndexed) == 1 && (value.Kind() != reflect.Struct || nonIndexed[0].Type.T == TupleTy):
+		dsts[0] = value
+	case value.Kind() == reflect.Struct:
+		argNames := make([]string, len(nonIndexed))
+		for i, arg := range nonIndexed {
+			argNames[i] = arg.Name
+		}
+		abi2struct, err := mapArgNamesToStructFields(argNames, value)
+		if err != nil {
+			return err
+		}
+		for i, arg := range nonIndexed {
+			field := value.FieldByName(abi2struct[arg.Name])
+			if !field.IsValid() {
+				return fmt.Errorf("abi: field %s can't be found in the given value", arg.Name)
+			}
+			dsts[i] = field
+		}
+	default:
+		return fmt.Errorf("abi: cannot unmarshal tuple into %v", value.Type())
+	}
+	virtualArgs := 0
+	for i, arg := range nonIndexed {
+		if err := unpackInto((i+virtualArgs)*32, arg.Type, data, dsts[i], nil); err != nil {
+			return err
+		}
+		// Static arrays and tuples are encoded inline, see unpackValues
+		if (arg.Type.T == ArrayTy || arg.Type.T == TupleTy) && !isDynamicType(arg.Type) {
+			virtualArgs += getTypeSize(arg.Type)/32 - 1
+		}
+	}
+	return nil
+}
+
 // UnpackIntoMap performs the operation hexdata -> mapping of argument name to argument value
 func (arguments Arguments) UnpackIntoMap(v map[string]interface{}, data []byte) error {
 	marshalledValues, err := arguments.UnpackValues(data)
@@ -112,6 +179,87 @@ func (arguments Arguments) UnpackIntoMap(v map[string]interface{}, data []byte)
 	return arguments.unpackIntoMap(v, marshalledValues)
 }
 
+// UnpackOrdered performs the operation hexdata -> list of argument name and value
+// pairs, preserving the order in which the arguments are declared. Indexed event
+// arguments are skipped, same as with UnpackIntoMap.
+func (arguments Arguments) UnpackOrdered(data []byte) ([]NamedValue, error) {
+	marshalledValues, err := arguments.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]NamedValue, 0, len(marshalledValues))
+	for i, arg := range arguments.NonIndexed() {
+		values = append(values, NamedValue{Name: arg.Name, Value: marshalledValues[i]})
+	}
+	return values, nil
+}
+
+// UnpackIntoMapHex performs the same operation as UnpackIntoMap, but encodes all
+// integers wider than 64 bits as 0x-prefixed hex strings, the same way the RPC
+// layer encodes big numbers. This keeps values beyond JSON's safe integer range
+// intact when the map is marshalled and forwarded to e.g. JavaScript consumers.
+// Arrays and tuples containing such integers are converted into []interface{}
+// and map[string]interface{} values respectively.
+func (arguments Arguments) UnpackIntoMapHex(v map[string]interface{}, data []byte) error {
+	if err := arguments.UnpackIntoMap(v, data); err != nil {
+		return err
+	}
+	for _, arg := range arguments.NonIndexed() {
+		v[arg.Name] = hexifyBigInts(arg.Type, reflect.ValueOf(v[arg.Name]))
+	}
+	return nil
+}
+
+// hexifyBigInts converts all big integers within the given unpacked value of
+// type t into hex strings, recursing into arrays and tuples.
+func hexifyBigInts(t Type, v reflect.Value) interface{} {
+	if !containsBigInt(t) {
+		return v.Interface()
+	}
+	switch t.T {
+	case IntTy, UintTy, FixedPointTy:
+		return hexutil.EncodeBig(v.Interface().(*big.Int))
+	case SliceTy, ArrayTy:
+		list := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list[i] = hexifyBigInts(*t.Elem, v.Index(i))
+		}
+		return list
+	case TupleTy:
+		v = indirect(v)
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			if name == "" {
+				name = fmt.Sprintf("%d", i)
+			}
+			fields[name] = hexifyBigInts(*elem, v.Field(i))
+		}
+		return fields
+	}
+	return v.Interface()
+}
+
+// containsBigInt reports whether values of type t are or contain integers which
+// are unpacked into *big.Int.
+func containsBigInt(t Type) bool {
+	switch t.T {
+	case IntTy, UintTy:
+		return t.Size > 64
+	case FixedPointTy:
+		return true
+	case SliceTy, ArrayTy:
+		return containsBigInt(*t.Elem)
+	case TupleTy:
+		for _, elem := range t.TupleElems {
+			if containsBigInt(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // unpack sets the unmarshalled value to go format.
 // Note the dst here must be settable.
 func unpack(t *Type, dst interface{}, src interface{}) error {
@@ -132,25 +280,25 @@ func unpack(t *Type, dst interface{}, src interface{}) error {
 		return set(dstVal, srcVal)
 	}
 
-	// Dereferences interface or pointer wrapper
+	// Dereferences interface or pointer wrapper, allocating nil struct pointers
+	// so slices of *T can be filled the same way as slices of T.
 	dstVal = indirectInterfaceOrPtr(dstVal)
+	if dstVal.Kind() == reflect.Ptr && dstVal.IsNil() && dstVal.CanSet() {
+		dstVal.Set(reflect.New(dstVal.Type().Elem()))
+		dstVal = dstVal.Elem()
+	}
 
 	switch t.T {
 	case TupleTy:
 		if dstVal.Kind() != reflect.Struct {
 			return fmt.Errorf("abi: invalid dst value for unpack, want struct, got %s", dstVal.Kind())
 		}
-		fieldmap, err := mapArgNamesToStructFields(t.TupleRawNames, dstVal)
+		fields, err := tupleFields(*t, dstVal)
 		if err != nil {
 			return err
 		}
 		for i, elem := range t.TupleElems {
-			fname := fieldmap[t.TupleRawNames[i]]
-			field := dstVal.FieldByName(fname)
-			if !field.IsValid() {
-				return fmt.Errorf("abi: field %s can't found in the given value", t.TupleRawNames[i])
-			}
-			if err := unpack(elem, field.Addr().Interface(), srcVal.Field(i).Interface()); err != nil {
+			if err := unpack(elem, fields[i].Addr().Interface(), srcVal.Field(i).Interface()); err != nil {
 				return err
 			}
 		}
@@ -275,6 +423,29 @@ func (arguments Arguments) unpackTuple(v interface{}, marshalledValues []interfa
 // without supplying a struct to unpack into. Instead, this method returns a list containing the
 // values. An atomic argument will be a list with one element.
 func (arguments Arguments) UnpackValues(data []byte) ([]interface{}, error) {
+	retval, _, err := arguments.unpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+	return retval, nil
+}
+
+// UnpackPartial decodes as many leading non-indexed arguments as the data allows.
+// If decoding stops early, the values decoded so far are returned along with a
+// *PartialDataError identifying the argument that could not be decoded.
+func (arguments Arguments) UnpackPartial(data []byte) ([]interface{}, error) {
+	retval, index, err := arguments.unpackValues(data)
+	if err != nil {
+		arg := arguments.NonIndexed()[index]
+		return retval, &PartialDataError{Index: index, Name: arg.Name, Type: arg.Type.String(), Length: len(data), Err: err}
+	}
+	return retval, nil
+}
+
+// unpackValues decodes the non-indexed arguments in order, stopping at the first
+// one that fails. It returns the values decoded so far and the index of the
+// failing argument.
+func (arguments Arguments) unpackValues(data []byte) ([]interface{}, int, error) {
 	retval := make([]interface{}, 0, arguments.LengthNonIndexed())
 	virtualArgs := 0
 	for index, arg := range arguments.NonIndexed() {
@@ -297,11 +468,11 @@ func (arguments Arguments) UnpackValues(data []byte) ([]interface{}, error) {
 			virtualArgs += getTypeSize(arg.Type)/32 - 1
 		}
 		if err != nil {
-			return nil, err
+			return retval, index, err
 		}
 		retval = append(retval, marshalledValue)
 	}
-	return retval, nil
+	return retval, 0, nil
 }
 
 // PackValues performs the operation Go format -> Hexdata
@@ -329,6 +500,10 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 	var ret []byte
 	for i, a := range args {
 		input := abiArgs[i]
+		a, err := decodeHexArg(input.Type, a)
+		if err != nil {
+			return nil, err
+		}
 		// pack the input
 		packed, err := input.Type.pack(reflect.ValueOf(a))
 		if err != nil {
@@ -353,6 +528,96 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 	return ret, nil
 }
 
+// ValidateArgs checks whether the given values are compatible with the arguments,
+// running the same type checks as Pack without encoding anything. The first
+// mismatch is returned along with the index and the expected type of the argument.
+func (arguments Arguments) ValidateArgs(args ...interface{}) error {
+	if len(args) != len(arguments) {
+		return fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+	for i, a := range args {
+		a, err := decodeHexArg(arguments[i].Type, a)
+		if err == nil {
+			err = arguments[i].Type.validate(reflect.ValueOf(a))
+		}
+		if err != nil {
+			return fmt.Errorf("abi: argument %d (%s): %v", i, arguments[i].Type, err)
+		}
+	}
+	return nil
+}
+
+// decodeHexArg converts a 0x prefixed hex string passed for a bytes, bytesN or
+// address argument into the native Go type Pack expects, checking the length of
+// the fixed size ones. Any other value is returned as is.
+func decodeHexArg(t Type, arg interface{}) (interface{}, error) {
+	str, ok := arg.(string)
+	if !ok || (t.T != BytesTy && t.T != FixedBytesTy && t.T != AddressTy) {
+		return arg, nil
+	}
+	data, err := hexutil.Decode(str)
+	if err != nil {
+		return nil, fmt.Errorf("abi: cannot use %q as type %v as argument: %v", str, t, err)
+	}
+	switch t.T {
+	case AddressTy:
+		if len(data) != common.AddressLength {
+			return nil, fmt.Errorf("abi: cannot use %d bytes as type %v as argument", len(data), t)
+		}
+		return common.BytesToAddress(data), nil
+	case FixedBytesTy:
+		if len(data) != t.Size {
+			return nil, fmt.Errorf("abi: cannot use %d bytes as type %v as argument", len(data), t)
+		}
+		fixed := reflect.New(t.Type).Elem()
+		reflect.Copy(fixed, reflect.ValueOf(data))
+		return fixed.Interface(), nil
+	}
+	return data, nil
+}
+
+// EncodedLen returns the exact length of the encoding Pack would produce for the
+// given values, including the offsets of dynamic types, without encoding them.
+func (arguments Arguments) EncodedLen(args ...interface{}) (int, error) {
+	if len(args) != len(arguments) {
+		return 0, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+	size := 0
+	for i, a := range args {
+		a, err := decodeHexArg(arguments[i].Type, a)
+		if err != nil {
+			return 0, err
+		}
+		n, err := arguments[i].Type.encodedLen(reflect.ValueOf(a))
+		if err != nil {
+			return 0, err
+		}
+		if isDynamicType(arguments[i].Type) {
+			size += 32
+		}
+		size += n
+	}
+	return size, nil
+}
+
+// PackPacked performs the operation Go format -> Hexdata using the non-standard
+// packed mode of Solidity's abi.encodePacked: values are concatenated without
+// offsets or length prefixes and elementary types use their minimal width.
+func (arguments Arguments) PackPacked(args ...interface{}) ([]byte, error) {
+	if len(args) != len(arguments) {
+		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+	var ret []byte
+	for i, a := range args {
+		packed, err := arguments[i].Type.packPacked(reflect.ValueOf(a), false)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, packed...)
+	}
+	return ret, nil
+}
+
 // ToCamelCase converts an under-score string to a camel-case string
 func ToCamelCase(input string) string {
 	parts := strings.Split(input, "_")
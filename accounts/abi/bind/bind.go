@@ -375,7 +375,7 @@ func bindStructTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
 		var fields []*tmplField
 		for i, elem := range kind.TupleElems {
 			field := bindStructTypeGo(*elem, structs)
-			fields = append(fields, &tmplField{Type: field, Name: capitalise(kind.TupleRawNames[i]), SolKind: *elem})
+			fields = append(fields, &tmplField{Type: field, Name: kind.Type.Field(i).Name, SolKind: *elem})
 		}
 		name := fmt.Sprintf("Struct%d", len(structs))
 		structs[kind.String()] = &tmplStruct{
@@ -404,7 +404,7 @@ func bindStructTypeJava(kind abi.Type, structs map[string]*tmplStruct) string {
 		var fields []*tmplField
 		for i, elem := range kind.TupleElems {
 			field := bindStructTypeJava(*elem, structs)
-			fields = append(fields, &tmplField{Type: field, Name: decapitalise(kind.TupleRawNames[i]), SolKind: *elem})
+			fields = append(fields, &tmplField{Type: field, Name: decapitalise(kind.Type.Field(i).Name), SolKind: *elem})
 		}
 		name := fmt.Sprintf("Class%d", len(structs))
 		structs[kind.String()] = &tmplStruct{
@@ -0,0 +1,150 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// canonicalEntry is a single constructor, function or event of a canonical ABI.
+// The field order of the struct defines the key order of the emitted JSON.
+type canonicalEntry struct {
+	Type      string              `json:"type"`
+	Name      string              `json:"name,omitempty"`
+	Constant  bool                `json:"constant,omitempty"`
+	Payable   bool                `json:"payable,omitempty"`
+	Anonymous bool                `json:"anonymous,omitempty"`
+	Inputs    []canonicalArgument `json:"inputs"`
+	Outputs   []canonicalArgument `json:"outputs,omitempty"`
+}
+
+// canonicalArgument is a single argument or tuple component of a canonical ABI.
+type canonicalArgument struct {
+	Name       string              `json:"name"`
+	Type       string              `json:"type"`
+	Indexed    bool                `json:"indexed,omitempty"`
+	Components []canonicalArgument `json:"components,omitempty"`
+}
+
+// Canonical returns a normalized JSON representation of the ABI, such that any
+// two semantically identical ABIs produce the exact same bytes regardless of
+// the formatting and declaration order of their source definitions.
+//
+// The constructor comes first (if it takes inputs or accepts value), followed
+// by the methods sorted by selector and the events sorted by topic. Argument
+// order is retained as it is part of the encoding. Types are emitted in their
+// canonical form, with tuples expanded into their components.
+func (abi ABI) Canonical() ([]byte, error) {
+	var entries []canonicalEntry
+	if len(abi.Constructor.Inputs) > 0 || abi.Constructor.Payable {
+		entries = append(entries, canonicalEntry{
+			Type:    "constructor",
+			Payable: abi.Constructor.Payable,
+			Inputs:  canonicalArguments(abi.Constructor.Inputs),
+		})
+	}
+	methods := make([]Method, 0, len(abi.Methods))
+	for _, method := range abi.Methods {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if c := bytes.Compare(methods[i].Id(), methods[j].Id()); c != 0 {
+			return c < 0
+		}
+		return methods[i].Name < methods[j].Name
+	})
+	for _, method := range methods {
+		name := method.RawName
+		if name == "" {
+			name = method.Name
+		}
+		entries = append(entries, canonicalEntry{
+			Type:     "function",
+			Name:     name,
+			Constant: method.Const,
+			Payable:  method.Payable,
+			Inputs:   canonicalArguments(method.Inputs),
+			Outputs:  canonicalArguments(method.Outputs),
+		})
+	}
+	events := make([]Event, 0, len(abi.Events))
+	for _, event := range abi.Events {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if c := bytes.Compare(events[i].Id().Bytes(), events[j].Id().Bytes()); c != 0 {
+			return c < 0
+		}
+		return events[i].String() < events[j].String()
+	})
+	for _, event := range events {
+		entries = append(entries, canonicalEntry{
+			Type:      "event",
+			Name:      event.Name,
+			Anonymous: event.Anonymous,
+			Inputs:    canonicalArguments(event.Inputs),
+		})
+	}
+	if entries == nil {
+		entries = []canonicalEntry{}
+	}
+	return json.Marshal(entries)
+}
+
+// canonicalArguments converts a list of arguments into their canonical form.
+func canonicalArguments(args Arguments) []canonicalArgument {
+	out := make([]canonicalArgument, len(args))
+	for i, arg := range args {
+		typ, components := canonicalType(arg.Type)
+		out[i] = canonicalArgument{
+			Name:       arg.Name,
+			Type:       typ,
+			Indexed:    arg.Indexed,
+			Components: components,
+		}
+	}
+	return out
+}
+
+// canonicalType returns the canonical type string of t along with the components
+// of its tuple, if t is a tuple or an array or slice of tuples.
+func canonicalType(t Type) (string, []canonicalArgument) {
+	switch t.T {
+	case SliceTy:
+		typ, components := canonicalType(*t.Elem)
+		return typ + "[]", components
+	case ArrayTy:
+		typ, components := canonicalType(*t.Elem)
+		return fmt.Sprintf("%s[%d]", typ, t.Size), components
+	case TupleTy:
+		components := make([]canonicalArgument, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			typ, nested := canonicalType(*elem)
+			components[i] = canonicalArgument{
+				Name:       t.TupleRawNames[i],
+				Type:       typ,
+				Components: nested,
+			}
+		}
+		return "tuple", components
+	default:
+		return t.String(), nil
+	}
+}
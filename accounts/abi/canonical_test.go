@@ -0,0 +1,83 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	const first = `[
+		{ "type" : "constructor", "payable" : true, "inputs" : [{ "name" : "owner", "type" : "address" }] },
+		{ "type" : "function", "name" : "send", "inputs" : [{ "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "balance", "constant" : true, "inputs" : [], "outputs" : [{ "name" : "", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "batch", "inputs" : [{ "name" : "items", "type" : "tuple[]", "components" : [{ "name" : "to", "type" : "address" }, { "name" : "value", "type" : "uint256" }] }] },
+		{ "type" : "event", "name" : "Sent", "inputs" : [{ "name" : "to", "type" : "address", "indexed" : true }, { "name" : "amount", "type" : "uint256" }] }
+	]`
+	const second = `[
+		{"type":"event","name":"Sent","inputs":[{"name":"to","type":"address","indexed":true},{"name":"amount","type":"uint256"}]},
+		{"type":"function","name":"batch","inputs":[{"name":"items","type":"tuple[]","components":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}]},
+		{"type":"function","name":"balance","stateMutability":"view","outputs":[{"name":"","type":"uint256"}]},
+		{"name":"send","inputs":[{"name":"amount","type":"uint256"}]},
+		{"type":"constructor","stateMutability":"payable","inputs":[{"name":"owner","type":"address"}]}
+	]`
+	abi1, err := JSON(strings.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	abi2, err := JSON(strings.NewReader(second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canon1, err := abi1.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	canon2, err := abi2.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(canon1, canon2) {
+		t.Fatalf("canonical forms differ:\n%s\n%s", canon1, canon2)
+	}
+	// The canonical form must itself parse back into an equivalent ABI
+	abi3, err := JSON(bytes.NewReader(canon1))
+	if err != nil {
+		t.Fatalf("failed to parse canonical form: %v", err)
+	}
+	canon3, err := abi3.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(canon1, canon3) {
+		t.Fatalf("canonical form not stable across round trip:\n%s\n%s", canon1, canon3)
+	}
+	// Any semantic change must alter the canonical form
+	changed, err := JSON(strings.NewReader(strings.Replace(first, `"name" : "amount"`, `"name" : "value"`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canon4, err := changed.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(canon1, canon4) {
+		t.Fatalf("renamed argument yields identical canonical form")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"github.com/ccmchain/go-ccmchain/common"
+)
+
+// ABIDiff lists the differences between two versions of a contract ABI. Methods
+// are keyed by their 4 byte selector and events by their topic hash. A method or
+// event whose name exists in both versions but whose signature differs is listed
+// as changed instead of as removed and added. Changed methods are keyed by their
+// old signature, so overloads don't collide, and changed events by their name.
+type ABIDiff struct {
+	AddedMethods   map[[4]byte]Method
+	RemovedMethods map[[4]byte]Method
+	ChangedMethods map[string]MethodChange
+
+	AddedEvents   map[common.Hash]Event
+	RemovedEvents map[common.Hash]Event
+	ChangedEvents map[string]EventChange
+}
+
+// MethodChange is a method whose declared name was retained across two ABI
+// versions, but whose signature (and thus selector) changed.
+type MethodChange struct {
+	Old Method
+	New Method
+}
+
+// EventChange is an event whose name was retained across two ABI versions, but
+// whose signature (and thus topic hash) changed.
+type EventChange struct {
+	Old Event
+	New Event
+}
+
+// Compatible reports whether callers of the old ABI can keep using the new one,
+// i.e. nothing was removed and no signature was changed.
+func (d ABIDiff) Compatible() bool {
+	return len(d.RemovedMethods) == 0 && len(d.ChangedMethods) == 0 &&
+		len(d.RemovedEvents) == 0 && len(d.ChangedEvents) == 0
+}
+
+// DiffABI compares two versions of a contract ABI, reporting the methods and
+// events that were added, removed or had their signatures changed.
+func DiffABI(old, new ABI) ABIDiff {
+	diff := ABIDiff{
+		AddedMethods:   make(map[[4]byte]Method),
+		RemovedMethods: make(map[[4]byte]Method),
+		ChangedMethods: make(map[string]MethodChange),
+		AddedEvents:    make(map[common.Hash]Event),
+		RemovedEvents:  make(map[common.Hash]Event),
+		ChangedEvents:  make(map[string]EventChange),
+	}
+	// Collect the methods present in only one of the versions
+	oldMethods, newMethods := methodsBySelector(old), methodsBySelector(new)
+	for id, method := range oldMethods {
+		if _, ok := newMethods[id]; !ok {
+			diff.RemovedMethods[id] = method
+		}
+	}
+	for id, method := range newMethods {
+		if _, ok := oldMethods[id]; !ok {
+			diff.AddedMethods[id] = method
+		}
+	}
+	// Pair up removed and added methods with the same declared name as changed
+	// ones. Overloads can't be told apart once their selectors change, so they are
+	// only paired if a single one was removed and a single one added.
+	removed, added := methodsByRawName(diff.RemovedMethods), methodsByRawName(diff.AddedMethods)
+	for name, oldIDs := range removed {
+		newIDs := added[name]
+		if len(oldIDs) != 1 || len(newIDs) != 1 {
+			continue
+		}
+		oldMethod, newMethod := diff.RemovedMethods[oldIDs[0]], diff.AddedMethods[newIDs[0]]
+		diff.ChangedMethods[oldMethod.Sig()] = MethodChange{Old: oldMethod, New: newMethod}
+		delete(diff.RemovedMethods, oldIDs[0])
+		delete(diff.AddedMethods, newIDs[0])
+	}
+	// Do the same for the events
+	oldEvents, newEvents := eventsByTopic(old), eventsByTopic(new)
+	for id, event := range oldEvents {
+		if _, ok := newEvents[id]; !ok {
+			diff.RemovedEvents[id] = event
+		}
+	}
+	for id, event := range newEvents {
+		if _, ok := oldEvents[id]; !ok {
+			diff.AddedEvents[id] = event
+		}
+	}
+	for oldID, oldEvent := range diff.RemovedEvents {
+		for newID, newEvent := range diff.AddedEvents {
+			if oldEvent.Name == newEvent.Name {
+				diff.ChangedEvents[oldEvent.Name] = EventChange{Old: oldEvent, New: newEvent}
+				delete(diff.RemovedEvents, oldID)
+				delete(diff.AddedEvents, newID)
+				break
+			}
+		}
+	}
+	return diff
+}
+
+// methodsBySelector indexes the methods of an ABI by their 4 byte selector.
+func methodsBySelector(abi ABI) map[[4]byte]Method {
+	methods := make(map[[4]byte]Method, len(abi.Methods))
+	for _, method := range abi.Methods {
+		var id [4]byte
+		copy(id[:], method.Id())
+		methods[id] = method
+	}
+	return methods
+}
+
+// methodsByRawName groups the selectors of a set of methods by the name declared
+// in the contract, collecting overloads under the same name.
+func methodsByRawName(methods map[[4]byte]Method) map[string][][4]byte {
+	names := make(map[string][][4]byte)
+	for id, method := range methods {
+		name := method.RawName
+		if name == "" {
+			name = method.Name
+		}
+		names[name] = append(names[name], id)
+	}
+	return names
+}
+
+// eventsByTopic indexes the events of an ABI by their topic hash.
+func eventsByTopic(abi ABI) map[common.Hash]Event {
+	events := make(map[common.Hash]Event, len(abi.Events))
+	for _, event := range abi.Events {
+		events[event.Id()] = event
+	}
+	return events
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffABI(t *testing.T) {
+	const oldDef = `[
+		{ "type" : "function", "name" : "balance", "constant" : true, "inputs" : [] },
+		{ "type" : "function", "name" : "send", "inputs" : [{ "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "burn", "inputs" : [] },
+		{ "type" : "event", "name" : "Sent", "inputs" : [{ "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "event", "name" : "Burnt", "inputs" : [] }
+	]`
+	const newDef = `[
+		{ "type" : "function", "name" : "balance", "constant" : true, "inputs" : [] },
+		{ "type" : "function", "name" : "send", "inputs" : [{ "name" : "to", "type" : "address" }, { "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "mint", "inputs" : [] },
+		{ "type" : "event", "name" : "Sent", "inputs" : [{ "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "event", "name" : "Minted", "inputs" : [] }
+	]`
+	oldABI, err := JSON(strings.NewReader(oldDef))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newABI, err := JSON(strings.NewReader(newDef))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffABI(oldABI, newABI)
+	if diff.Compatible() {
+		t.Errorf("incompatible ABIs reported compatible")
+	}
+	if len(diff.AddedMethods) != 1 || len(diff.RemovedMethods) != 1 || len(diff.ChangedMethods) != 1 {
+		t.Fatalf("method diff mismatch: added %d, removed %d, changed %d", len(diff.AddedMethods), len(diff.RemovedMethods), len(diff.ChangedMethods))
+	}
+	for _, method := range diff.AddedMethods {
+		if method.Name != "mint" {
+			t.Errorf("added method mismatch: have %s, want mint", method.Name)
+		}
+	}
+	for _, method := range diff.RemovedMethods {
+		if method.Name != "burn" {
+			t.Errorf("removed method mismatch: have %s, want burn", method.Name)
+		}
+	}
+	if change, ok := diff.ChangedMethods["send(uint256)"]; !ok {
+		t.Errorf("changed method send missing")
+	} else if change.Old.Sig() != "send(uint256)" || change.New.Sig() != "send(address,uint256)" {
+		t.Errorf("changed method signature mismatch: have %s -> %s", change.Old.Sig(), change.New.Sig())
+	}
+	if len(diff.AddedEvents) != 1 || len(diff.RemovedEvents) != 1 || len(diff.ChangedEvents) != 0 {
+		t.Fatalf("event diff mismatch: added %d, removed %d, changed %d", len(diff.AddedEvents), len(diff.RemovedEvents), len(diff.ChangedEvents))
+	}
+	if _, ok := diff.AddedEvents[newABI.Events["Minted"].Id()]; !ok {
+		t.Errorf("added event Minted missing")
+	}
+	if _, ok := diff.RemovedEvents[oldABI.Events["Burnt"].Id()]; !ok {
+		t.Errorf("removed event Burnt missing")
+	}
+	// Diffing an ABI against itself should yield no changes
+	if diff := DiffABI(oldABI, oldABI); !diff.Compatible() || len(diff.AddedMethods) != 0 || len(diff.AddedEvents) != 0 {
+		t.Errorf("identical ABIs reported different: %+v", diff)
+	}
+}
+
+// Tests that overloaded methods are paired by their declared name and signature,
+// rather than by the suffixed Go name that depends on the overload order.
+func TestDiffABIOverloads(t *testing.T) {
+	const oldDef = `[
+		{ "type" : "function", "name" : "transfer", "inputs" : [{ "name" : "to", "type" : "address" }, { "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "transfer", "inputs" : [{ "name" : "to", "type" : "address" }, { "name" : "amount", "type" : "uint256" }, { "name" : "data", "type" : "bytes" }] },
+		{ "type" : "function", "name" : "approve", "inputs" : [{ "name" : "amount", "type" : "uint256" }] },
+		{ "type" : "function", "name" : "approve", "inputs" : [{ "name" : "spender", "type" : "address" }] }
+	]`
+	const newDef = `[
+		{ "type" : "function", "name" : "transfer", "inputs" : [{ "name" : "to", "type" : "address" }, { "name" : "amount", "type" : "uint256" }, { "name" : "data", "type" : "bytes" }] },
+		{ "type" : "function", "name" : "transfer", "inputs" : [{ "name" : "to", "type" : "address" }, { "name" : "amount", "type" : "uint128" }] },
+		{ "type" : "function", "name" : "approve", "inputs" : [{ "name" : "amount", "type" : "uint128" }] },
+		{ "type" : "function", "name" : "approve", "inputs" : [{ "name" : "spender", "type" : "bytes32" }] }
+	]`
+	oldABI, err := JSON(strings.NewReader(oldDef))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newABI, err := JSON(strings.NewReader(newDef))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffABI(oldABI, newABI)
+
+	// The single changed transfer overload is paired, the unchanged one is ignored
+	if change, ok := diff.ChangedMethods["transfer(address,uint256)"]; !ok {
+		t.Errorf("changed overload transfer(address,uint256) missing")
+	} else if change.New.Sig() != "transfer(address,uint128)" {
+		t.Errorf("changed overload mismatch: have %s, want transfer(address,uint128)", change.New.Sig())
+	}
+	// Both approve overloads changed, which is ambiguous and can't be paired
+	if len(diff.ChangedMethods) != 1 {
+		t.Errorf("changed method count mismatch: have %d, want 1", len(diff.ChangedMethods))
+	}
+	if len(diff.RemovedMethods) != 2 || len(diff.AddedMethods) != 2 {
+		t.Fatalf("method diff mismatch: added %d, removed %d", len(diff.AddedMethods), len(diff.RemovedMethods))
+	}
+	for _, method := range diff.RemovedMethods {
+		if method.RawName != "approve" {
+			t.Errorf("removed method mismatch: have %s, want approve overload", method.Sig())
+		}
+	}
+}
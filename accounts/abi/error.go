@@ -24,8 +24,31 @@ import (
 
 var (
 	errBadBool = errors.New("abi: improperly encoded boolean value")
+
+	// ErrEmptyCallData is returned by ResolveCall if the call data is empty, as is
+	// the case for plain value transfers and calls of the fallback function.
+	ErrEmptyCallData = errors.New("abi: empty call data")
+
+	// ErrConstructorCall is returned by ResolveCall if the call has no recipient,
+	// i.e. its data is contract creation code. Constructors have no selector, their arguments are appended
+	// to the init code and cannot be located without knowing its length.
+	ErrConstructorCall = errors.New("abi: call data is contract creation code")
 )
 
+// PartialDataError is returned by UnpackPartial if the data ran out (or was
+// otherwise malformed) before all arguments could be decoded.
+type PartialDataError struct {
+	Index  int    // Position of the first argument that could not be decoded
+	Name   string // Name of the failing argument
+	Type   string // ABI type of the failing argument
+	Length int    // Length of the decoded data
+	Err    error  // Underlying decoding error
+}
+
+func (e *PartialDataError) Error() string {
+	return fmt.Sprintf("abi: argument %d (%s %s) could not be decoded from %d bytes: %v", e.Index, e.Type, e.Name, e.Length, e.Err)
+}
+
 // formatSliceString formats the reflection kind with the given slice size
 // and returns a formatted string representation.
 func formatSliceString(kind reflect.Kind, sliceSize int) string {
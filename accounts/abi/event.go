@@ -18,6 +18,7 @@ package abi
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/ccmchain/go-ccmchain/common"
@@ -47,11 +48,34 @@ func (e Event) String() string {
 // Id returns the canonical representation of the event's signature used by the
 // abi definition to identify event names and types.
 func (e Event) Id() common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte(e.sig())))
+}
+
+// sig returns the event signature the topic hash is derived from, e.g.
+// Transfer(address,address,uint256).
+func (e Event) sig() string {
 	types := make([]string, len(e.Inputs))
-	i := 0
-	for _, input := range e.Inputs {
+	for i, input := range e.Inputs {
 		types[i] = input.Type.String()
-		i++
 	}
-	return common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ",")))))
+	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
+}
+
+// makeTopic encodes a value of an indexed event argument of the given type into
+// a log topic. Value types are encoded in place, whereas strings, byte slices
+// and arrays are replaced by the Keccak256 hash of their packed encoding.
+func makeTopic(t Type, v interface{}) (common.Hash, error) {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy, ArrayTy, TupleTy:
+		packed, err := t.packPacked(reflect.ValueOf(v), false)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return crypto.Keccak256Hash(packed), nil
+	}
+	packed, err := t.pack(reflect.ValueOf(v))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(packed), nil
 }
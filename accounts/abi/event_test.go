@@ -405,6 +405,43 @@ func TestEventUnpackIndexed(t *testing.T) {
 	require.Equal(t, uint8(8), rst.Value2)
 }
 
+func TestEventFilter(t *testing.T) {
+	definition := `[
+	{"name": "Transfer", "type": "event", "inputs": [{"indexed": true, "name": "from", "type": "address"}, {"indexed": true, "name": "to", "type": "address"}, {"indexed": false, "name": "value", "type": "uint256"}]},
+	{"name": "Note", "type": "event", "anonymous": true, "inputs": [{"indexed": true, "name": "tag", "type": "string"}, {"indexed": true, "name": "delta", "type": "int8"}]}
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	require.NoError(t, err)
+
+	var (
+		alice = common.HexToAddress("0x0102030405060708091011121314151617181920")
+		bob   = common.HexToAddress("0x2122232425262728293031323334353637383940")
+	)
+	topics, err := abi.EventFilter("Transfer", nil, []interface{}{alice, bob})
+	require.NoError(t, err)
+	require.Equal(t, [][]common.Hash{
+		{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))},
+		nil,
+		{common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())},
+	}, topics)
+
+	// Anonymous events have no signature topic, dynamic values are hashed and
+	// negative numbers are sign extended
+	topics, err = abi.EventFilter("Note", []interface{}{"hello"}, []interface{}{int8(-1)})
+	require.NoError(t, err)
+	require.Equal(t, [][]common.Hash{
+		{crypto.Keccak256Hash([]byte("hello"))},
+		{common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")},
+	}, topics)
+
+	_, err = abi.EventFilter("Transfer", nil, nil, []interface{}{big.NewInt(1)})
+	require.Error(t, err, "too many indexed filters")
+	_, err = abi.EventFilter("Transfer", []interface{}{"alice"})
+	require.Error(t, err, "mistyped filter value")
+	_, err = abi.EventFilter("Approval")
+	require.Error(t, err, "unknown event")
+}
+
 // TestEventIndexedWithArrayUnpack verifies that decoder will not overlow when static array is indexed input.
 func TestEventIndexedWithArrayUnpack(t *testing.T) {
 	definition := `[{"name": "test", "type": "event", "inputs": [{"indexed": true, "name":"value1", "type":"uint8[2]"},{"indexed": false, "name":"value2", "type":"string"}]}]`
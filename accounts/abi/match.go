@@ -0,0 +1,82 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/core/types"
+)
+
+// errNoEventSignature is returned if a log without topics is matched against a
+// set of ABIs. Such logs are emitted by anonymous events, which can't be
+// identified by their signature.
+var errNoEventSignature = errors.New("log has no event signature topic")
+
+// eventMatch is a single ABI event indexed by its topic hash.
+type eventMatch struct {
+	abi   *ABI
+	event *Event
+}
+
+// EventMatcher identifies the contract ABI and event that emitted a log out of a
+// set of candidate ABIs. The event ids of all candidates are hashed only once,
+// on construction, so a matcher should be reused when decoding many logs.
+type EventMatcher struct {
+	events map[common.Hash]eventMatch
+}
+
+// NewEventMatcher creates an event matcher over the given candidate ABIs. If the
+// same event signature is declared by multiple ABIs, the first one wins.
+func NewEventMatcher(abis []ABI) *EventMatcher {
+	m := &EventMatcher{events: make(map[common.Hash]eventMatch)}
+	for i := range abis {
+		abi := &abis[i]
+		for name := range abi.Events {
+			event := abi.Events[name]
+			if event.Anonymous {
+				continue
+			}
+			if _, exist := m.events[event.Id()]; !exist {
+				m.events[event.Id()] = eventMatch{abi: abi, event: &event}
+			}
+		}
+	}
+	return m
+}
+
+// Match returns the ABI and event whose signature matches the first topic of
+// the given log.
+func (m *EventMatcher) Match(log types.Log) (*ABI, *Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil, errNoEventSignature
+	}
+	match, ok := m.events[log.Topics[0]]
+	if !ok {
+		return nil, nil, fmt.Errorf("no event with id: %#x", log.Topics[0])
+	}
+	return match.abi, match.event, nil
+}
+
+// MatchEvent returns the ABI and event out of the given candidates whose
+// signature matches the first topic of the given log. It is a shorthand for
+// creating a one-off EventMatcher, use that directly when matching many logs.
+func MatchEvent(abis []ABI, log types.Log) (*ABI, *Event, error) {
+	return NewEventMatcher(abis).Match(log)
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/crypto"
+)
+
+func TestMatchEvent(t *testing.T) {
+	const tokenDef = `[
+		{ "type" : "event", "name" : "Transfer", "inputs" : [{ "name" : "from", "type" : "address", "indexed" : true }, { "name" : "to", "type" : "address", "indexed" : true }, { "name" : "value", "type" : "uint256" }] }
+	]`
+	const registryDef = `[
+		{ "type" : "event", "name" : "Registered", "inputs" : [{ "name" : "name", "type" : "string" }] },
+		{ "type" : "event", "name" : "Transfer", "inputs" : [{ "name" : "from", "type" : "address", "indexed" : true }, { "name" : "to", "type" : "address", "indexed" : true }, { "name" : "value", "type" : "uint256" }] }
+	]`
+	var abis []ABI
+	for _, def := range []string{tokenDef, registryDef} {
+		abi, err := JSON(strings.NewReader(def))
+		if err != nil {
+			t.Fatal(err)
+		}
+		abis = append(abis, abi)
+	}
+	// Events declared by a single ABI should resolve to that ABI
+	abi, event, err := MatchEvent(abis, types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Registered(string)"))}})
+	if err != nil {
+		t.Fatalf("failed to match event: %v", err)
+	}
+	if event.Name != "Registered" || abi != &abis[1] {
+		t.Errorf("matched wrong event: have %s in abi %p, want Registered in %p", event.Name, abi, &abis[1])
+	}
+	// Events declared by multiple ABIs should resolve to the first candidate
+	abi, event, err = MatchEvent(abis, types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))}})
+	if err != nil {
+		t.Fatalf("failed to match event: %v", err)
+	}
+	if event.Name != "Transfer" || abi != &abis[0] {
+		t.Errorf("matched wrong event: have %s in abi %p, want Transfer in %p", event.Name, abi, &abis[0])
+	}
+	// Unknown and anonymous logs should be rejected
+	if _, _, err := MatchEvent(abis, types.Log{Topics: []common.Hash{{0x01}}}); err == nil {
+		t.Errorf("matched unknown event")
+	}
+	if _, _, err := MatchEvent(abis, types.Log{}); err == nil {
+		t.Errorf("matched log without topics")
+	}
+}
@@ -31,11 +31,18 @@ import (
 // network. A method such as `Transact` does require a Tx and thus will
 // be flagged `false`.
 // Input specifies the required input parameters for this gives method.
+// Payable reports whether the method (or constructor) accepts value transfers.
+//
+// Overloaded methods are stored under a suffixed Name (e.g. `foo0`) to keep them
+// unique, RawName retains the name as declared in the contract, which is the one
+// used for the method signature. If RawName is empty, Name is used instead.
 type Method struct {
 	Name    string
 	Const   bool
 	Inputs  Arguments
 	Outputs Arguments
+	Payable bool
+	RawName string
 }
 
 // Sig returns the methods string signature according to the ABI spec.
@@ -50,7 +57,11 @@ func (method Method) Sig() string {
 	for i, input := range method.Inputs {
 		types[i] = input.Type.String()
 	}
-	return fmt.Sprintf("%v(%v)", method.Name, strings.Join(types, ","))
+	name := method.RawName
+	if name == "" {
+		name = method.Name
+	}
+	return fmt.Sprintf("%v(%v)", name, strings.Join(types, ","))
 }
 
 func (method Method) String() string {
@@ -75,3 +86,22 @@ func (method Method) String() string {
 func (method Method) Id() []byte {
 	return crypto.Keccak256([]byte(method.Sig()))[:4]
 }
+
+// ValidateArgs checks whether the given values are compatible with the inputs of
+// the method without packing them. See Arguments.ValidateArgs for details.
+func (method Method) ValidateArgs(args ...interface{}) error {
+	return method.Inputs.ValidateArgs(args...)
+}
+
+// DynamicInputs returns the indices of the inputs whose types are dynamically
+// sized (strings, bytes, dynamic arrays and any array or tuple containing them),
+// i.e. the inputs that are encoded out of place behind an offset.
+func (method Method) DynamicInputs() []int {
+	var indices []int
+	for i, input := range method.Inputs {
+		if isDynamicType(input.Type) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
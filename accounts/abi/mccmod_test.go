@@ -17,8 +17,12 @@
 package abi
 
 import (
+	"math/big"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/ccmchain/go-ccmchain/common"
 )
 
 const methoddata = `
@@ -126,3 +130,58 @@ func TestMethodSig(t *testing.T) {
 		}
 	}
 }
+
+func TestMethodValidateArgs(t *testing.T) {
+	abi, err := JSON(strings.NewReader(methoddata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type point struct {
+		X *big.Int
+		Y *big.Int
+	}
+	tests := []struct {
+		method string
+		args   []interface{}
+		fail   bool
+	}{
+		{"send", []interface{}{big.NewInt(1)}, false},
+		{"send", []interface{}{uint64(1)}, true},
+		{"send", nil, true},
+		{"transfer", []interface{}{common.Address{1}, common.Address{2}, big.NewInt(3)}, false},
+		{"transfer", []interface{}{common.Address{1}, "0x02", big.NewInt(3)}, true},
+		{"tupleSlice", []interface{}{[]point{{big.NewInt(1), big.NewInt(2)}}}, false},
+		{"tupleSlice", []interface{}{[]struct{ X, Y uint8 }{{1, 2}}}, true},
+	}
+	for i, tt := range tests {
+		err := abi.Methods[tt.method].ValidateArgs(tt.args...)
+		if tt.fail != (err != nil) {
+			t.Errorf("test %d: validation mismatch, want failure %t, have %v", i, tt.fail, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		// Values accepted by validation must pack successfully too
+		if _, err := abi.Methods[tt.method].Inputs.Pack(tt.args...); err != nil {
+			t.Errorf("test %d: validated arguments failed to pack: %v", i, err)
+		}
+	}
+}
+
+func TestMethodDynamicInputs(t *testing.T) {
+	const definition = `[
+		{"type":"function","name":"static","inputs":[{"name":"a","type":"uint256"},{"name":"b","type":"address[3]"},{"name":"c","type":"tuple","components":[{"name":"x","type":"uint256"}]}]},
+		{"type":"function","name":"dynamic","inputs":[{"name":"a","type":"string"},{"name":"b","type":"uint8"},{"name":"c","type":"bytes"},{"name":"d","type":"uint256[]"},{"name":"e","type":"string[2]"},{"name":"f","type":"tuple","components":[{"name":"x","type":"uint256"},{"name":"y","type":"bytes"}]},{"name":"g","type":"bytes32"}]}
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := abi.Methods["static"].DynamicInputs(); len(have) != 0 {
+		t.Errorf("static method has dynamic inputs: %v", have)
+	}
+	if have, want := abi.Methods["dynamic"].DynamicInputs(), []int{0, 2, 3, 4, 5}; !reflect.DeepEqual(have, want) {
+		t.Errorf("dynamic inputs mismatch: have %v, want %v", have, want)
+	}
+}
@@ -35,7 +35,7 @@ func packBytesSlice(bytes []byte, l int) []byte {
 // t.
 func packElement(t Type, reflectValue reflect.Value) []byte {
 	switch t.T {
-	case IntTy, UintTy:
+	case IntTy, UintTy, FixedPointTy:
 		return packNum(reflectValue)
 	case StringTy:
 		return packBytesSlice([]byte(reflectValue.String()), reflectValue.Len())
@@ -79,3 +79,38 @@ func packNum(value reflect.Value) []byte {
 	}
 
 }
+
+// packSliceFast packs dynamic arrays of big integers and addresses, the most
+// common homogeneous array arguments, without reflecting on every element. It
+// reports false if the value is not of such a type, in which case the generic
+// encoder must be used. The output is identical to the generic encoder's.
+func packSliceFast(t Type, v reflect.Value) ([]byte, bool) {
+	if t.T != SliceTy || v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	switch {
+	case (t.Elem.T == UintTy || t.Elem.T == IntTy) && v.Type().Elem() == bigT:
+		ints := v.Interface().([]*big.Int)
+		ret := make([]byte, 32*(len(ints)+1))
+		math.ReadBits(big.NewInt(int64(len(ints))), ret[:32])
+		for i, n := range ints {
+			word := ret[32*(i+1) : 32*(i+2)]
+			if n.Sign() >= 0 {
+				math.ReadBits(n, word)
+			} else {
+				copy(word, U256(new(big.Int).Set(n)))
+			}
+		}
+		return ret, true
+
+	case t.Elem.T == AddressTy && v.Type().Elem() == addressT:
+		addrs := v.Interface().([]common.Address)
+		ret := make([]byte, 32*(len(addrs)+1))
+		math.ReadBits(big.NewInt(int64(len(addrs))), ret[:32])
+		for i, addr := range addrs {
+			copy(ret[32*(i+2)-common.AddressLength:32*(i+2)], addr[:])
+		}
+		return ret, true
+	}
+	return nil, false
+}
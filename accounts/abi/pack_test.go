@@ -625,6 +625,47 @@ func TestPack(t *testing.T) {
 		if !bytes.Equal(output, test.output) {
 			t.Errorf("input %d for typ: %v failed. Expected bytes: '%x' Got: '%x'", i, typ.String(), test.output, output)
 		}
+		if size, err := typ.encodedLen(reflect.ValueOf(test.input)); err != nil || size != len(output) {
+			t.Errorf("input %d for typ: %v failed. Encoded length mismatch: have %d (%v), want %d", i, typ.String(), size, err, len(output))
+		}
+	}
+}
+
+func TestPackPacked(t *testing.T) {
+	for i, test := range []struct {
+		types  []string
+		inputs []interface{}
+		output string
+	}{
+		{[]string{"uint8", "uint16", "uint32"}, []interface{}{uint8(1), uint16(2), uint32(3)}, "01000200000003"},
+		{[]string{"int16", "int256"}, []interface{}{int16(-1), big.NewInt(-2)}, "ffff" + strings.Repeat("ff", 31) + "fe"},
+		{[]string{"bool", "bool"}, []interface{}{true, false}, "0100"},
+		{[]string{"address"}, []interface{}{common.Address{1}}, "0100000000000000000000000000000000000000"},
+		{[]string{"bytes4", "bytes"}, []interface{}{[4]byte{1, 2, 3, 4}, []byte{5, 6}}, "010203040506"},
+		{[]string{"string", "uint8"}, []interface{}{"hi", uint8(7)}, "686907"},
+		{[]string{"uint8[]"}, []interface{}{[]uint8{1, 2}}, strings.Repeat("00", 31) + "01" + strings.Repeat("00", 31) + "02"},
+		{[]string{"address[2]"}, []interface{}{[2]common.Address{{1}, {2}}}, strings.Repeat("00", 12) + "01" + strings.Repeat("00", 19) + strings.Repeat("00", 12) + "02" + strings.Repeat("00", 19)},
+	} {
+		var args Arguments
+		for _, typ := range test.types {
+			abiType, err := NewType(typ, nil)
+			if err != nil {
+				t.Fatalf("test %d: invalid type %s: %v", i, typ, err)
+			}
+			args = append(args, Argument{Type: abiType})
+		}
+		output, err := args.PackPacked(test.inputs...)
+		if err != nil {
+			t.Fatalf("test %d: pack error: %v", i, err)
+		}
+		if have := common.Bytes2Hex(output); have != test.output {
+			t.Errorf("test %d: pack mismatch: have %s, want %s", i, have, test.output)
+		}
+	}
+	// Dynamic array elements are not representable in packed mode
+	typ, _ := NewType("string[]", nil)
+	if _, err := (Arguments{{Type: typ}}).PackPacked([]string{"a"}); err == nil {
+		t.Errorf("expected error packing dynamic array elements")
 	}
 }
 
@@ -781,3 +822,233 @@ func TestPackNumber(t *testing.T) {
 		}
 	}
 }
+
+// packSliceGeneric packs a dynamic array of static elements through the generic
+// reflection based encoder, bypassing the fast paths.
+func packSliceGeneric(t Type, v reflect.Value) []byte {
+	ret := packNum(reflect.ValueOf(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		ret = append(ret, packElement(*t.Elem, v.Index(i))...)
+	}
+	return ret
+}
+
+// Tests that the fast paths of common dynamic arrays produce the same output as
+// the generic encoder.
+func TestPackSliceFast(t *testing.T) {
+	huge := new(big.Int).Lsh(common.Big1, 255)
+	tests := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint256[]", []*big.Int{}},
+		{"uint256[]", []*big.Int{big.NewInt(0), big.NewInt(1), huge, new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 256), common.Big1)}},
+		{"int256[]", []*big.Int{big.NewInt(-1), big.NewInt(1), new(big.Int).Neg(huge)}},
+		{"uint128[]", []*big.Int{big.NewInt(42)}},
+		{"address[]", []common.Address{}},
+		{"address[]", []common.Address{{1}, common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")}},
+	}
+	for i, tt := range tests {
+		typ, err := NewType(tt.typ, nil)
+		if err != nil {
+			t.Fatalf("test %d: invalid type: %v", i, err)
+		}
+		want := packSliceGeneric(typ, reflect.ValueOf(tt.value))
+		have, err := typ.pack(reflect.ValueOf(tt.value))
+		if err != nil {
+			t.Fatalf("test %d: pack failed: %v", i, err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Errorf("test %d: pack mismatch: have %x, want %x", i, have, want)
+		}
+	}
+}
+
+func BenchmarkPackBigIntSlice(b *testing.B) {
+	typ, _ := NewType("uint256[]", nil)
+	ints := make([]*big.Int, 10000)
+	for i := range ints {
+		ints[i] = new(big.Int).Lsh(big.NewInt(int64(i)), 128)
+	}
+	value := reflect.ValueOf(ints)
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			typ.pack(value)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			packSliceGeneric(typ, value)
+		}
+	})
+}
+
+// Tests that dynamic arrays of dynamic types are encoded with correct offsets,
+// both at the top level and nested within tuples and tuple arrays, and that the
+// encodings unpack back into the original values.
+func TestPackNestedDynamicArrays(t *testing.T) {
+	const definition = `[
+	{"name":"strings","type":"function","outputs":[{"name":"a","type":"string[]"}]},
+	{"name":"bytes","type":"function","outputs":[{"name":"a","type":"bytes[]"}]},
+	{"name":"tuple","type":"function","outputs":[{"name":"a","type":"tuple","components":[{"name":"names","type":"string[]"},{"name":"data","type":"bytes"}]}]},
+	{"name":"tuples","type":"function","outputs":[{"name":"a","type":"tuple[]","components":[{"name":"names","type":"string[]"},{"name":"data","type":"bytes"}]}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type entry struct {
+		Names []string
+		Data  []byte
+	}
+	tests := []struct {
+		method string
+		input  interface{}
+		output interface{} // pointer to an empty value to unpack into
+		packed string
+	}{
+		{
+			"strings", []string{"a", "bc"}, new([]string),
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000080" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"6100000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"6263000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			"bytes", [][]byte{{0x01}, {0x02, 0x03}}, new([][]byte),
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000080" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0100000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0203000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			"tuple", entry{[]string{"x"}, []byte{0x09}}, new(entry),
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"00000000000000000000000000000000000000000000000000000000000000c0" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"7800000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0900000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			"tuples", []entry{{[]string{"a", "bc"}, []byte{0x01, 0x02}}, {[]string{}, []byte{}}}, new([]entry),
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"00000000000000000000000000000000000000000000000000000000000001a0" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000120" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000080" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"6100000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"6263000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0102000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000060" +
+				"0000000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+	for i, tt := range tests {
+		packed, err := abi.Methods[tt.method].Outputs.Pack(tt.input)
+		if err != nil {
+			t.Fatalf("test %d (%s): pack failed: %v", i, tt.method, err)
+		}
+		if want := common.Hex2Bytes(tt.packed); !bytes.Equal(packed, want) {
+			t.Errorf("test %d (%s): pack mismatch: have %x, want %x", i, tt.method, packed, want)
+			continue
+		}
+		if err := abi.Unpack(tt.output, tt.method, packed); err != nil {
+			t.Fatalf("test %d (%s): unpack failed: %v", i, tt.method, err)
+		}
+		if have := reflect.ValueOf(tt.output).Elem().Interface(); !reflect.DeepEqual(have, tt.input) {
+			t.Errorf("test %d (%s): unpack mismatch: have %v, want %v", i, tt.method, have, tt.input)
+		}
+	}
+}
+
+func TestPackHexStrings(t *testing.T) {
+	const definition = `[{"name":"f","type":"function","inputs":[{"name":"a","type":"address"},{"name":"b","type":"bytes4"},{"name":"c","type":"bytes"},{"name":"d","type":"string"}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+
+	native, err := abi.Pack("f", addr, [4]byte{1, 2, 3, 4}, []byte{0xca, 0xfe}, "0x1234")
+	if err != nil {
+		t.Fatalf("failed to pack native arguments: %v", err)
+	}
+	hexed, err := abi.Pack("f", addr.Hex(), "0x01020304", "0xcafe", "0x1234")
+	if err != nil {
+		t.Fatalf("failed to pack hex arguments: %v", err)
+	}
+	if !bytes.Equal(native, hexed) {
+		t.Errorf("packed hex arguments mismatch: have %x, want %x", hexed, native)
+	}
+	if err := abi.Methods["f"].Inputs.ValidateArgs(addr.Hex(), "0x01020304", "0xcafe", "0x1234"); err != nil {
+		t.Errorf("failed to validate hex arguments: %v", err)
+	}
+	// Malformed hex and wrong lengths for fixed size types must be rejected
+	invalid := [][]interface{}{
+		{"0xdeadbeef", [4]byte{}, []byte{}, ""},
+		{addr, "0x010203", []byte{}, ""},
+		{addr, [4]byte{}, "cafe", ""},
+		{addr, [4]byte{}, "0xcaf", ""},
+	}
+	for i, args := range invalid {
+		if _, err := abi.Pack("f", args...); err == nil {
+			t.Errorf("test %d: invalid hex argument accepted", i)
+		}
+	}
+}
+
+func TestEncodedLen(t *testing.T) {
+	const definition = `[{"name":"f","type":"function","inputs":[{"name":"a","type":"uint256"},{"name":"b","type":"string"},{"name":"c","type":"bytes32[2]"},{"name":"d","type":"bytes[]"},{"name":"e","type":"tuple","components":[{"name":"x","type":"string[]"},{"name":"y","type":"address"}]}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type tuple struct {
+		X []string
+		Y common.Address
+	}
+	args := []interface{}{
+		big.NewInt(1),
+		strings.Repeat("a", 33),
+		[2][32]byte{{1}, {2}},
+		[][]byte{{}, {1, 2, 3}, make([]byte, 64)},
+		tuple{[]string{"x", "", strings.Repeat("y", 32)}, common.Address{1}},
+	}
+	packed, err := abi.Methods["f"].Inputs.Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := abi.Methods["f"].Inputs.EncodedLen(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != len(packed) {
+		t.Errorf("encoded length mismatch: have %d, want %d", size, len(packed))
+	}
+	if _, err := abi.Methods["f"].Inputs.EncodedLen(args[:4]...); err == nil {
+		t.Errorf("expected argument count mismatch error")
+	}
+}
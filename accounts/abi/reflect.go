@@ -134,6 +134,59 @@ func requireUnpackKind(v reflect.Value, t reflect.Type, k reflect.Kind,
 	return nil
 }
 
+// tupleFields resolves the fields of the struct value that correspond to the
+// components of the tuple type t. Named components are mapped onto the struct
+// by name. If any component is unnamed, name based mapping is impossible and
+// all components are mapped positionally onto the struct fields instead.
+func tupleFields(t Type, value reflect.Value) ([]reflect.Value, error) {
+	indices, err := tupleFieldIndices(t, value.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]reflect.Value, len(indices))
+	for i, index := range indices {
+		fields[i] = value.FieldByIndex(index)
+	}
+	return fields, nil
+}
+
+// tupleFieldIndices resolves the field index sequences of the struct type typ
+// that correspond to the components of the tuple type t, as per tupleFields.
+func tupleFieldIndices(t Type, typ reflect.Type) ([][]int, error) {
+	positional := false
+	for _, name := range t.TupleRawNames {
+		if ToCamelCase(name) == "" {
+			positional = true
+			break
+		}
+	}
+	indices := make([][]int, len(t.TupleElems))
+	if positional {
+		if typ.NumField() != len(t.TupleElems) {
+			return nil, fmt.Errorf("abi: unnamed tuple has %d components, struct has %d fields", len(t.TupleElems), typ.NumField())
+		}
+		for i := range indices {
+			if typ.Field(i).PkgPath != "" {
+				return nil, fmt.Errorf("abi: field %d of the given struct is unexported", i)
+			}
+			indices[i] = []int{i}
+		}
+		return indices, nil
+	}
+	fieldmap, err := mapArgNamesToStructFields(t.TupleRawNames, reflect.New(typ).Elem())
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range t.TupleRawNames {
+		field, ok := typ.FieldByName(fieldmap[name])
+		if !ok {
+			return nil, fmt.Errorf("abi: field %s can't found in the given value", name)
+		}
+		indices[i] = field.Index
+	}
+	return indices, nil
+}
+
 // mapArgNamesToStructFields maps a slice of argument names to struct fields.
 // first round: for each Exportable field that contains a `abi:""` tag
 //   and this field name exists in the given argument name list, pair them togccmer.
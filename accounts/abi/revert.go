@@ -0,0 +1,102 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// revertSelector is the selector of Error(string), used by require and revert.
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// panicSelector is the selector of Panic(uint256), used by Solidity 0.8+ for
+	// failed assertions, arithmetic overflows and other internal errors.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+	errNotRevert = errors.New("abi: data is not an Error(string) or Panic(uint256) revert")
+	errNotPanic  = errors.New("abi: data is not a Panic(uint256) revert")
+)
+
+// panicReasons maps the panic codes emitted by the Solidity compiler to a human
+// readable description.
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion to invalid enum value",
+	0x22: "access to incorrectly encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized internal function",
+}
+
+// PanicReason returns a human readable description of the given Solidity panic
+// code, or a generic one if the code is unknown.
+func PanicReason(code *big.Int) string {
+	if code.IsUint64() {
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return reason
+		}
+	}
+	return fmt.Sprintf("unknown panic code %#x", code)
+}
+
+// UnpackPanic decodes the panic code from the return data of an execution that
+// reverted with Panic(uint256).
+func UnpackPanic(data []byte) (*big.Int, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], panicSelector) {
+		return nil, errNotPanic
+	}
+	typ, _ := NewType("uint256", nil)
+	values, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// UnpackRevert decodes the reason from the return data of a reverted execution.
+// Both Error(string) reverts and Panic(uint256) reverts are recognized, the
+// latter being described as "panic: <reason> (<code>)".
+func UnpackRevert(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", errNotRevert
+	}
+	switch {
+	case bytes.Equal(data[:4], revertSelector):
+		typ, _ := NewType("string", nil)
+		values, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		return values[0].(string), nil
+
+	case bytes.Equal(data[:4], panicSelector):
+		code, err := UnpackPanic(data)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("panic: %s (%#x)", PanicReason(code), code), nil
+	}
+	return "", errNotRevert
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"testing"
+
+	"github.com/ccmchain/go-ccmchain/common"
+)
+
+func TestUnpackRevert(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+		fail bool
+	}{
+		// Error("revert reason")
+		{"0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000", "revert reason", false},
+		// Panic(0x11)
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000011", "panic: arithmetic underflow or overflow (0x11)", false},
+		// Panic(0x01)
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000001", "panic: assertion failed (0x1)", false},
+		// Panic(0x99)
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000099", "panic: unknown panic code 0x99 (0x99)", false},
+		// Truncated panic, custom error and no data at all
+		{"0x4e487b7100", "", true},
+		{"0xdeadbeef0000000000000000000000000000000000000000000000000000000000000001", "", true},
+		{"0x", "", true},
+	}
+	for i, tt := range tests {
+		have, err := UnpackRevert(common.FromHex(tt.data))
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error, got %q", i, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to unpack revert: %v", i, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("test %d: reason mismatch: have %q, want %q", i, have, tt.want)
+		}
+	}
+	code, err := UnpackPanic(common.FromHex("0x4e487b710000000000000000000000000000000000000000000000000000000000000012"))
+	if err != nil {
+		t.Fatalf("failed to unpack panic: %v", err)
+	}
+	if code.Uint64() != 0x12 || PanicReason(code) != "division or modulo by zero" {
+		t.Errorf("panic mismatch: have code %v, reason %q", code, PanicReason(code))
+	}
+}
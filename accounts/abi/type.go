@@ -17,12 +17,13 @@
 package abi
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/ccmchain/go-ccmchain/common"
 )
 
 // Type enumerator
@@ -42,6 +43,50 @@ const (
 	FunctionTy
 )
 
+// TypeClass is a coarse classification of ABI types, e.g. for choosing input
+// widgets in user interfaces.
+type TypeClass int
+
+// Type classes returned by Type.Class.
+const (
+	OtherClass      TypeClass = iota // Types without a dedicated class (hash, fixed point)
+	IntegerClass                     // Signed and unsigned integers of any width
+	FixedBytesClass                  // bytes1 to bytes32
+	BytesClass                       // Dynamically sized bytes
+	AddressClass                     // Addresses
+	BoolClass                        // Booleans
+	StringClass                      // Strings
+	ArrayClass                       // Fixed and dynamically sized arrays
+	TupleClass                       // Tuples (structs)
+	FunctionClass                    // Function types
+)
+
+// String implements fmt.Stringer.
+func (c TypeClass) String() string {
+	switch c {
+	case IntegerClass:
+		return "integer"
+	case FixedBytesClass:
+		return "fixed-bytes"
+	case BytesClass:
+		return "bytes"
+	case AddressClass:
+		return "address"
+	case BoolClass:
+		return "bool"
+	case StringClass:
+		return "string"
+	case ArrayClass:
+		return "array"
+	case TupleClass:
+		return "tuple"
+	case FunctionClass:
+		return "function"
+	default:
+		return "other"
+	}
+}
+
 // Type is the reflection of the supported argument type
 type Type struct {
 	Elem *Type
@@ -119,16 +164,27 @@ func NewType(t string, components []ArgumentMarshaling) (typ Type, err error) {
 	var varSize int
 	if len(parsedType[3]) > 0 {
 		var err error
-		varSize, err = strconv.Atoi(parsedType[2])
+		size := parsedType[2]
+		if parsedType[1] == "fixed" || parsedType[1] == "ufixed" {
+			// Fixed point types are sized as <M>x<N>, N being the decimals
+			if len(parsedType[5]) == 0 {
+				return Type{}, fmt.Errorf("invalid fixed point type '%v'", t)
+			}
+			size = parsedType[3]
+		}
+		varSize, err = strconv.Atoi(size)
 		if err != nil {
 			return Type{}, fmt.Errorf("abi: error parsing variable size: %v", err)
 		}
-	} else {
-		if parsedType[0] == "uint" || parsedType[0] == "int" {
-			// this should fail because it means that there's somccming wrong with
-			// the abi type (the compiler should always format it to the size...always)
-			return Type{}, fmt.Errorf("unsupported arg type: %s", t)
-		}
+	} else if parsedType[0] == "uint" || parsedType[0] == "int" {
+		// The unsized aliases are equivalent to their 256 bit counterparts, use
+		// the canonical form so signatures and Go type matching agree
+		varSize = 256
+		typ.stringKind = parsedType[0] + "256"
+	} else if parsedType[0] == "ufixed" || parsedType[0] == "fixed" {
+		// Likewise the unsized fixed point aliases stand for 128 bits with 18 decimals
+		varSize = 128
+		typ.stringKind = parsedType[0] + "128x18"
 	}
 	// varType is the parsed abi type
 	switch varType := parsedType[1]; varType {
@@ -140,6 +196,12 @@ func NewType(t string, components []ArgumentMarshaling) (typ Type, err error) {
 		typ.Kind, typ.Type = reflectIntKindAndType(true, varSize)
 		typ.Size = varSize
 		typ.T = UintTy
+	case "fixed", "ufixed":
+		// Fixed point numbers are handled as their raw, scaled integer values
+		typ.Kind = reflect.Ptr
+		typ.Type = bigT
+		typ.Size = varSize
+		typ.T = FixedPointTy
 	case "bool":
 		typ.Kind = reflect.Bool
 		typ.T = BoolTy
@@ -172,19 +234,32 @@ func NewType(t string, components []ArgumentMarshaling) (typ Type, err error) {
 			expression string // canonical parameter expression
 		)
 		expression += "("
+		used := make(map[string]bool)
+		for _, c := range components {
+			used[ToCamelCase(c.Name)] = true
+		}
 		for idx, c := range components {
 			cType, err := NewType(c.Type, c.Components)
 			if err != nil {
 				return Type{}, err
 			}
-			if ToCamelCase(c.Name) == "" {
-				return Type{}, errors.New("abi: purely anonymous or underscored field is not supported")
-			}
-			fields = append(fields, reflect.StructField{
+			// Unnamed (or purely underscored) components get a positional field
+			// name and are mapped onto Go structs by their index.
+			field := reflect.StructField{
 				Name: ToCamelCase(c.Name), // reflect.StructOf will panic for any exported field.
 				Type: cType.Type,
 				Tag:  reflect.StructTag("json:\"" + c.Name + "\""),
-			})
+			}
+			if field.Name == "" {
+				// Avoid clashing with named components, e.g. "field1"
+				field.Name = fmt.Sprintf("Field%d", idx)
+				for used[field.Name] {
+					field.Name += "_"
+				}
+				used[field.Name] = true
+				field.Tag = ""
+			}
+			fields = append(fields, field)
 			elems = append(elems, &cType)
 			names = append(names, c.Name)
 			expression += cType.stringKind
@@ -211,6 +286,33 @@ func NewType(t string, components []ArgumentMarshaling) (typ Type, err error) {
 	return
 }
 
+// Class returns the coarse classification of the type. Arrays are classified as
+// ArrayClass regardless of their element type, which is available via Elem.
+func (t Type) Class() TypeClass {
+	switch t.T {
+	case IntTy, UintTy:
+		return IntegerClass
+	case FixedBytesTy:
+		return FixedBytesClass
+	case BytesTy:
+		return BytesClass
+	case AddressTy:
+		return AddressClass
+	case BoolTy:
+		return BoolClass
+	case StringTy:
+		return StringClass
+	case SliceTy, ArrayTy:
+		return ArrayClass
+	case TupleTy:
+		return TupleClass
+	case FunctionTy:
+		return FunctionClass
+	default:
+		return OtherClass
+	}
+}
+
 // String implements Stringer
 func (t Type) String() (out string) {
 	return t.stringKind
@@ -225,6 +327,9 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 
 	switch t.T {
 	case SliceTy, ArrayTy:
+		if packed, ok := packSliceFast(t, v); ok {
+			return packed, nil
+		}
 		var ret []byte
 
 		if t.requiresLengthPrefix() {
@@ -263,7 +368,7 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 		//     head(X(i)) = enc(len(head(X(1)) ... head(X(k)) tail(X(1)) ... tail(X(i-1))))
 		//     tail(X(i)) = enc(X(i))
 		// otherwise, i.e. if Ti is a dynamic type.
-		fieldmap, err := mapArgNamesToStructFields(t.TupleRawNames, v)
+		fields, err := tupleFields(t, v)
 		if err != nil {
 			return nil, err
 		}
@@ -274,11 +379,7 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 		}
 		var ret, tail []byte
 		for i, elem := range t.TupleElems {
-			field := v.FieldByName(fieldmap[t.TupleRawNames[i]])
-			if !field.IsValid() {
-				return nil, fmt.Errorf("field %s for tuple not found in the given struct", t.TupleRawNames[i])
-			}
-			val, err := elem.pack(field)
+			val, err := elem.pack(fields[i])
 			if err != nil {
 				return nil, err
 			}
@@ -297,6 +398,145 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 	}
 }
 
+// packPacked packs the given value according to Solidity's packed encoding.
+// Top level values use the minimal width of their type, whereas array elements
+// are padded to 32 bytes. Dynamic array elements and tuples are not supported.
+func (t Type) packPacked(v reflect.Value, elem bool) ([]byte, error) {
+	// dereference pointer first if it's a pointer
+	v = indirect(v)
+	if err := typeCheck(t, v); err != nil {
+		return nil, err
+	}
+
+	switch t.T {
+	case SliceTy, ArrayTy:
+		if elem {
+			return nil, fmt.Errorf("abi: nested array %v not supported in packed mode", t)
+		}
+		var ret []byte
+		for i := 0; i < v.Len(); i++ {
+			val, err := t.Elem.packPacked(v.Index(i), true)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, val...)
+		}
+		return ret, nil
+	case TupleTy:
+		return nil, fmt.Errorf("abi: tuple %v not supported in packed mode", t)
+	case StringTy:
+		if elem {
+			return nil, fmt.Errorf("abi: dynamic array element %v not supported in packed mode", t)
+		}
+		return []byte(v.String()), nil
+	case BytesTy:
+		if elem {
+			return nil, fmt.Errorf("abi: dynamic array element %v not supported in packed mode", t)
+		}
+		if v.Kind() == reflect.Array {
+			v = mustArrayToByteSlice(v)
+		}
+		return common.CopyBytes(v.Bytes()), nil
+	}
+	// Elementary static type, packed into a 32 byte word and trimmed if needed
+	word := packElement(t, v)
+	if elem {
+		return word, nil
+	}
+	switch t.T {
+	case IntTy, UintTy, FixedPointTy:
+		return word[32-t.Size/8:], nil
+	case BoolTy:
+		return word[31:], nil
+	case AddressTy:
+		return word[32-t.Size:], nil
+	default: // FixedBytesTy, FunctionTy
+		return word[:t.Size], nil
+	}
+}
+
+// encodedLen returns the length of the encoding pack would produce for the given
+// value, running the same type checks but without encoding anything.
+func (t Type) encodedLen(v reflect.Value) (int, error) {
+	v = indirect(v)
+	if err := typeCheck(t, v); err != nil {
+		return 0, err
+	}
+	switch t.T {
+	case SliceTy, ArrayTy:
+		size := 0
+		if t.requiresLengthPrefix() {
+			size += 32
+		}
+		for i := 0; i < v.Len(); i++ {
+			n, err := t.Elem.encodedLen(v.Index(i))
+			if err != nil {
+				return 0, err
+			}
+			if isDynamicType(*t.Elem) {
+				size += 32
+			}
+			size += n
+		}
+		return size, nil
+	case TupleTy:
+		fields, err := tupleFields(t, v)
+		if err != nil {
+			return 0, err
+		}
+		size := 0
+		for i, elem := range t.TupleElems {
+			n, err := elem.encodedLen(fields[i])
+			if err != nil {
+				return 0, err
+			}
+			if isDynamicType(*elem) {
+				size += 32
+			}
+			size += n
+		}
+		return size, nil
+	case StringTy, BytesTy:
+		return 32 + (v.Len()+31)/32*32, nil
+	default:
+		return 32, nil
+	}
+}
+
+// validate runs the same type checks as pack on the given value and all of its
+// elements, without producing the encoding.
+func (t Type) validate(v reflect.Value) error {
+	v = indirect(v)
+	if err := typeCheck(t, v); err != nil {
+		return err
+	}
+	switch t.T {
+	case SliceTy, ArrayTy:
+		for i := 0; i < v.Len(); i++ {
+			if err := t.Elem.validate(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case TupleTy:
+		fields, err := tupleFields(t, v)
+		if err != nil {
+			return err
+		}
+		for i, elem := range t.TupleElems {
+			if err := elem.validate(fields[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// signed reports whether the type is a signed number, i.e. an int or a fixed
+// point number of the signed flavour.
+func (t Type) signed() bool {
+	return t.T == IntTy || (t.T == FixedPointTy && !strings.HasPrefix(t.stringKind, "ufixed"))
+}
+
 // requireLengthPrefix returns whccmer the type requires any sort of length
 // prefixing.
 func (t Type) requiresLengthPrefix() bool {
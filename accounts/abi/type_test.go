@@ -88,13 +88,13 @@ func TestTypeRegexp(t *testing.T) {
 		{"address", nil, Type{Kind: reflect.Array, Type: addressT, Size: 20, T: AddressTy, stringKind: "address"}},
 		{"address[]", nil, Type{T: SliceTy, Kind: reflect.Slice, Type: reflect.TypeOf([]common.Address{}), Elem: &Type{Kind: reflect.Array, Type: addressT, Size: 20, T: AddressTy, stringKind: "address"}, stringKind: "address[]"}},
 		{"address[2]", nil, Type{Kind: reflect.Array, T: ArrayTy, Size: 2, Type: reflect.TypeOf([2]common.Address{}), Elem: &Type{Kind: reflect.Array, Type: addressT, Size: 20, T: AddressTy, stringKind: "address"}, stringKind: "address[2]"}},
-		// TODO when fixed types are implemented properly
-		// {"fixed", nil, Type{}},
-		// {"fixed128x128", nil, Type{}},
-		// {"fixed[]", nil, Type{}},
-		// {"fixed[2]", nil, Type{}},
-		// {"fixed128x128[]", nil, Type{}},
-		// {"fixed128x128[2]", nil, Type{}},
+		{"fixed", nil, Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x18"}},
+		{"ufixed", nil, Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "ufixed128x18"}},
+		{"fixed128x128", nil, Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x128"}},
+		{"fixed[]", nil, Type{T: SliceTy, Kind: reflect.Slice, Type: reflect.TypeOf([]*big.Int{}), Elem: &Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x18"}, stringKind: "fixed128x18[]"}},
+		{"fixed[2]", nil, Type{Kind: reflect.Array, T: ArrayTy, Size: 2, Type: reflect.TypeOf([2]*big.Int{}), Elem: &Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x18"}, stringKind: "fixed128x18[2]"}},
+		{"fixed128x128[]", nil, Type{T: SliceTy, Kind: reflect.Slice, Type: reflect.TypeOf([]*big.Int{}), Elem: &Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x128"}, stringKind: "fixed128x128[]"}},
+		{"fixed128x128[2]", nil, Type{Kind: reflect.Array, T: ArrayTy, Size: 2, Type: reflect.TypeOf([2]*big.Int{}), Elem: &Type{Kind: reflect.Ptr, Type: bigT, Size: 128, T: FixedPointTy, stringKind: "fixed128x128"}, stringKind: "fixed128x128[2]"}},
 		{"tuple", []ArgumentMarshaling{{Name: "a", Type: "int64"}}, Type{Kind: reflect.Struct, T: TupleTy, Type: reflect.TypeOf(struct {
 			A int64 `json:"a"`
 		}{}), stringKind: "(int64)",
@@ -123,8 +123,9 @@ func TestTypeCheck(t *testing.T) {
 		input      interface{}
 		err        string
 	}{
-		{"uint", nil, big.NewInt(1), "unsupported arg type: uint"},
-		{"int", nil, big.NewInt(1), "unsupported arg type: int"},
+		{"uint", nil, big.NewInt(1), ""},
+		{"int", nil, big.NewInt(1), ""},
+		{"uint[]", nil, []*big.Int{big.NewInt(1)}, ""},
 		{"uint256", nil, big.NewInt(1), ""},
 		{"uint256[][3][]", nil, [][3][]*big.Int{{{}}}, ""},
 		{"uint256[][][3]", nil, [3][][]*big.Int{{{}}}, ""},
@@ -306,3 +307,53 @@ func TestTypeCheck(t *testing.T) {
 		}
 	}
 }
+
+// Tests that types are classified correctly.
+func TestTypeClass(t *testing.T) {
+	tests := []struct {
+		typ        string
+		components []ArgumentMarshaling
+		class      TypeClass
+	}{
+		{"int8", nil, IntegerClass},
+		{"uint256", nil, IntegerClass},
+		{"bytes4", nil, FixedBytesClass},
+		{"bytes", nil, BytesClass},
+		{"address", nil, AddressClass},
+		{"bool", nil, BoolClass},
+		{"string", nil, StringClass},
+		{"uint8[]", nil, ArrayClass},
+		{"string[2]", nil, ArrayClass},
+		{"tuple", []ArgumentMarshaling{{Name: "a", Type: "uint256"}}, TupleClass},
+		{"function", nil, FunctionClass},
+	}
+	for _, test := range tests {
+		typ, err := NewType(test.typ, test.components)
+		if err != nil {
+			t.Fatalf("%s: failed to create type: %v", test.typ, err)
+		}
+		if class := typ.Class(); class != test.class {
+			t.Errorf("%s: class mismatch: have %v, want %v", test.typ, class, test.class)
+		}
+	}
+}
+
+// Tests that the positional field names of unnamed tuple components don't clash
+// with the names of other components.
+func TestTypeUnnamedTupleFieldNames(t *testing.T) {
+	typ, err := NewType("tuple", []ArgumentMarshaling{
+		{Name: "", Type: "uint256"},
+		{Name: "_", Type: "bool"},
+		{Name: "field1", Type: "address"},
+		{Name: "field0", Type: "uint8"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create type: %v", err)
+	}
+	want := []string{"Field0_", "Field1_", "Field1", "Field0"}
+	for i, name := range want {
+		if have := typ.Type.Field(i).Name; have != name {
+			t.Errorf("field %d: name mismatch: have %s, want %s", i, have, name)
+		}
+	}
+}
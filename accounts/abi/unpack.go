@@ -231,6 +231,12 @@ func toGoType(index int, t Type, output []byte) (interface{}, error) {
 		return string(output[begin : begin+length]), nil
 	case IntTy, UintTy:
 		return readInteger(t.T, t.Kind, returnOutput), nil
+	case FixedPointTy:
+		// Fixed point numbers decode into their raw, scaled integer value
+		if t.signed() {
+			return readInteger(IntTy, t.Kind, returnOutput), nil
+		}
+		return readInteger(UintTy, t.Kind, returnOutput), nil
 	case BoolTy:
 		return readBool(returnOutput)
 	case AddressTy:
@@ -293,3 +299,175 @@ func tuplePointsTo(index int, output []byte) (start int, err error) {
 	}
 	return int(offset.Uint64()), nil
 }
+
+// unpackInto parses the output bytes at the given index like toGoType, but writes
+// the value straight into dst, reusing the memory already held by it. Slices are
+// resliced if their capacity suffices, non-nil big integers are overwritten and
+// byte slices are copied into their existing backing arrays. Destinations that
+// have no reuse-aware path fall back to toGoType and set.
+//
+// If t is a tuple, fields may hold the struct field indices resolved beforehand
+// by tupleFieldIndices, saving their resolution on every element of a slice.
+func unpackInto(index int, t Type, output []byte, dst reflect.Value, fields [][]int) error {
+	if index+32 > len(output) {
+		return fmt.Errorf("abi: cannot marshal in to go type: length insufficient %d require %d", len(output), index+32)
+	}
+	// Allocate nil struct pointers so tuples can be decoded through them
+	if t.T == TupleTy && dst.Kind() == reflect.Ptr && dst.Type().Elem().Kind() == reflect.Struct {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	word := output[index : index+32]
+
+	switch {
+	case t.T == TupleTy && dst.Kind() == reflect.Struct:
+		if isDynamicType(t) {
+			begin, err := tuplePointsTo(index, output)
+			if err != nil {
+				return err
+			}
+			return unpackTupleInto(t, output[begin:], dst, fields)
+		}
+		return unpackTupleInto(t, output[index:], dst, fields)
+
+	case t.T == SliceTy && dst.Kind() == reflect.Slice:
+		begin, length, err := lengthPrefixPointsTo(index, output)
+		if err != nil {
+			return err
+		}
+		if 32*length > len(output)-begin {
+			return fmt.Errorf("abi: cannot marshal in to go array: offset %d would go over slice boundary (len=%d)", len(output), begin+32*length)
+		}
+		if dst.Cap() >= length {
+			dst.SetLen(length)
+		} else {
+			dst.Set(reflect.MakeSlice(dst.Type(), length, length))
+		}
+		return unpackElemsInto(t, output[begin:], dst)
+
+	case t.T == ArrayTy && dst.Kind() == reflect.Array && dst.Len() == t.Size:
+		if isDynamicType(*t.Elem) {
+			offset := int(binary.BigEndian.Uint64(word[24:]))
+			if offset < 0 || offset > len(output) {
+				return fmt.Errorf("abi: cannot marshal in to go array: offset %d would go over slice boundary (len=%d)", offset, len(output))
+			}
+			return unpackElemsInto(t, output[offset:], dst)
+		}
+		return unpackElemsInto(t, output[index:], dst)
+
+	case (t.T == IntTy || t.T == UintTy || t.T == FixedPointTy) && dst.Type() == bigT:
+		if dst.IsNil() {
+			dst.Set(reflect.New(derefbigT))
+		}
+		num := dst.Interface().(*big.Int).SetBytes(word)
+		if t.signed() && num.Cmp(maxInt256) > 0 {
+			num.Sub(num, maxUint256)
+			num.Sub(num, common.Big1)
+		}
+		return nil
+
+	case (t.T == IntTy || t.T == UintTy) && dst.Kind() == t.Kind:
+		if t.T == UintTy {
+			dst.SetUint(binary.BigEndian.Uint64(word[24:]))
+		} else {
+			dst.SetInt(int64(binary.BigEndian.Uint64(word[24:])))
+		}
+		return nil
+
+	case t.T == BoolTy && dst.Kind() == reflect.Bool:
+		b, err := readBool(word)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+		return nil
+
+	case t.T == AddressTy && dst.Type() == t.Type:
+		*dst.Addr().Interface().(*common.Address) = common.BytesToAddress(word)
+		return nil
+
+	case t.T == HashTy && dst.Type() == t.Type:
+		*dst.Addr().Interface().(*common.Hash) = common.BytesToHash(word)
+		return nil
+
+	case t.T == BytesTy && dst.Type() == t.Type:
+		begin, length, err := lengthPrefixPointsTo(index, output)
+		if err != nil {
+			return err
+		}
+		buf := dst.Addr().Interface().(*[]byte)
+		*buf = append((*buf)[:0], output[begin:begin+length]...)
+		return nil
+
+	case t.T == StringTy && dst.Kind() == reflect.String:
+		begin, length, err := lengthPrefixPointsTo(index, output)
+		if err != nil {
+			return err
+		}
+		// Strings are immutable, only allocate if the content changed
+		if raw := output[begin : begin+length]; dst.String() != string(raw) {
+			dst.SetString(string(raw))
+		}
+		return nil
+
+	case t.T == FixedBytesTy && dst.Kind() == reflect.Array && dst.Len() == t.Size && dst.Type().Elem().Kind() == reflect.Uint8:
+		for i := 0; i < t.Size; i++ {
+			dst.Index(i).SetUint(uint64(word[i]))
+		}
+		return nil
+	}
+	value, err := toGoType(index, t, output)
+	if err != nil {
+		return err
+	}
+	return set(dst, reflect.ValueOf(value))
+}
+
+// unpackElemsInto decodes the elements of the array or slice type t into the
+// elements of dst, which must already have the correct length.
+func unpackElemsInto(t Type, output []byte, dst reflect.Value) error {
+	// Resolve the fields of tuple elements once for all of them
+	var fields [][]int
+	if elem := dst.Type().Elem(); t.Elem.T == TupleTy && dst.Len() > 0 {
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct {
+			var err error
+			if fields, err = tupleFieldIndices(*t.Elem, elem); err != nil {
+				return err
+			}
+		}
+	}
+	elemSize := getTypeSize(*t.Elem)
+	for i, j := 0, 0; j < dst.Len(); i, j = i+elemSize, j+1 {
+		if err := unpackInto(i, *t.Elem, output, dst.Index(j), fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unpackTupleInto decodes the components of the tuple type t into the matching
+// fields of the struct value dst, resolving them if fields is nil.
+func unpackTupleInto(t Type, output []byte, dst reflect.Value, fields [][]int) error {
+	if fields == nil {
+		var err error
+		if fields, err = tupleFieldIndices(t, dst.Type()); err != nil {
+			return err
+		}
+	}
+	virtualArgs := 0
+	for index, elem := range t.TupleElems {
+		if err := unpackInto((index+virtualArgs)*32, *elem, output, dst.FieldByIndex(fields[index]), nil); err != nil {
+			return err
+		}
+		// Static arrays and tuples are encoded inline, see forTupleUnpack
+		if (elem.T == ArrayTy || elem.T == TupleTy) && !isDynamicType(*elem) {
+			virtualArgs += getTypeSize(*elem)/32 - 1
+		}
+	}
+	return nil
+}
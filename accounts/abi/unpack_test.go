@@ -1052,6 +1052,403 @@ func TestUnpackTuple(t *testing.T) {
 	}
 }
 
+func TestUnpackUnnamedTuple(t *testing.T) {
+	const unnamedTuple = `[{"name":"tuple","constant":false,"outputs":[{"type":"tuple","name":"ret","components":[{"type":"int256","name":""},{"type":"bool"},{"type":"uint256[]","name":"_"}]}]}]`
+	abi, err := JSON(strings.NewReader(unnamedTuple))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buff := new(bytes.Buffer)
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000020")) // ret offset
+	buff.Write(common.Hex2Bytes("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")) // ret[0] = -1
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000001")) // ret[1] = true
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000060")) // ret[2] offset
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000002")) // ret[2] length
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000001")) // ret[2][0] = 1
+	buff.Write(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000002")) // ret[2][1] = 2
+
+	// Unnamed components are mapped onto the struct fields by position.
+	var v struct {
+		Value *big.Int
+		Flag  bool
+		List  []*big.Int
+	}
+	if err := abi.Unpack(&v, "tuple", buff.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Value.Cmp(big.NewInt(-1)) != 0 {
+		t.Errorf("unexpected value unpacked: want %d, got %d", -1, v.Value)
+	}
+	if !v.Flag {
+		t.Errorf("unexpected flag unpacked: want true, got false")
+	}
+	if len(v.List) != 2 || v.List[0].Cmp(big.NewInt(1)) != 0 || v.List[1].Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("unexpected list unpacked: want [1 2], got %v", v.List)
+	}
+	// Packing the struct back should yield the original encoding.
+	packed, err := abi.Methods["tuple"].Outputs.Pack(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(packed, buff.Bytes()) {
+		t.Errorf("repacked tuple mismatch: have %x, want %x", packed, buff.Bytes())
+	}
+	// A struct with a mismatching number of fields must be rejected.
+	var short struct {
+		Value *big.Int
+	}
+	if err := abi.Unpack(&short, "tuple", buff.Bytes()); err == nil {
+		t.Errorf("expected error unpacking into mismatching struct")
+	}
+}
+
+func TestUnpackTupleSliceIntoNamedStructs(t *testing.T) {
+	const definition = `[{"name":"tupleSlice","constant":true,"outputs":[{"components":[{"name":"x","type":"uint256"},{"name":"y","type":"uint256"}],"name":"a","type":"tuple[]"}]},
+	{"name":"tupleArray","constant":true,"outputs":[{"components":[{"name":"x","type":"uint256"},{"name":"y","type":"uint256"}],"name":"a","type":"tuple[2]"}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type Point struct {
+		X *big.Int
+		Y *big.Int
+	}
+	points := []Point{
+		{big.NewInt(1), big.NewInt(2)},
+		{big.NewInt(3), big.NewInt(4)},
+	}
+	// Round trip a dynamic tuple slice through named structs and struct pointers
+	packed, err := abi.Methods["tupleSlice"].Outputs.Pack(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slice []Point
+	if err := abi.Unpack(&slice, "tupleSlice", packed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(slice, points) {
+		t.Errorf("tuple slice mismatch: have %v, want %v", slice, points)
+	}
+	var ptrs []*Point
+	if err := abi.Unpack(&ptrs, "tupleSlice", packed); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range ptrs {
+		if !reflect.DeepEqual(*p, points[i]) {
+			t.Errorf("tuple pointer %d mismatch: have %v, want %v", i, *p, points[i])
+		}
+	}
+	// Round trip a fixed size tuple array through named structs
+	packed, err = abi.Methods["tupleArray"].Outputs.Pack([2]Point{points[0], points[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var array [2]Point
+	if err := abi.Unpack(&array, "tupleArray", packed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(array[:], points) {
+		t.Errorf("tuple array mismatch: have %v, want %v", array, points)
+	}
+}
+
+func TestUnpackIntoMapHex(t *testing.T) {
+	const definition = `[{"name":"values","constant":true,"outputs":[{"type":"uint256","name":"big"},{"type":"uint64","name":"small"},{"type":"int256[]","name":"list"},{"type":"address","name":"addr"}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	packed, err := abi.Methods["values"].Outputs.Pack(huge, uint64(42), []*big.Int{big.NewInt(-1), big.NewInt(16)}, common.Address{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := make(map[string]interface{})
+	if err := abi.UnpackIntoMapHex(v, "values", packed); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"big":   "0x18ee90ff6c373e0ee4e3f0ad2",
+		"small": uint64(42),
+		"list":  []interface{}{"-0x1", "0x10"},
+		"addr":  common.Address{1},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("unpacked map mismatch: have %v, want %v", v, want)
+	}
+}
+
+func TestUnpackOrdered(t *testing.T) {
+	const definition = `[{"name":"values","constant":true,"outputs":[{"type":"uint256","name":"zeta"},{"type":"bool","name":"alpha"},{"type":"address","name":"mid"}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed, err := abi.Methods["values"].Outputs.Pack(big.NewInt(7), true, common.Address{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := abi.UnpackOrdered("values", packed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NamedValue{
+		{"zeta", big.NewInt(7)},
+		{"alpha", true},
+		{"mid", common.Address{1}},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("unpacked values mismatch: have %v, want %v", values, want)
+	}
+}
+
+// Tests that the unsized int and uint aliases decode exactly like their 256 bit
+// counterparts, both into Go values and into struct fields.
+func TestUnpackIntAliases(t *testing.T) {
+	const definition = `[
+		{"name":"short","constant":true,"inputs":[{"type":"uint","name":"a"}],"outputs":[{"type":"int","name":"Signed"},{"type":"uint[]","name":"Values"},{"type":"tuple","name":"Pair","components":[{"type":"uint","name":"x"},{"type":"int","name":"y"}]}]},
+		{"name":"long","constant":true,"inputs":[{"type":"uint256","name":"a"}],"outputs":[{"type":"int256","name":"Signed"},{"type":"uint256[]","name":"Values"},{"type":"tuple","name":"Pair","components":[{"type":"uint256","name":"x"},{"type":"int256","name":"y"}]}]}
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	short, long := abi.Methods["short"], abi.Methods["long"]
+	if have, want := short.Sig(), "short(uint256)"; have != want {
+		t.Errorf("signature mismatch: have %s, want %s", have, want)
+	}
+	for i := range short.Outputs {
+		if have, want := short.Outputs[i].Type.String(), long.Outputs[i].Type.String(); have != want {
+			t.Errorf("output %d: type mismatch: have %s, want %s", i, have, want)
+		}
+	}
+	type pair struct {
+		X *big.Int
+		Y *big.Int
+	}
+	packed, err := long.Outputs.Pack(big.NewInt(-5), []*big.Int{big.NewInt(1), big.NewInt(2)}, pair{big.NewInt(3), big.NewInt(-4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want struct {
+		Signed *big.Int
+		Values []*big.Int
+		Pair   pair
+	}
+	if err := abi.Unpack(&have, "short", packed); err != nil {
+		t.Fatalf("failed to unpack aliased outputs: %v", err)
+	}
+	if err := abi.Unpack(&want, "long", packed); err != nil {
+		t.Fatalf("failed to unpack canonical outputs: %v", err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("unpacked outputs mismatch: have %+v, want %+v", have, want)
+	}
+	if have.Signed.Int64() != -5 || have.Pair.Y.Int64() != -4 {
+		t.Errorf("signed values mismatch: have %v and %v", have.Signed, have.Pair.Y)
+	}
+}
+
+const reuseDefinition = `[
+	{"type":"event","name":"Transfer","inputs":[
+		{"type":"address","name":"from"},{"type":"int256","name":"delta"},{"type":"uint8","name":"kind"},
+		{"type":"uint256[]","name":"amounts"},{"type":"bytes","name":"memo"},{"type":"string","name":"note"},
+		{"type":"bytes4[2]","name":"tags"},{"type":"tuple[]","name":"legs","components":[{"type":"address","name":"to"},{"type":"uint256","name":"value"}]}
+	]}
+]`
+
+type reuseLeg struct {
+	To    common.Address
+	Value *big.Int
+}
+
+type reuseTransfer struct {
+	From    common.Address
+	Delta   *big.Int
+	Kind    uint8
+	Amounts []*big.Int
+	Memo    []byte
+	Note    string
+	Tags    [2][4]byte
+	Legs    []reuseLeg
+}
+
+// packReuseTransfer packs a Transfer event payload with n amounts and legs.
+func packReuseTransfer(abi ABI, n int) ([]byte, error) {
+	amounts := make([]*big.Int, n)
+	legs := make([]reuseLeg, n)
+	for i := 0; i < n; i++ {
+		amounts[i] = new(big.Int).Lsh(big.NewInt(int64(i+1)), 100)
+		legs[i] = reuseLeg{To: common.BigToAddress(big.NewInt(int64(i))), Value: big.NewInt(int64(i * 7))}
+	}
+	return abi.Events["Transfer"].Inputs.Pack(common.HexToAddress("0x01"), big.NewInt(int64(-n)), uint8(n),
+		amounts, bytes.Repeat([]byte{byte(n)}, n), strings.Repeat("x", n), [2][4]byte{{1, 2, 3, 4}, {byte(n)}}, legs)
+}
+
+func TestUnpackReuse(t *testing.T) {
+	abi, err := JSON(strings.NewReader(reuseDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reused reuseTransfer
+	for i, n := range []int{5, 2, 0, 8} {
+		packed, err := packReuseTransfer(abi, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want reuseTransfer
+		if err := abi.Unpack(&want, "Transfer", packed); err != nil {
+			t.Fatalf("test %d: failed to unpack: %v", i, err)
+		}
+		prevAmounts, prevMemo := reused.Amounts, reused.Memo
+		if err := abi.UnpackReuse(&reused, "Transfer", packed); err != nil {
+			t.Fatalf("test %d: failed to unpack with reuse: %v", i, err)
+		}
+		// Unpack yields empty slices, compare lengths and contents only
+		if len(want.Amounts) == 0 && len(reused.Amounts) == 0 {
+			want.Amounts, want.Legs, want.Memo = reused.Amounts, reused.Legs, reused.Memo
+		}
+		if !reflect.DeepEqual(reused, want) {
+			t.Errorf("test %d: unpacked value mismatch: have %+v, want %+v", i, reused, want)
+		}
+		// Shrinking destinations must keep their backing arrays
+		if n <= cap(prevAmounts) && n > 0 && &reused.Amounts[0] != &prevAmounts[:1][0] {
+			t.Errorf("test %d: amounts slice reallocated", i)
+		}
+		if n <= cap(prevMemo) && n > 0 && &reused.Memo[0] != &prevMemo[:1][0] {
+			t.Errorf("test %d: memo slice reallocated", i)
+		}
+		// Decoded bytes must not alias the input, unlike with Unpack
+		for j := range packed {
+			packed[j] = 0xff
+		}
+		if memo := bytes.Repeat([]byte{byte(n)}, n); !bytes.Equal(reused.Memo, memo) {
+			t.Errorf("test %d: memo aliases input: have %x, want %x", i, reused.Memo, memo)
+		}
+	}
+	if err := abi.UnpackReuse(reused, "Transfer", []byte{0}); err == nil {
+		t.Errorf("expected error for non-pointer destination")
+	}
+}
+
+func BenchmarkUnpackReuse(b *testing.B) {
+	abi, err := JSON(strings.NewReader(reuseDefinition))
+	if err != nil {
+		b.Fatal(err)
+	}
+	packed, err := packReuseTransfer(abi, 16)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("unpack", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v reuseTransfer
+			if err := abi.Unpack(&v, "Transfer", packed); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("reuse", func(b *testing.B) {
+		b.ReportAllocs()
+		var v reuseTransfer
+		for i := 0; i < b.N; i++ {
+			if err := abi.UnpackReuse(&v, "Transfer", packed); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// Tests that the unsized fixed and ufixed aliases decode exactly like their
+// 128x18 counterparts, yielding the raw scaled integer values.
+func TestUnpackFixedAliases(t *testing.T) {
+	const definition = `[
+		{"name":"short","constant":true,"inputs":[{"type":"fixed","name":"a"}],"outputs":[{"type":"fixed","name":"Signed"},{"type":"ufixed","name":"Unsigned"},{"type":"fixed[]","name":"Values"}]},
+		{"name":"long","constant":true,"inputs":[{"type":"fixed128x18","name":"a"}],"outputs":[{"type":"fixed128x18","name":"Signed"},{"type":"ufixed128x18","name":"Unsigned"},{"type":"fixed128x18[]","name":"Values"}]}
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	short, long := abi.Methods["short"], abi.Methods["long"]
+	if have, want := short.Sig(), "short(fixed128x18)"; have != want {
+		t.Errorf("signature mismatch: have %s, want %s", have, want)
+	}
+	for i := range short.Outputs {
+		if have, want := short.Outputs[i].Type.String(), long.Outputs[i].Type.String(); have != want {
+			t.Errorf("output %d: type mismatch: have %s, want %s", i, have, want)
+		}
+	}
+	// -1.5 and 2.25 scaled by the 18 decimals
+	signed, _ := new(big.Int).SetString("-1500000000000000000", 10)
+	unsigned, _ := new(big.Int).SetString("2250000000000000000", 10)
+
+	// Packing converts negative values to two's complement in place, pack copies
+	packed, err := long.Outputs.Pack(new(big.Int).Set(signed), unsigned, []*big.Int{big.NewInt(1), big.NewInt(-2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want struct {
+		Signed   *big.Int
+		Unsigned *big.Int
+		Values   []*big.Int
+	}
+	if err := abi.Unpack(&have, "short", packed); err != nil {
+		t.Fatalf("failed to unpack aliased outputs: %v", err)
+	}
+	if err := abi.Unpack(&want, "long", packed); err != nil {
+		t.Fatalf("failed to unpack canonical outputs: %v", err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("unpacked outputs mismatch: have %+v, want %+v", have, want)
+	}
+	if have.Signed.Cmp(signed) != 0 || have.Unsigned.Cmp(unsigned) != 0 || have.Values[1].Int64() != -2 {
+		t.Errorf("fixed point values mismatch: have %v, %v and %v", have.Signed, have.Unsigned, have.Values)
+	}
+}
+
+func TestUnpackPartial(t *testing.T) {
+	const definition = `[{"name":"values","constant":true,"outputs":[{"type":"uint256","name":"first"},{"type":"bool","name":"second"},{"type":"string","name":"third"}]}]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := abi.Methods["values"].Outputs
+	packed, err := outputs.Pack(big.NewInt(7), true, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Complete data should decode without errors
+	values, err := outputs.UnpackPartial(packed)
+	if err != nil {
+		t.Fatalf("failed to unpack complete data: %v", err)
+	}
+	if want := []interface{}{big.NewInt(7), true, "hello"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("unpacked values mismatch: have %v, want %v", values, want)
+	}
+	// Truncated data should decode the leading arguments and report the failing one
+	values, err = outputs.UnpackPartial(packed[:40])
+	perr, ok := err.(*PartialDataError)
+	if !ok {
+		t.Fatalf("expected partial data error, got %v", err)
+	}
+	if perr.Index != 1 || perr.Name != "second" || perr.Type != "bool" || perr.Length != 40 {
+		t.Errorf("error mismatch: have %+v", perr)
+	}
+	if want := []interface{}{big.NewInt(7)}; !reflect.DeepEqual(values, want) {
+		t.Errorf("partial values mismatch: have %v, want %v", values, want)
+	}
+	// Truncated dynamic data should fail on the dynamic argument
+	values, err = outputs.UnpackPartial(packed[:len(packed)-32])
+	if perr, ok := err.(*PartialDataError); !ok || perr.Index != 2 {
+		t.Fatalf("expected partial data error on argument 2, got %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("partial values count mismatch: have %d, want 2", len(values))
+	}
+}
+
 func TestOOMMaliciousInput(t *testing.T) {
 	oomTests := []unpackTest{
 		{
@@ -92,6 +92,11 @@ func NewPlaintextKeyStore(keydir string) *KeyStore {
 	return ks
 }
 
+// Dir returns the directory the keystore stores its key files in.
+func (ks *KeyStore) Dir() string {
+	return ks.storage.JoinPath("")
+}
+
 func (ks *KeyStore) init(keydir string) {
 	// Lock the mutex since the account cache might call back with events
 	ks.mu.Lock()
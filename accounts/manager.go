@@ -130,8 +130,25 @@ func (am *Manager) update() {
 	}
 }
 
+// AddBackend registers an additional backend with the account manager at runtime,
+// merging its wallets into the cache and subscribing to its wallet notifications.
+func (am *Manager) AddBackend(backend Backend) {
+	wallets := backend.Wallets()
+
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	kind := reflect.TypeOf(backend)
+	am.backends[kind] = append(am.backends[kind], backend)
+	am.updaters = append(am.updaters, backend.Subscribe(am.updates))
+	am.wallets = merge(am.wallets, wallets...)
+}
+
 // Backends retrieves the backend(s) with the given type from the account manager.
 func (am *Manager) Backends(kind reflect.Type) []Backend {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
 	return am.backends[kind]
 }
 
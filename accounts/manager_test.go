@@ -0,0 +1,65 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ccmchain/go-ccmchain/event"
+)
+
+// testBackend is an account backend without any wallets.
+type testBackend struct{}
+
+func (testBackend) Wallets() []Wallet { return nil }
+
+func (testBackend) Subscribe(sink chan<- WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// Tests that backends can be added at runtime while others are being looked up.
+func TestManagerConcurrentAddBackend(t *testing.T) {
+	am := NewManager(&Config{})
+	defer am.Close()
+
+	kind := reflect.TypeOf(testBackend{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			am.AddBackend(testBackend{})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			am.Backends(kind)
+		}
+	}()
+	wg.Wait()
+
+	if n := len(am.Backends(kind)); n != 100 {
+		t.Fatalf("backend count mismatch: have %d, want 100", n)
+	}
+}
@@ -30,11 +30,14 @@ import (
 
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
 	"github.com/ccmchain/go-ccmchain/core"
 	"github.com/ccmchain/go-ccmchain/core/rawdb"
 	"github.com/ccmchain/go-ccmchain/core/state"
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
+	"github.com/ccmchain/go-ccmchain/log"
+	"github.com/ccmchain/go-ccmchain/params"
 	"github.com/ccmchain/go-ccmchain/rlp"
 	"github.com/ccmchain/go-ccmchain/rpc"
 	"github.com/ccmchain/go-ccmchain/trie"
@@ -75,6 +78,57 @@ func (api *PublicCcmchainAPI) ChainId() hexutil.Uint64 {
 	return (hexutil.Uint64)(chainID.Uint64())
 }
 
+// BalanceSample is the balance of an account at a specific block.
+type BalanceSample struct {
+	Number  hexutil.Uint64 `json:"number"`
+	Balance *hexutil.Big   `json:"balance"`
+}
+
+// BalanceHistory returns the balance of addr at every step-th block between from
+// and to (inclusive), defaulting to every block if no step is given. Sampling
+// blocks older than the recent past requires an archive node.
+func (api *PublicCcmchainAPI) BalanceHistory(ctx context.Context, addr common.Address, from, to hexutil.Uint64, step *hexutil.Uint64) ([]BalanceSample, error) {
+	interval := uint64(1)
+	if step != nil && *step > 0 {
+		interval = uint64(*step)
+	}
+	balances, err := api.e.APIBackend.BalanceHistory(ctx, addr, uint64(from), uint64(to), interval)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]BalanceSample, len(balances))
+	for i, balance := range balances {
+		samples[i] = BalanceSample{
+			Number:  hexutil.Uint64(uint64(from) + uint64(i)*interval),
+			Balance: (*hexutil.Big)(balance),
+		}
+	}
+	return samples, nil
+}
+
+// maxStorageRangeResults is the maximum number of storage slots returned by a
+// single GetStorageRangeAt call.
+const maxStorageRangeResults = 1024
+
+// GetStorageRangeAt returns a page of at most maxResult storage slots of the given
+// contract at the requested block, starting at the (hashed) key keyStart. The
+// returned NextKey can be used as the start of the next page, and is nil once
+// the end of the storage has been reached.
+func (api *PublicCcmchainAPI) GetStorageRangeAt(ctx context.Context, contractAddress common.Address, blockNr rpc.BlockNumber, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
+	if maxResult <= 0 || maxResult > maxStorageRangeResults {
+		return StorageRangeResult{}, fmt.Errorf("invalid result limit %d, must be between 1 and %d", maxResult, maxStorageRangeResults)
+	}
+	statedb, _, err := api.e.APIBackend.StateAndHeaderByNumber(ctx, blockNr)
+	if statedb == nil || err != nil {
+		return StorageRangeResult{}, err
+	}
+	st := statedb.StorageTrie(contractAddress)
+	if st == nil {
+		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+	}
+	return storageRangeAt(st, keyStart, maxResult)
+}
+
 // PublicMinerAPI provides an API to control the miner.
 // It offers only mccmods that operate on data that pose no security risk when it is publicly accessible.
 type PublicMinerAPI struct {
@@ -144,6 +198,38 @@ func (api *PrivateMinerAPI) SetCcmchainbase(ccmerbase common.Address) bool {
 	return true
 }
 
+// SetMaxUncles sets the maximum number of uncles included in mined blocks.
+func (api *PrivateMinerAPI) SetMaxUncles(count int) (bool, error) {
+	if err := api.e.Miner().SetMaxUncles(count); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetWorkSubmissionTimeout updates how long (in milliseconds) work packages handed
+// out to remote miners are retained awaiting a solution. Zero reverts to the
+// default chain depth based retention.
+func (api *PrivateMinerAPI) SetWorkSubmissionTimeout(timeout int) (bool, error) {
+	engine, ok := api.e.engine.(*ccmash.Ethash)
+	if !ok {
+		return false, errors.New("remote work submission requires ccmash")
+	}
+	if err := engine.SetWorkTimeout(time.Duration(timeout) * time.Millisecond); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StaleWorkCount returns the number of previously handed out work packages still
+// tracked awaiting a solution from remote miners.
+func (api *PrivateMinerAPI) StaleWorkCount() (int, error) {
+	engine, ok := api.e.engine.(*ccmash.Ethash)
+	if !ok {
+		return 0, errors.New("remote work submission requires ccmash")
+	}
+	return engine.StaleWorks()
+}
+
 // SetRecommitInterval updates the interval for miner sealing work recommitting.
 func (api *PrivateMinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
@@ -166,6 +252,30 @@ func NewPrivateAdminAPI(ccm *Ccmchain) *PrivateAdminAPI {
 	return &PrivateAdminAPI{ccm: ccm}
 }
 
+// PeerChainInfo returns the announced head, total difficulty and negotiated
+// protocol version of every connected Ccmchain peer.
+func (api *PrivateAdminAPI) PeerChainInfo() []*PeerChainInfo {
+	return api.ccm.PeerInfo()
+}
+
+// PauseSync aborts the running chain synchronisation, if any, and prevents new
+// ones from starting until ResumeSync is called. It returns false if syncing
+// was already paused.
+func (api *PrivateAdminAPI) PauseSync() bool {
+	return api.ccm.Downloader().Pause()
+}
+
+// ResumeSync allows chain synchronisation to start again after a PauseSync. It
+// returns false if syncing was not paused.
+func (api *PrivateAdminAPI) ResumeSync() bool {
+	return api.ccm.Downloader().Resume()
+}
+
+// SyncPaused reports whether chain synchronisation is currently paused.
+func (api *PrivateAdminAPI) SyncPaused() bool {
+	return api.ccm.Downloader().Paused()
+}
+
 // ExportChain exports the current blockchain into a local file.
 func (api *PrivateAdminAPI) ExportChain(file string) (bool, error) {
 	// Make sure we can create the file to export into
@@ -188,6 +298,75 @@ func (api *PrivateAdminAPI) ExportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// ExportedBlock is a single RLP encoded block streamed by ExportChainStream. If
+// the block could not be exported, Error is set instead of Rlp.
+type ExportedBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+	Rlp    hexutil.Bytes  `json:"rlp,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// ExportChainStream streams the canonical blocks in the range [first, last] back
+// over the RPC connection, one block per notification, so that the chain can be
+// exported without access to the node's filesystem. The range defaults to the
+// whole chain. The notification of block last marks the end of the export, as
+// does a notification carrying an error, after which no more blocks are sent.
+func (api *PrivateAdminAPI) ExportChainStream(ctx context.Context, first, last *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var (
+		head  = api.ccm.BlockChain().CurrentBlock().NumberU64()
+		start = uint64(0)
+		end   = head
+	)
+	if first != nil {
+		start = uint64(*first)
+	}
+	if last != nil {
+		end = uint64(*last)
+	}
+	if start > end {
+		return nil, fmt.Errorf("export failed: first (%d) is greater than last (%d)", start, end)
+	}
+	if end > head {
+		return nil, fmt.Errorf("export failed: last (%d) is beyond the current head (%d)", end, head)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		log.Info("Streaming batch of blocks", "count", end-start+1)
+		for nr := start; nr <= end; nr++ {
+			select {
+			case <-rpcSub.Err():
+				log.Warn("Block export aborted", "number", nr)
+				return
+			case <-notifier.Closed():
+				return
+			default:
+			}
+			// Retrieve and encode a single block at a time to keep memory bounded
+			block := api.ccm.BlockChain().GetBlockByNumber(nr)
+			if block == nil {
+				log.Error("Block export failed", "number", nr, "err", "not found")
+				notifier.Notify(rpcSub.ID, &ExportedBlock{Number: hexutil.Uint64(nr), Error: "block not found"})
+				return
+			}
+			enc, err := rlp.EncodeToBytes(block)
+			if err != nil {
+				log.Error("Block export failed", "number", nr, "err", err)
+				notifier.Notify(rpcSub.ID, &ExportedBlock{Number: hexutil.Uint64(nr), Error: err.Error()})
+				return
+			}
+			if err := notifier.Notify(rpcSub.ID, &ExportedBlock{Number: hexutil.Uint64(nr), Rlp: enc}); err != nil {
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
@@ -296,6 +475,31 @@ func NewPrivateDebugAPI(ccm *Ccmchain) *PrivateDebugAPI {
 	return &PrivateDebugAPI{ccm: ccm}
 }
 
+// RebuildBloomBits re-derives the bloom bits index of the given range of already
+// indexed sections (inclusive) from the canonical headers, overwriting the stored
+// data. It returns the number of indexed sections after the rebuild.
+func (api *PrivateDebugAPI) RebuildBloomBits(ctx context.Context, fromSection, toSection hexutil.Uint64) (hexutil.Uint64, error) {
+	if fromSection > toSection {
+		return 0, fmt.Errorf("invalid section range: from %d > to %d", fromSection, toSection)
+	}
+	sections, _, _ := api.ccm.bloomIndexer.Sections()
+	if uint64(toSection) >= sections {
+		return 0, fmt.Errorf("section %d not yet indexed, %d sections available", toSection, sections)
+	}
+	for section := uint64(fromSection); section <= uint64(toSection); section++ {
+		if err := rebuildBloomSection(ctx, api.ccm.chainDb, api.ccm.blockchain, api.ccm.bloomIndexer, params.BloomBitsBlocks, section); err != nil {
+			return 0, fmt.Errorf("failed to rebuild section %d: %v", section, err)
+		}
+		log.Info("Rebuilt bloombits section", "section", section)
+	}
+	// Ensure the indexer didn't lose track of any sections meanwhile
+	after, _, _ := api.ccm.bloomIndexer.Sections()
+	if after < sections {
+		return hexutil.Uint64(after), fmt.Errorf("indexed sections dropped from %d to %d during rebuild", sections, after)
+	}
+	return hexutil.Uint64(after), nil
+}
+
 // Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
 func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	if preimage := rawdb.ReadPreimage(api.ccm.ChainDb(), hash); preimage != nil {
@@ -304,6 +508,16 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 	return nil, errors.New("unknown preimage")
 }
 
+// GetTrieNode is a debug API function that returns the RLP encoded trie node with
+// the given hash from the state database, if known.
+func (api *PrivateDebugAPI) GetTrieNode(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	node, err := api.ccm.BlockChain().StateCache().TrieDB().Node(hash)
+	if err != nil {
+		return nil, fmt.Errorf("trie node %x not found: %v", hash, err)
+	}
+	return node, nil
+}
+
 // BadBlockArgs represents the entries in the list returned when bad blocks are queried.
 type BadBlockArgs struct {
 	Hash  common.Hash            `json:"hash"`
@@ -400,7 +614,8 @@ func (api *PrivateDebugAPI) AccountRange(ctx context.Context, start *common.Hash
 	return accountRange(trie, start, maxResults)
 }
 
-// StorageRangeResult is the result of a debug_storageRangeAt API call.
+// StorageRangeResult is the result of a debug_storageRangeAt or ccm_getStorageRangeAt
+// API call.
 type StorageRangeResult struct {
 	Storage storageMap   `json:"storage"`
 	NextKey *common.Hash `json:"nextKey"` // nil if Storage includes the last key in the trie.
@@ -455,26 +670,36 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 //
 // With one parameter, returns the list of accounts modified in the specified block.
 func (api *PrivateDebugAPI) GetModifiedAccountsByNumber(startNum uint64, endNum *uint64) ([]common.Address, error) {
+	startBlock, endBlock, err := api.blockRangeByNumber(startNum, endNum)
+	if err != nil {
+		return nil, err
+	}
+	return api.getModifiedAccounts(startBlock, endBlock)
+}
+
+// blockRangeByNumber resolves the start and end blocks of a block range given by
+// number. Without an end, the range spans the specified block and its parent.
+func (api *PrivateDebugAPI) blockRangeByNumber(startNum uint64, endNum *uint64) (*types.Block, *types.Block, error) {
 	var startBlock, endBlock *types.Block
 
 	startBlock = api.ccm.blockchain.GetBlockByNumber(startNum)
 	if startBlock == nil {
-		return nil, fmt.Errorf("start block %x not found", startNum)
+		return nil, nil, fmt.Errorf("start block %x not found", startNum)
 	}
 
 	if endNum == nil {
 		endBlock = startBlock
 		startBlock = api.ccm.blockchain.GetBlockByHash(startBlock.ParentHash())
 		if startBlock == nil {
-			return nil, fmt.Errorf("block %x has no parent", endBlock.Number())
+			return nil, nil, fmt.Errorf("block %x has no parent", endBlock.Number())
 		}
 	} else {
 		endBlock = api.ccm.blockchain.GetBlockByNumber(*endNum)
 		if endBlock == nil {
-			return nil, fmt.Errorf("end block %d not found", *endNum)
+			return nil, nil, fmt.Errorf("end block %d not found", *endNum)
 		}
 	}
-	return api.getModifiedAccounts(startBlock, endBlock)
+	return startBlock, endBlock, nil
 }
 
 // GetModifiedAccountsByHash returns all accounts that have changed between the
@@ -531,3 +756,137 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	}
 	return dirty, nil
 }
+
+// BalanceDiff is the balance of an account before and after a state transition.
+type BalanceDiff struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+// NonceDiff is the nonce of an account before and after a state transition.
+type NonceDiff struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// HashDiff is a code hash or storage value before and after a state transition.
+type HashDiff struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// AccountDiff describes how an account changed between two blocks. Storage slots
+// are keyed by their preimage if known, or by their hashed key otherwise.
+type AccountDiff struct {
+	Balance  BalanceDiff              `json:"balance"`
+	Nonce    NonceDiff                `json:"nonce"`
+	CodeHash HashDiff                 `json:"codeHash"`
+	Storage  map[common.Hash]HashDiff `json:"storage"`
+}
+
+// StateDiff returns the before and after balance, nonce, code hash and changed
+// storage slots of every account modified between the two blocks specified.
+//
+// With one parameter, returns the changes made by the specified block.
+func (api *PrivateDebugAPI) StateDiff(startNum uint64, endNum *uint64) (map[common.Address]*AccountDiff, error) {
+	startBlock, endBlock, err := api.blockRangeByNumber(startNum, endNum)
+	if err != nil {
+		return nil, err
+	}
+	dirty, err := api.getModifiedAccounts(startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	oldState, err := api.ccm.BlockChain().StateAt(startBlock.Root())
+	if err != nil {
+		return nil, err
+	}
+	newState, err := api.ccm.BlockChain().StateAt(endBlock.Root())
+	if err != nil {
+		return nil, err
+	}
+	diffs := make(map[common.Address]*AccountDiff, len(dirty))
+	for _, addr := range dirty {
+		storage, err := storageDiff(oldState.StorageTrie(addr), newState.StorageTrie(addr))
+		if err != nil {
+			return nil, err
+		}
+		diffs[addr] = &AccountDiff{
+			Balance:  BalanceDiff{From: (*hexutil.Big)(oldState.GetBalance(addr)), To: (*hexutil.Big)(newState.GetBalance(addr))},
+			Nonce:    NonceDiff{From: hexutil.Uint64(oldState.GetNonce(addr)), To: hexutil.Uint64(newState.GetNonce(addr))},
+			CodeHash: HashDiff{From: oldState.GetCodeHash(addr), To: newState.GetCodeHash(addr)},
+			Storage:  storage,
+		}
+	}
+	return diffs, nil
+}
+
+// storageDiff returns the slots that differ between two storage tries, either
+// of which may be nil for a non-existent account.
+func storageDiff(oldTrie, newTrie state.Trie) (map[common.Hash]HashDiff, error) {
+	// Collect the slots of each trie that are missing from or differ in the other
+	oldSlots, err := storageDifference(newTrie, oldTrie)
+	if err != nil {
+		return nil, err
+	}
+	newSlots, err := storageDifference(oldTrie, newTrie)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make(map[common.Hash]HashDiff)
+	for hash, slot := range oldSlots {
+		diffs[slot.key] = HashDiff{From: slot.value, To: newSlots[hash].value}
+	}
+	for hash, slot := range newSlots {
+		if _, ok := oldSlots[hash]; !ok {
+			diffs[slot.key] = HashDiff{To: slot.value}
+		}
+	}
+	return diffs, nil
+}
+
+// storageSlot is a decoded storage trie entry along with its preimage key, or
+// its hashed key if the preimage is unknown.
+type storageSlot struct {
+	key   common.Hash
+	value common.Hash
+}
+
+// storageDifference returns the slots of trie b that are not present with the
+// same value in trie a, keyed by their hashed key.
+func storageDifference(a, b state.Trie) (map[common.Hash]storageSlot, error) {
+	slots := make(map[common.Hash]storageSlot)
+	if b == nil {
+		return slots, nil
+	}
+	it := b.NodeIterator(nil)
+	if a != nil {
+		it, _ = trie.NewDifferenceIterator(a.NodeIterator(nil), it)
+	}
+	iter := trie.NewIterator(it)
+	for iter.Next() {
+		value, err := decodeStorageValue(iter.Value)
+		if err != nil {
+			return nil, err
+		}
+		slot := storageSlot{key: common.BytesToHash(iter.Key), value: value}
+		if preimage := b.GetKey(iter.Key); preimage != nil {
+			slot.key = common.BytesToHash(preimage)
+		}
+		slots[common.BytesToHash(iter.Key)] = slot
+	}
+	return slots, iter.Err
+}
+
+// decodeStorageValue decodes an RLP encoded storage trie value, treating an
+// empty value as zero.
+func decodeStorageValue(enc []byte) (common.Hash, error) {
+	if len(enc) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
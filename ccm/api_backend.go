@@ -19,7 +19,9 @@ package ccm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ccmchain/go-ccmchain/accounts"
 	"github.com/ccmchain/go-ccmchain/common"
@@ -31,9 +33,11 @@ import (
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/core/vm"
 	"github.com/ccmchain/go-ccmchain/ccm/downloader"
+	"github.com/ccmchain/go-ccmchain/ccm/filters"
 	"github.com/ccmchain/go-ccmchain/ccm/gasprice"
 	"github.com/ccmchain/go-ccmchain/ccmdb"
 	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
 	"github.com/ccmchain/go-ccmchain/params"
 	"github.com/ccmchain/go-ccmchain/rpc"
 )
@@ -43,6 +47,8 @@ type EthAPIBackend struct {
 	extRPCEnabled bool
 	ccm           *Ccmchain
 	gpo           *gasprice.Oracle
+	requests      *ccmapi.RequestRegistry
+	states        *ccmapi.StateReaders
 }
 
 // ChainConfig returns the active chain configuration.
@@ -50,6 +56,12 @@ func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
 	return b.ccm.blockchain.Config()
 }
 
+// ChainConfigFull returns the active chain configuration along with its fork
+// schedule relative to the current head.
+func (b *EthAPIBackend) ChainConfigFull() *ccmapi.ChainConfigFull {
+	return ccmapi.NewChainConfigFull(b.ChainConfig(), b.CurrentBlock().NumberU64())
+}
+
 func (b *EthAPIBackend) CurrentBlock() *types.Block {
 	return b.ccm.blockchain.CurrentBlock()
 }
@@ -59,7 +71,19 @@ func (b *EthAPIBackend) SetHead(number uint64) {
 	b.ccm.blockchain.SetHead(number)
 }
 
+// resolveConfirmed converts the safe and finalized block tags into the number of
+// the canonical block they currently refer to.
+func (b *EthAPIBackend) resolveConfirmed(number rpc.BlockNumber) rpc.BlockNumber {
+	if number != rpc.SafeBlockNumber && number != rpc.FinalizedBlockNumber {
+		return number
+	}
+	head := b.ccm.blockchain.CurrentHeader()
+	return ccmapi.ResolveConfirmedNumber(b.ccm.engine, b.ccm.blockchain, head, number, b.ccm.config.SafeDepth, b.ccm.config.FinalityDepth)
+}
+
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	number = b.resolveConfirmed(number)
+
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block := b.ccm.miner.PendingBlock()
@@ -72,11 +96,24 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	return b.ccm.blockchain.GetHeaderByNumber(uint64(number)), nil
 }
 
+// HeaderByHash retrieves a header by its hash. Besides the chain, the miner's
+// pending block is also consulted to keep hash based lookups consistent with the
+// number based ones. Note, the pending block is replaced whenever new transactions
+// arrive or a new head is imported, so its hash may not resolve anymore by the
+// time a caller looks it up.
 func (b *EthAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
-	return b.ccm.blockchain.GetHeaderByHash(hash), nil
+	if header := b.ccm.blockchain.GetHeaderByHash(hash); header != nil {
+		return header, nil
+	}
+	if block := b.ccm.miner.PendingBlock(); block != nil && block.Hash() == hash {
+		return block.Header(), nil
+	}
+	return nil, nil
 }
 
 func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	number = b.resolveConfirmed(number)
+
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block := b.ccm.miner.PendingBlock()
@@ -107,6 +144,102 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	return stateDb, header, err
 }
 
+// StatesAndHeaderByNumber opens n independent states of the same block, which
+// can be used concurrently, e.g. to execute multiple calls in parallel. The
+// states count against the limit of open states until released.
+func (b *EthAPIBackend) StatesAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber, n int) ([]*state.StateDB, *types.Header, func(), error) {
+	release, err := b.states.Acquire(ctx, n)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Pending state is only known by the miner, copy it for every reader
+	if number == rpc.PendingBlockNumber {
+		block, pending := b.ccm.miner.Pending()
+		states := make([]*state.StateDB, n)
+		for i := range states {
+			states[i] = pending.Copy()
+		}
+		return states, block.Header(), release, nil
+	}
+	// Otherwise resolve the block number and open its states
+	header, err := b.HeaderByNumber(ctx, number)
+	if err == nil && header == nil {
+		err = errors.New("header not found")
+	}
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	states, err := b.ccm.BlockChain().StatesAt(header.Root, n)
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return states, header, release, nil
+}
+
+// GetCodeSize returns the size of the code deployed at the given address, which
+// is served from the code size cache without copying the code if possible.
+func (b *EthAPIBackend) GetCodeSize(ctx context.Context, addr common.Address, number rpc.BlockNumber) (int, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	size := state.GetCodeSize(addr)
+	return size, state.Error()
+}
+
+// BlockRange returns the lowest and highest block numbers whose full block data
+// is available locally. Block bodies are assumed to be available contiguously up
+// to the head, so the lowest one is found by a binary search. The genesis body is
+// always present, so it is left out of the search and only reported if block 1
+// is available too.
+func (b *EthAPIBackend) BlockRange() (uint64, uint64) {
+	var (
+		db   = b.ccm.ChainDb()
+		head = b.ccm.blockchain.CurrentBlock().NumberU64()
+	)
+	lowest := 1 + uint64(sort.Search(int(head), func(i int) bool {
+		n := uint64(i + 1)
+		hash := rawdb.ReadCanonicalHash(db, n)
+		return hash != (common.Hash{}) && rawdb.HasBody(db, hash, n)
+	}))
+	if lowest == 1 {
+		lowest = 0
+	}
+	return lowest, head
+}
+
+// BalancesAt returns the balances of the given accounts in the state of the
+// requested block, resolving the state only once for all of them.
+func (b *EthAPIBackend) BalancesAt(ctx context.Context, addrs []common.Address, number rpc.BlockNumber) ([]*big.Int, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		balances[i] = state.GetBalance(addr)
+	}
+	return balances, state.Error()
+}
+
+// GetAccount returns the balance, nonce, code hash, code size and storage root of
+// the given account, all read from a single state of the given block.
+func (b *EthAPIBackend) GetAccount(ctx context.Context, addr common.Address, number rpc.BlockNumber) (*ccmapi.AccountInfo, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return ccmapi.ReadAccountInfo(state, addr)
+}
+
+// GasUsageStats calculates the gas usage statistics over the given number of most
+// recent canonical blocks, walking the locally stored headers backwards from the head.
+func (b *EthAPIBackend) GasUsageStats(ctx context.Context, blocks int) (*ccmapi.GasUsageStats, error) {
+	return ccmapi.ChainGasUsageStats(b.ccm.blockchain, blocks)
+}
+
 func (b *EthAPIBackend) GetHeader(ctx context.Context, hash common.Hash) *types.Header {
 	return b.ccm.blockchain.GetHeaderByHash(hash)
 }
@@ -115,12 +248,107 @@ func (b *EthAPIBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.
 	return b.ccm.blockchain.GetBlockByHash(hash), nil
 }
 
+// GetUncle returns the uncle header at the given index within the block with the
+// given hash, or nil if either the block or the uncle doesn't exist.
+func (b *EthAPIBackend) GetUncle(ctx context.Context, blockHash common.Hash, index int) (*types.Header, error) {
+	body := b.ccm.blockchain.GetBody(blockHash)
+	if body == nil || index < 0 || index >= len(body.Uncles) {
+		return nil, nil
+	}
+	return body.Uncles[index], nil
+}
+
+// BlockTransactionCountByNumber returns the number of transactions in the block
+// with the given number, reading it from the stored body without decoding it.
+func (b *EthAPIBackend) BlockTransactionCountByNumber(ctx context.Context, number rpc.BlockNumber) (int, error) {
+	// Pending block is only known by the miner
+	if number == rpc.PendingBlockNumber {
+		block := b.ccm.miner.PendingBlock()
+		if block == nil {
+			return 0, errors.New("pending block not available")
+		}
+		return len(block.Transactions()), nil
+	}
+	header, err := b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return 0, fmt.Errorf("block #%d not found", number)
+	}
+	return b.BlockTransactionCount(ctx, header.Hash())
+}
+
+// BlockTransactionCount returns the number of transactions in the block with
+// the given hash, reading it from the stored body without decoding it.
+func (b *EthAPIBackend) BlockTransactionCount(ctx context.Context, hash common.Hash) (int, error) {
+	number := rawdb.ReadHeaderNumber(b.ccm.ChainDb(), hash)
+	if number == nil {
+		return 0, fmt.Errorf("block %#x not found", hash)
+	}
+	count, ok := rawdb.ReadBodyTxCount(b.ccm.ChainDb(), hash, *number)
+	if !ok {
+		return 0, fmt.Errorf("block body %#x not found", hash)
+	}
+	return count, nil
+}
+
+// maxBalanceHistorySamples is the maximum number of blocks a single balance
+// history query is allowed to resolve state for.
+const maxBalanceHistorySamples = 1024
+
+// BalanceHistory returns the balance of addr at every step-th block of the
+// inclusive range [from, to], resolving the state of each sampled block exactly
+// once. The i-th balance belongs to block from+i*step.
+//
+// Non-archive nodes only retain the state of recent blocks, so sampling further
+// back in history requires the node to run with pruning disabled.
+func (b *EthAPIBackend) BalanceHistory(ctx context.Context, addr common.Address, from, to, step uint64) ([]*big.Int, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from, to)
+	}
+	if step == 0 {
+		step = 1
+	}
+	if samples := (to-from)/step + 1; samples > maxBalanceHistorySamples {
+		return nil, fmt.Errorf("too many samples requested: %d > %d", samples, maxBalanceHistorySamples)
+	}
+	var balances []*big.Int
+	for number := from; number <= to; number += step {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		header := b.ccm.blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		statedb, err := b.ccm.blockchain.StateAt(header.Root)
+		if err != nil {
+			return nil, fmt.Errorf("state of block #%d not available (archive node required): %v", number, err)
+		}
+		balances = append(balances, statedb.GetBalance(addr))
+
+		if number+step < number { // overflow, we're done
+			break
+		}
+	}
+	return balances, nil
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	return b.ccm.blockchain.GetReceiptsByHash(hash), nil
 }
 
+// GetReceiptsByTxHashes returns the minimal receipt info of each transaction,
+// with nil entries for the ones not found in the transaction index.
+func (b *EthAPIBackend) GetReceiptsByTxHashes(ctx context.Context, hashes []common.Hash) ([]*ccmapi.ReceiptInfo, error) {
+	return ccmapi.LookupReceiptInfos(ctx, b, hashes)
+}
+
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
-	receipts := b.ccm.blockchain.GetReceiptsByHash(hash)
+	var receipts types.Receipts
+	if b.ccm.logsDb == b.ccm.chainDb {
+		receipts = b.ccm.blockchain.GetReceiptsByHash(hash)
+	} else if number := rawdb.ReadHeaderNumber(b.ccm.logsDb, hash); number != nil {
+		receipts = rawdb.ReadReceipts(b.ccm.logsDb, hash, *number, b.ccm.blockchain.Config())
+	}
 	if receipts == nil {
 		return nil, nil
 	}
@@ -131,16 +359,78 @@ func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*typ
 	return logs, nil
 }
 
+// GetLogsInRange retrieves the logs of all blocks in the inclusive range [from, to]
+// matching the given address and topic criteria. The bloombits index is used to
+// skip over blocks that cannot contain matching logs.
+func (b *EthAPIBackend) GetLogsInRange(ctx context.Context, from, to uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from, to)
+	}
+	filter := filters.NewRangeFilter(b, int64(from), int64(to), addresses, topics)
+	filter.SetLimit(ccmapi.MaxLogsInRange)
+
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
 func (b *EthAPIBackend) GetTd(blockHash common.Hash) *big.Int {
 	return b.ccm.blockchain.GetTdByHash(blockHash)
 }
 
-func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
+// CurrentTd returns the total difficulty of the current head block.
+func (b *EthAPIBackend) CurrentTd() *big.Int {
+	head := b.ccm.blockchain.CurrentBlock()
+	return b.ccm.blockchain.GetTd(head.Hash(), head.NumberU64())
+}
+
+// GetEVM creates an EVM executing the message on top of the given state. The VM
+// configuration of the chain is used unless vmConfig overrides it.
+func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	if vmConfig == nil {
+		vmConfig = b.ccm.blockchain.GetVMConfig()
+	}
+	return b.newEVM(msg, state, header, b.ccm.blockchain.Config(), *vmConfig)
+}
+
+// GetEVMWithConfig creates an EVM executing the message under the rules of the
+// given chain config instead of the ones of the local chain.
+func (b *EthAPIBackend) GetEVMWithConfig(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig) (*vm.EVM, func() error, error) {
+	return b.newEVM(msg, state, header, config, *b.ccm.blockchain.GetVMConfig())
+}
+
+// newEVM creates an EVM for the message, funding the sender so that unpriced
+// calls don't run out of balance.
+func (b *EthAPIBackend) newEVM(msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig, vmConfig vm.Config) (*vm.EVM, func() error, error) {
 	state.SetBalance(msg.From(), math.MaxBig256)
 	vmError := func() error { return nil }
 
 	context := core.NewEVMContext(msg, header, b.ccm.BlockChain(), nil)
-	return vm.NewEVM(context, state, b.ccm.blockchain.Config(), *b.ccm.blockchain.GetVMConfig()), vmError, nil
+	return vm.NewEVM(context, state, config, vmConfig), vmError, nil
+}
+
+// CallBundle executes the given messages sequentially on top of the state of the
+// requested block, after applying the state overrides. Every message sees the
+// state changes of the ones before it.
+func (b *EthAPIBackend) CallBundle(ctx context.Context, msgs []core.Message, number rpc.BlockNumber, overrides ccmapi.StateOverride) ([]*ccmapi.BundleResult, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	overrides.Apply(state)
+	return ccmapi.ApplyBundle(ctx, b.ChainConfig(), b.ccm.blockchain, *b.ccm.blockchain.GetVMConfig(), msgs, state, header)
+}
+
+// ForkEffect executes the message on top of the state of the requested block
+// under both the current chain rules and those of the next scheduled fork.
+func (b *EthAPIBackend) ForkEffect(ctx context.Context, msg core.Message, number rpc.BlockNumber) (*ccmapi.ForkEffect, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return ccmapi.CompareForkRules(ctx, b, msg, state, header)
 }
 
 func (b *EthAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
@@ -155,6 +445,10 @@ func (b *EthAPIBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) e
 	return b.ccm.BlockChain().SubscribeChainHeadEvent(ch)
 }
 
+func (b *EthAPIBackend) SubscribeFinalityHeadEvent(ch chan<- core.FinalityHeadEvent) event.Subscription {
+	return ccmapi.NewFinalityHeadSubscription(b.SubscribeChainHeadEvent, b.ccm.engine, b.ccm.blockchain, b.ccm.config.FinalityDepth, ch)
+}
+
 func (b *EthAPIBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
 	return b.ccm.BlockChain().SubscribeChainSideEvent(ch)
 }
@@ -163,7 +457,14 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 	return b.ccm.BlockChain().SubscribeLogsEvent(ch)
 }
 
+func (b *EthAPIBackend) SubscribeAllLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.ccm.BlockChain().SubscribeAllLogsEvent(ch)
+}
+
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if min := b.ccm.config.RPCMinGasPrice; min != nil && signedTx.GasPrice().Cmp(min) < 0 {
+		return ccmapi.ErrRPCUnderpriced
+	}
 	return b.ccm.txPool.AddLocal(signedTx)
 }
 
@@ -179,10 +480,40 @@ func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	return txs, nil
 }
 
+// PendingFeeHistogram buckets the executable transactions of the pool by their
+// gas price, providing a live view of the competing prices.
+func (b *EthAPIBackend) PendingFeeHistogram(buckets int) ([]*ccmapi.FeeBucket, error) {
+	pending, err := b.ccm.txPool.Pending()
+	if err != nil {
+		return nil, err
+	}
+	var txs types.Transactions
+	for _, batch := range pending {
+		txs = append(txs, batch...)
+	}
+	return ccmapi.NewFeeHistogram(txs, buckets), nil
+}
+
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
 	return b.ccm.txPool.Get(hash)
 }
 
+func (b *EthAPIBackend) HasPoolTransaction(hash common.Hash) bool {
+	return b.ccm.txPool.Has(hash)
+}
+
+// PoolTransactionStatus returns whether the transaction is queued or pending in
+// the pool, or already included in the canonical chain.
+func (b *EthAPIBackend) PoolTransactionStatus(ctx context.Context, hash common.Hash) core.TxStatus {
+	if status := b.ccm.txPool.Status([]common.Hash{hash})[0]; status != core.TxStatusUnknown {
+		return status
+	}
+	if rawdb.ReadTxLookupEntry(b.ccm.ChainDb(), hash) != nil {
+		return core.TxStatusIncluded
+	}
+	return core.TxStatusUnknown
+}
+
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.ccm.ChainDb(), txHash)
 	return tx, blockHash, blockNumber, index, nil
@@ -192,6 +523,20 @@ func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (
 	return b.ccm.txPool.Nonce(addr), nil
 }
 
+// GetPoolNonceGap returns the next executable nonce of the account as next, and
+// whether queued transactions are blocked by the gap at that nonce as gapped.
+func (b *EthAPIBackend) GetPoolNonceGap(ctx context.Context, addr common.Address) (uint64, bool, error) {
+	next, gapped := b.ccm.txPool.NonceGap(addr)
+	return next, gapped, nil
+}
+
+// NextSendableNonce returns the nonce a new transaction needs to be immediately
+// executable. The pool nonce only advances over executable transactions, so it
+// already fills any gap before queued ones.
+func (b *EthAPIBackend) NextSendableNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return b.ccm.txPool.Nonce(addr), nil
+}
+
 func (b *EthAPIBackend) Stats() (pending int, queued int) {
 	return b.ccm.txPool.Stats()
 }
@@ -200,10 +545,24 @@ func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.ccm.TxPool().Content()
 }
 
+// ImportTxPool adds the transactions to the pool as local ones, returning the
+// error of each one.
+func (b *EthAPIBackend) ImportTxPool(ctx context.Context, txs types.Transactions) []error {
+	return b.ccm.TxPool().AddLocals(txs)
+}
+
 func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.ccm.TxPool().SubscribeNewTxsEvent(ch)
 }
 
+func (b *EthAPIBackend) SubscribeFilteredTxsEvent(ch chan<- core.NewTxsEvent, filter ccmapi.PendingTxFilter) event.Subscription {
+	return ccmapi.NewFilteredTxsSubscription(b, filter, ch)
+}
+
+func (b *EthAPIBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) (event.Subscription, error) {
+	return b.ccm.TxPool().SubscribeDroppedTxsEvent(ch), nil
+}
+
 func (b *EthAPIBackend) Downloader() *downloader.Downloader {
 	return b.ccm.Downloader()
 }
@@ -216,6 +575,18 @@ func (b *EthAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *EthAPIBackend) SuggestPriorityFee(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestPriorityFee(ctx)
+}
+
+func (b *EthAPIBackend) SuggestPriceTier(ctx context.Context, tier string) (*big.Int, error) {
+	return b.gpo.SuggestPriceTier(ctx, tier)
+}
+
+func (b *EthAPIBackend) GasPriceFiltering() bool {
+	return b.gpo.Filtering()
+}
+
 func (b *EthAPIBackend) ChainDb() ccmdb.Database {
 	return b.ccm.ChainDb()
 }
@@ -236,6 +607,18 @@ func (b *EthAPIBackend) RPCGasCap() *big.Int {
 	return b.ccm.config.RPCGasCap
 }
 
+func (b *EthAPIBackend) TraceGasCap() *big.Int {
+	return b.ccm.config.TraceGasCap
+}
+
+func (b *EthAPIBackend) RPCCallDataCap() uint64 {
+	return b.ccm.config.RPCCallDataCap
+}
+
+func (b *EthAPIBackend) Requests() *ccmapi.RequestRegistry {
+	return b.requests
+}
+
 func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.ccm.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
@@ -18,17 +18,34 @@ package ccm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
+	"github.com/ccmchain/go-ccmchain/core"
 	"github.com/ccmchain/go-ccmchain/core/rawdb"
 	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/core/vm"
 	"github.com/ccmchain/go-ccmchain/crypto"
+	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
+	"github.com/ccmchain/go-ccmchain/miner"
+	"github.com/ccmchain/go-ccmchain/params"
+	"github.com/ccmchain/go-ccmchain/rlp"
+	"github.com/ccmchain/go-ccmchain/rpc"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -245,3 +262,741 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestStorageDiff(t *testing.T) {
+	var (
+		oldState, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		newState, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		addr        = common.Address{0x01}
+	)
+	// Slot 1 is unchanged, slot 2 is modified, slot 3 is deleted and slot 4 is added
+	oldState.SetState(addr, common.Hash{0x01}, common.Hash{0x01})
+	oldState.SetState(addr, common.Hash{0x02}, common.Hash{0x02})
+	oldState.SetState(addr, common.Hash{0x03}, common.Hash{0x03})
+
+	newState.SetState(addr, common.Hash{0x01}, common.Hash{0x01})
+	newState.SetState(addr, common.Hash{0x02}, common.Hash{0x05})
+	newState.SetState(addr, common.Hash{0x04}, common.Hash{0x04})
+
+	diff, err := storageDiff(oldState.StorageTrie(addr), newState.StorageTrie(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[common.Hash]HashDiff{
+		{0x02}: {From: common.Hash{0x02}, To: common.Hash{0x05}},
+		{0x03}: {From: common.Hash{0x03}, To: common.Hash{}},
+		{0x04}: {From: common.Hash{}, To: common.Hash{0x04}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("storage diff mismatch:\nhave %v\nwant %v", diff, want)
+	}
+	// A missing account on either side should report all slots as changed
+	diff, err = storageDiff(nil, newState.StorageTrie(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 3 || diff[common.Hash{0x01}] != (HashDiff{To: common.Hash{0x01}}) {
+		t.Fatalf("storage diff of created account mismatch: %v", diff)
+	}
+	diff, err = storageDiff(oldState.StorageTrie(addr), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 3 || diff[common.Hash{0x01}] != (HashDiff{From: common.Hash{0x01}}) {
+		t.Fatalf("storage diff of deleted account mismatch: %v", diff)
+	}
+}
+
+// Tests that logs are served from a configured read-only replica, covering both
+// its key-value store and its ancient store, and that missing replicas are
+// rejected instead of silently created.
+func TestGetLogsFromReplica(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs-replica")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	missing := filepath.Join(dir, "missing")
+	if _, err := openLogsDatabase(missing); err == nil {
+		t.Fatalf("missing replica opened")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Fatalf("missing replica created: %v", err)
+	}
+	// Assemble two blocks with a log each, freezing the first one
+	var (
+		key, _   = crypto.GenerateKey()
+		replica  = filepath.Join(dir, "chaindata")
+		blocks   []*types.Block
+		receipts []types.Receipts
+	)
+	for i := 0; i < 2; i++ {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		receipt := &types.Receipt{
+			Status: types.ReceiptStatusSuccessful,
+			Logs:   []*types.Log{{Address: common.Address{byte(i + 1)}}},
+			TxHash: tx.Hash(),
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("replica")}
+		blocks = append(blocks, types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}))
+		receipts = append(receipts, types.Receipts{receipt})
+	}
+	db, err := rawdb.NewLevelDBDatabaseWithFreezer(replica, 16, 16, filepath.Join(replica, "ancient"), "")
+	if err != nil {
+		t.Fatalf("failed to create replica: %v", err)
+	}
+	rawdb.WriteAncientBlock(db, blocks[0], receipts[0], big.NewInt(1))
+	rawdb.WriteHeaderNumber(db, blocks[0].Hash(), 0)
+	rawdb.WriteBlock(db, blocks[1])
+	rawdb.WriteReceipts(db, blocks[1].Hash(), 1, receipts[1])
+	db.Close()
+
+	logsDb, err := openLogsDatabase(replica)
+	if err != nil {
+		t.Fatalf("failed to open replica: %v", err)
+	}
+	defer logsDb.Close()
+
+	if err := logsDb.Put([]byte("key"), []byte("value")); err == nil {
+		t.Errorf("replica accepted a write")
+	}
+	// Serve the logs through a backend whose own chain doesn't contain them
+	chainDb := rawdb.NewMemoryDatabase()
+	(&core.Genesis{Config: params.TestChainConfig}).MustCommit(chainDb)
+	chain, err := core.NewBlockChain(chainDb, nil, params.TestChainConfig, ccmash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	backend := &EthAPIBackend{ccm: &Ccmchain{chainDb: chainDb, logsDb: logsDb, blockchain: chain}}
+	for i, block := range blocks {
+		logs, err := backend.GetLogs(context.Background(), block.Hash())
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve logs: %v", i, err)
+		}
+		if len(logs) != 1 || len(logs[0]) != 1 {
+			t.Fatalf("block %d: logs mismatch: have %v, want 1 log", i, logs)
+		}
+		if have, want := logs[0][0].Address, (common.Address{byte(i + 1)}); have != want {
+			t.Errorf("block %d: log address mismatch: have %x, want %x", i, have, want)
+		}
+	}
+}
+
+// exportChainStream subscribes to a chain export over an in-process RPC client,
+// collecting the streamed blocks until the last one or an error is received.
+func exportChainStream(t *testing.T, ccm *Ccmchain, first, last uint64) []ExportedBlock {
+	t.Helper()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("admin", NewPrivateAdminAPI(ccm)); err != nil {
+		t.Fatalf("failed to register admin API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan ExportedBlock)
+	sub, err := client.Subscribe(context.Background(), "admin", ch, "exportChainStream", hexutil.Uint64(first), hexutil.Uint64(last))
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var blocks []ExportedBlock
+	for {
+		select {
+		case block := <-ch:
+			blocks = append(blocks, block)
+			if block.Error != "" || uint64(block.Number) == last {
+				return blocks
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for exported blocks, have %d", len(blocks))
+		}
+	}
+}
+
+// Tests that a range of canonical blocks is streamed in order.
+func TestExportChainStream(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 4, nil)
+	defer ccm.blockchain.Stop()
+
+	blocks := exportChainStream(t, ccm, 1, 3)
+	if len(blocks) != 3 {
+		t.Fatalf("exported block count mismatch: have %d, want %d", len(blocks), 3)
+	}
+	for i, exported := range blocks {
+		number := uint64(i + 1)
+		if uint64(exported.Number) != number || exported.Error != "" {
+			t.Fatalf("block %d: unexpected export: number %d, error %q", number, exported.Number, exported.Error)
+		}
+		var block types.Block
+		if err := rlp.DecodeBytes(exported.Rlp, &block); err != nil {
+			t.Fatalf("block %d: failed to decode: %v", number, err)
+		}
+		if want := ccm.blockchain.GetBlockByNumber(number).Hash(); block.Hash() != want {
+			t.Errorf("block %d: hash mismatch: have %x, want %x", number, block.Hash(), want)
+		}
+	}
+}
+
+// Tests that a block missing from the database ends the export with an error
+// notification, instead of leaving the subscriber waiting.
+func TestExportChainStreamMissingBlock(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 4, nil)
+	missing := ccm.blockchain.GetBlockByNumber(2)
+	ccm.blockchain.Stop()
+
+	// Drop the body and reopen the chain to bypass its caches
+	rawdb.DeleteBody(ccm.chainDb, missing.Hash(), missing.NumberU64())
+	chain, err := core.NewBlockChain(ccm.chainDb, nil, params.TestChainConfig, ccmash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen blockchain: %v", err)
+	}
+	defer chain.Stop()
+	ccm.blockchain = chain
+
+	blocks := exportChainStream(t, ccm, 1, 4)
+	if len(blocks) != 2 {
+		t.Fatalf("exported block count mismatch: have %d, want %d", len(blocks), 2)
+	}
+	if blocks[0].Error != "" {
+		t.Errorf("block 1: unexpected error: %s", blocks[0].Error)
+	}
+	if blocks[1].Number != 2 || blocks[1].Error == "" || len(blocks[1].Rlp) != 0 {
+		t.Errorf("block 2: expected error notification, have %+v", blocks[1])
+	}
+}
+
+// Tests that transactions submitted through the RPC APIs are refused below the
+// configured minimum gas price, and accepted into the pool at or above it.
+func TestSendTxMinGasPrice(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	ccm := newTestCcmchain(t, core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ccmchain)}}, 0, nil)
+	defer ccm.blockchain.Stop()
+
+	ccm.config.RPCMinGasPrice = big.NewInt(10)
+	// Use a pool without stateful disk side effects
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+	ccm.txPool = core.NewTxPool(config, params.TestChainConfig, ccm.blockchain)
+	defer ccm.txPool.Stop()
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	send := func(nonce uint64, price int64) error {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(price), nil), signer, key)
+		return ccm.APIBackend.SendTx(context.Background(), tx)
+	}
+	if err := send(0, 9); err != ccmapi.ErrRPCUnderpriced {
+		t.Fatalf("underpriced transaction error mismatch: have %v, want %v", err, ccmapi.ErrRPCUnderpriced)
+	}
+	if err := send(0, 10); err != nil {
+		t.Fatalf("failed to send transaction at the minimum price: %v", err)
+	}
+	if pending, _ := ccm.txPool.Stats(); pending != 1 {
+		t.Fatalf("pending transaction count mismatch: have %d, want 1", pending)
+	}
+}
+
+// Tests that the available block range starts after the missing bodies of a
+// pruned chain, regardless of the genesis body always being present.
+func TestBlockRangeMissingBodies(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 8, nil)
+	defer ccm.blockchain.Stop()
+
+	backend := ccm.APIBackend
+	if lowest, highest := backend.BlockRange(); lowest != 0 || highest != 8 {
+		t.Fatalf("full chain range mismatch: have [%d, %d], want [0, 8]", lowest, highest)
+	}
+	for _, prune := range []uint64{2, 4, 8} {
+		for n := uint64(1); n < prune; n++ {
+			rawdb.DeleteBody(ccm.chainDb, rawdb.ReadCanonicalHash(ccm.chainDb, n), n)
+		}
+		if lowest, highest := backend.BlockRange(); lowest != prune || highest != 8 {
+			t.Errorf("range mismatch with bodies below %d pruned: have [%d, %d], want [%d, 8]", prune, lowest, highest, prune)
+		}
+	}
+}
+
+// Tests that the balance history samples every step-th block of the range, and
+// refuses inverted, oversized and out of chain ranges.
+func TestBalanceHistory(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0xbb")
+		signer    = types.HomesteadSigner{}
+	)
+	// Transfer one more wei to the recipient in every block
+	ccm := newTestCcmchain(t, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ccmchain)}}, 6, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), recipient, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	defer ccm.blockchain.Stop()
+
+	api := NewPublicCcmchainAPI(ccm)
+	step := hexutil.Uint64(2)
+	samples, err := api.BalanceHistory(context.Background(), recipient, 0, 6, &step)
+	if err != nil {
+		t.Fatalf("failed to retrieve balance history: %v", err)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("sample count mismatch: have %d, want 4", len(samples))
+	}
+	for i, sample := range samples {
+		if want := uint64(2 * i); uint64(sample.Number) != want || sample.Balance.ToInt().Uint64() != want {
+			t.Errorf("sample %d mismatch: have block %d with balance %v, want block %d with balance %d", i, sample.Number, sample.Balance, want, want)
+		}
+	}
+	// Invalid ranges should be rejected instead of partially served
+	for _, tt := range []struct {
+		from, to hexutil.Uint64
+		err      string
+	}{
+		{4, 2, "invalid block range"},
+		{0, maxBalanceHistorySamples, "too many samples"},
+		{0, 8, "block #7 not found"},
+	} {
+		if _, err := api.BalanceHistory(context.Background(), recipient, tt.from, tt.to, nil); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("range [%d, %d]: error mismatch: have %v, want %q", tt.from, tt.to, err, tt.err)
+		}
+	}
+}
+
+// Tests that the storage of a contract can be paged through completely by
+// restarting each page at the next key of the previous one.
+func TestGetStorageRangeAtPaging(t *testing.T) {
+	contract := common.HexToAddress("0xc0")
+	storage := make(map[common.Hash]common.Hash)
+	for i := byte(1); i <= 5; i++ {
+		storage[common.Hash{i}] = common.Hash{0xff, i}
+	}
+	ccm := newTestCcmchain(t, core.GenesisAlloc{contract: {Code: []byte{0x00}, Storage: storage, Balance: new(big.Int)}}, 0, nil)
+	defer ccm.blockchain.Stop()
+
+	var (
+		api   = NewPublicCcmchainAPI(ccm)
+		seen  = make(map[common.Hash]bool)
+		start hexutil.Bytes
+		pages int
+	)
+	for {
+		result, err := api.GetStorageRangeAt(context.Background(), contract, rpc.LatestBlockNumber, start, 2)
+		if err != nil {
+			t.Fatalf("page %d: failed to retrieve storage range: %v", pages, err)
+		}
+		if len(result.Storage) > 2 {
+			t.Fatalf("page %d: result limit exceeded: have %d slots", pages, len(result.Storage))
+		}
+		for hash, entry := range result.Storage {
+			if seen[hash] {
+				t.Errorf("page %d: slot %x returned twice", pages, hash)
+			}
+			seen[hash] = true
+			if entry.Value[0] != 0xff {
+				t.Errorf("page %d: slot %x value mismatch: have %x", pages, hash, entry.Value)
+			}
+		}
+		pages++
+		if result.NextKey == nil {
+			break
+		}
+		start = result.NextKey.Bytes()
+	}
+	if pages != 3 || len(seen) != len(storage) {
+		t.Errorf("paging mismatch: have %d slots in %d pages, want %d slots in 3 pages", len(seen), pages, len(storage))
+	}
+	// Out of bounds limits and unknown accounts should be rejected
+	for _, limit := range []int{0, maxStorageRangeResults + 1} {
+		if _, err := api.GetStorageRangeAt(context.Background(), contract, rpc.LatestBlockNumber, nil, limit); err == nil {
+			t.Errorf("limit %d: expected error", limit)
+		}
+	}
+	if _, err := api.GetStorageRangeAt(context.Background(), common.HexToAddress("0xdead"), rpc.LatestBlockNumber, nil, 1); err == nil {
+		t.Errorf("missing account: expected error")
+	}
+}
+
+// Tests that the transaction status is resolved from the pool for pending and
+// queued transactions, and from the chain index for included ones.
+func TestGetTransactionStatus(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	transfer := func(nonce uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		return tx
+	}
+	included := transfer(0)
+	ccm := newTestCcmchain(t, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ccmchain)}}, 1, func(i int, gen *core.BlockGen) {
+		gen.AddTx(included)
+	})
+	defer ccm.blockchain.Stop()
+
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+	ccm.txPool = core.NewTxPool(config, params.TestChainConfig, ccm.blockchain)
+	defer ccm.txPool.Stop()
+
+	pending, queued := transfer(1), transfer(3)
+	for _, err := range ccm.txPool.AddRemotesSync([]*types.Transaction{pending, queued}) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	api := ccmapi.NewPublicTransactionPoolAPI(ccm.APIBackend, new(ccmapi.AddrLocker))
+	for _, tt := range []struct {
+		hash   common.Hash
+		pooled bool
+		status string
+	}{
+		{included.Hash(), false, "included"},
+		{pending.Hash(), true, "pending"},
+		{queued.Hash(), true, "queued"},
+		{transfer(2).Hash(), false, "unknown"},
+	} {
+		if have := ccm.APIBackend.HasPoolTransaction(tt.hash); have != tt.pooled {
+			t.Errorf("%s transaction: pool membership mismatch: have %v, want %v", tt.status, have, tt.pooled)
+		}
+		if have := api.GetTransactionStatus(context.Background(), tt.hash); have != tt.status {
+			t.Errorf("transaction status mismatch: have %s, want %s", have, tt.status)
+		}
+	}
+}
+
+// Tests that rebuilding indexed bloombits sections restores corrupted index data
+// from the canonical headers, and that sections not yet indexed are refused.
+func TestRebuildBloomBits(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0")
+		signer   = types.HomesteadSigner{}
+	)
+	// The contract emits an empty log: PUSH1 0, PUSH1 0, LOG0
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ccmchain)},
+		contract: {Code: []byte{0x60, 0x00, 0x60, 0x00, 0xa0}, Balance: new(big.Int)},
+	}
+	ccm := newTestCcmchain(t, alloc, 16, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), contract, new(big.Int), 100000, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	defer ccm.blockchain.Stop()
+
+	// Index the chain in sections of 8 blocks, the smallest supported size
+	const size = 8
+	ccm.bloomIndexer = NewBloomIndexer(ccm.chainDb, size, 0)
+	ccm.bloomIndexer.Start(ccm.blockchain)
+	defer ccm.bloomIndexer.Close()
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := ccm.bloomIndexer.Sections(); sections == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("chain not indexed in time")
+		}
+	}
+	head := ccm.bloomIndexer.SectionHead(1)
+	want, err := rawdb.ReadBloomBits(ccm.chainDb, 0, 1, head)
+	if err != nil {
+		t.Fatalf("failed to read indexed bloom bits: %v", err)
+	}
+	rawdb.WriteBloomBits(ccm.chainDb, 0, 1, head, []byte{0xde, 0xad})
+
+	if err := rebuildBloomSection(context.Background(), ccm.chainDb, ccm.blockchain, ccm.bloomIndexer, size, 1); err != nil {
+		t.Fatalf("failed to rebuild section: %v", err)
+	}
+	if have, _ := rawdb.ReadBloomBits(ccm.chainDb, 0, 1, head); !bytes.Equal(have, want) {
+		t.Errorf("rebuilt bloom bits mismatch: have %x, want %x", have, want)
+	}
+	// Invalid and not yet indexed section ranges should be rejected
+	api := NewPrivateDebugAPI(ccm)
+	for _, tt := range [][2]hexutil.Uint64{{1, 0}, {0, 2}} {
+		if _, err := api.RebuildBloomBits(context.Background(), tt[0], tt[1]); err == nil {
+			t.Errorf("sections %d-%d: expected error", tt[0], tt[1])
+		}
+	}
+}
+
+// Tests that code sizes are reported for contracts and as zero for accounts
+// without code.
+func TestGetCodeSize(t *testing.T) {
+	contract := common.HexToAddress("0xc0")
+	ccm := newTestCcmchain(t, core.GenesisAlloc{contract: {Code: []byte{0x60, 0x00, 0x00}, Balance: new(big.Int)}}, 0, nil)
+	defer ccm.blockchain.Stop()
+
+	api := ccmapi.NewPublicBlockChainAPI(ccm.APIBackend)
+	for addr, want := range map[common.Address]hexutil.Uint64{contract: 3, common.HexToAddress("0xee"): 0} {
+		size, err := api.GetCodeSize(context.Background(), addr, rpc.LatestBlockNumber)
+		if err != nil {
+			t.Fatalf("%x: failed to retrieve code size: %v", addr, err)
+		}
+		if size != want {
+			t.Errorf("%x: code size mismatch: have %d, want %d", addr, size, want)
+		}
+	}
+}
+
+// Tests that header lookups by hash resolve the miner's pending block besides
+// the blocks of the chain.
+func TestHeaderByHashPending(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 1, nil)
+	defer ccm.blockchain.Stop()
+
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+	ccm.txPool = core.NewTxPool(config, params.TestChainConfig, ccm.blockchain)
+	defer ccm.txPool.Stop()
+
+	ccm.miner = miner.New(ccm, &ccm.config.Miner, params.TestChainConfig, new(event.TypeMux), ccm.engine, func(*types.Block) bool { return false })
+	defer ccm.miner.Close()
+
+	// The worker assembles the first pending block in the background
+	var pending *types.Block
+	for deadline := time.Now().Add(5 * time.Second); pending == nil; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("pending block not created in time")
+		}
+		pending = ccm.miner.PendingBlock()
+	}
+	head := ccm.blockchain.CurrentBlock()
+	for _, block := range []*types.Block{head, pending} {
+		header, err := ccm.APIBackend.HeaderByHash(context.Background(), block.Hash())
+		if err != nil || header == nil || header.Hash() != block.Hash() {
+			t.Errorf("block #%d: header mismatch: have %v, %v", block.NumberU64(), header, err)
+		}
+	}
+	if header, err := ccm.APIBackend.HeaderByHash(context.Background(), common.Hash{0x01}); header != nil || err != nil {
+		t.Errorf("unknown hash: have %v, %v, want nothing", header, err)
+	}
+}
+
+// Tests that uncles are looked up by block hash and index, and that missing
+// blocks and out of range indices resolve to nothing.
+func TestGetUncle(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 4, func(i int, gen *core.BlockGen) {
+		if i == 3 {
+			// Block 4 includes blocks 2 and 3 as uncle headers (with modified extra data)
+			b2 := gen.PrevBlock(1).Header()
+			b2.Extra = []byte("foo")
+			gen.AddUncle(b2)
+			b3 := gen.PrevBlock(2).Header()
+			b3.Extra = []byte("foo")
+			gen.AddUncle(b3)
+		}
+	})
+	defer ccm.blockchain.Stop()
+
+	block := ccm.blockchain.CurrentBlock()
+	for i, want := range block.Uncles() {
+		uncle, err := ccm.APIBackend.GetUncle(context.Background(), block.Hash(), i)
+		if err != nil || uncle == nil || uncle.Hash() != want.Hash() {
+			t.Errorf("uncle %d mismatch: have %v, %v, want %x", i, uncle, err, want.Hash())
+		}
+	}
+	for _, tt := range []struct {
+		hash  common.Hash
+		index int
+	}{
+		{block.Hash(), 2},
+		{block.Hash(), -1},
+		{block.ParentHash(), 0},
+		{common.Hash{0x01}, 0},
+	} {
+		if uncle, err := ccm.APIBackend.GetUncle(context.Background(), tt.hash, tt.index); uncle != nil || err != nil {
+			t.Errorf("block %x, index %d: have %v, %v, want nothing", tt.hash, tt.index, uncle, err)
+		}
+	}
+	// The RPC API should marshal the uncle as a block without transactions
+	api := ccmapi.NewPublicBlockChainAPI(ccm.APIBackend)
+	fields, err := api.GetUncleByBlockHashAndIndex(context.Background(), block.Hash(), 1)
+	if err != nil || fields == nil || fields["hash"] != block.Uncles()[1].Hash() {
+		t.Errorf("marshalled uncle mismatch: have %v, %v", fields, err)
+	}
+}
+
+// Tests that raw receipts are served in their consensus encoding for blocks
+// identified by number, tag or hash, deriving the receipt root of the block.
+func TestGetRawReceipts(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.HomesteadSigner{}
+	)
+	ccm := newTestCcmchain(t, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ccmchain)}}, 1, func(i int, gen *core.BlockGen) {
+		for nonce := uint64(0); nonce < 2; nonce++ {
+			tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	defer ccm.blockchain.Stop()
+
+	api := ccmapi.NewPublicDebugAPI(ccm.APIBackend)
+	header := ccm.blockchain.CurrentHeader()
+	for _, id := range []string{"0x1", "latest", header.Hash().Hex()} {
+		encoded, err := api.GetRawReceipts(context.Background(), id)
+		if err != nil {
+			t.Fatalf("%s: failed to retrieve raw receipts: %v", id, err)
+		}
+		receipts := make(types.Receipts, len(encoded))
+		for i, blob := range encoded {
+			receipts[i] = new(types.Receipt)
+			if err := rlp.DecodeBytes(blob, receipts[i]); err != nil {
+				t.Fatalf("%s: receipt %d: failed to decode: %v", id, i, err)
+			}
+		}
+		if len(receipts) != 2 || types.DeriveSha(receipts) != header.ReceiptHash {
+			t.Errorf("%s: receipts don't derive the receipt root: have %d receipts", id, len(receipts))
+		}
+	}
+	for _, id := range []string{"pending", "0x2"} {
+		if _, err := api.GetRawReceipts(context.Background(), id); err == nil {
+			t.Errorf("%s: expected error", id)
+		}
+	}
+}
+
+// Tests that the reported total difficulty follows the chain head.
+func TestTotalDifficulty(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 3, nil)
+	defer ccm.blockchain.Stop()
+
+	// The total difficulty accumulates the difficulties of the blocks on top of
+	// the genesis one
+	genesis := ccm.blockchain.Genesis()
+	td := func(head uint64) *big.Int {
+		sum := new(big.Int).Set(ccm.blockchain.GetTd(genesis.Hash(), 0))
+		for n := uint64(1); n <= head; n++ {
+			sum.Add(sum, ccm.blockchain.GetHeaderByNumber(n).Difficulty)
+		}
+		return sum
+	}
+	api := ccmapi.NewPublicBlockChainAPI(ccm.APIBackend)
+	if have, want := api.TotalDifficulty(), td(3); have.ToInt().Cmp(want) != 0 {
+		t.Errorf("total difficulty mismatch: have %v, want %v", have, want)
+	}
+	// Rewinding the chain should rewind the total difficulty too
+	if err := ccm.blockchain.SetHead(1); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	if have, want := api.TotalDifficulty(), td(1); have.ToInt().Cmp(want) != 0 {
+		t.Errorf("rewound total difficulty mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that trie nodes are served by hash from the state database.
+func TestGetTrieNode(t *testing.T) {
+	ccm := newTestCcmchain(t, core.GenesisAlloc{common.HexToAddress("0xaa"): {Balance: big.NewInt(1)}}, 1, nil)
+	defer ccm.blockchain.Stop()
+
+	api := NewPrivateDebugAPI(ccm)
+	root := ccm.blockchain.CurrentBlock().Root()
+	node, err := api.GetTrieNode(context.Background(), root)
+	if err != nil {
+		t.Fatalf("failed to retrieve state root node: %v", err)
+	}
+	if hash := crypto.Keccak256Hash(node); hash != root {
+		t.Errorf("trie node hash mismatch: have %x, want %x", hash, root)
+	}
+	if _, err := api.GetTrieNode(context.Background(), common.Hash{0x01}); err == nil {
+		t.Errorf("unknown trie node: expected error")
+	}
+}
+
+// Tests that receipt infos are batch retrieved in request order, leaving gaps for
+// unknown transactions, and that oversized batches are refused.
+func TestGetReceiptsByTxHashes(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0")
+		signer   = types.HomesteadSigner{}
+	)
+	// The contract reverts any call: PUSH1 0, DUP1, REVERT
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ccmchain)},
+		contract: {Code: []byte{0x60, 0x00, 0x80, 0xfd}, Balance: new(big.Int)},
+	}
+	var txs []*types.Transaction
+	ccm := newTestCcmchain(t, alloc, 2, func(i int, gen *core.BlockGen) {
+		to := common.Address{0x01}
+		if i == 1 {
+			to = contract
+		}
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), to, new(big.Int), 100000, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+		txs = append(txs, tx)
+	})
+	defer ccm.blockchain.Stop()
+
+	api := ccmapi.NewPublicTransactionPoolAPI(ccm.APIBackend, new(ccmapi.AddrLocker))
+	infos, err := api.GetReceiptsByTxHashes(context.Background(), []common.Hash{txs[1].Hash(), {0x01}, txs[0].Hash()})
+	if err != nil {
+		t.Fatalf("failed to retrieve receipts: %v", err)
+	}
+	if len(infos) != 3 || infos[1] != nil {
+		t.Fatalf("receipt infos mismatch: have %+v, want gap for unknown transaction", infos)
+	}
+	for i, tt := range []struct {
+		info   *ccmapi.ReceiptInfo
+		tx     *types.Transaction
+		number uint64
+		status uint64
+	}{
+		{infos[0], txs[1], 2, types.ReceiptStatusFailed},
+		{infos[2], txs[0], 1, types.ReceiptStatusSuccessful},
+	} {
+		receipt := ccm.blockchain.GetReceiptsByHash(ccm.blockchain.GetHeaderByNumber(tt.number).Hash())[0]
+		if tt.info.TxHash != tt.tx.Hash() || uint64(tt.info.BlockNumber) != tt.number || uint64(tt.info.GasUsed) != receipt.GasUsed {
+			t.Errorf("receipt %d mismatch: have %+v", i, tt.info)
+		}
+		if tt.info.Status == nil || uint64(*tt.info.Status) != tt.status {
+			t.Errorf("receipt %d status mismatch: have %v, want %d", i, tt.info.Status, tt.status)
+		}
+	}
+	if _, err := api.GetReceiptsByTxHashes(context.Background(), make([]common.Hash, ccmapi.MaxReceiptBatch+1)); err == nil {
+		t.Errorf("oversized batch: expected error")
+	}
+}
+
+// Tests that header encodings are served for blocks identified by number, tag or
+// hash, and that the pending block is refused.
+func TestGetHeaderRlp(t *testing.T) {
+	ccm := newTestCcmchain(t, nil, 2, nil)
+	defer ccm.blockchain.Stop()
+
+	api := ccmapi.NewPublicDebugAPI(ccm.APIBackend)
+	for id, number := range map[string]uint64{
+		"0x1":    1,
+		"latest": 2,
+		ccm.blockchain.GetHeaderByNumber(0).Hash().Hex(): 0,
+	} {
+		blob, err := api.GetHeaderRlp(context.Background(), id)
+		if err != nil {
+			t.Fatalf("%s: failed to retrieve header: %v", id, err)
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(blob, header); err != nil {
+			t.Fatalf("%s: failed to decode header: %v", id, err)
+		}
+		if want := ccm.blockchain.GetHeaderByNumber(number).Hash(); header.Hash() != want {
+			t.Errorf("%s: header mismatch: have %x, want %x", id, header.Hash(), want)
+		}
+	}
+	for _, id := range []string{"pending", "0x3"} {
+		if _, err := api.GetHeaderRlp(context.Background(), id); err == nil {
+			t.Errorf("%s: expected error", id)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"runtime"
 	"sync"
@@ -99,9 +100,22 @@ type txTraceTask struct {
 	index   int            // Transaction offset in the block
 }
 
+// validateTraceConfig compiles any custom JavaScript tracer requested by the
+// config upfront, so that a broken tracer is reported immediately instead of
+// after regenerating state and executing blocks.
+func validateTraceConfig(config *TraceConfig) error {
+	if config == nil || config.Tracer == nil {
+		return nil
+	}
+	return tracers.Validate(*config.Tracer)
+}
+
 // TraceChain returns the structured logs created during the execution of EVM
 // between two blocks (excluding start) and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
+	if err := validateTraceConfig(config); err != nil {
+		return nil, err
+	}
 	// Fetch the block interval that we want to trace
 	var from, to *types.Block
 
@@ -438,10 +452,94 @@ func (api *PrivateDebugAPI) StandardTraceBadBlockToFile(ctx context.Context, has
 	return nil, fmt.Errorf("bad block %#x not found", hash)
 }
 
+// BlockVerification is the outcome of re-executing a block on top of its parent
+// state and comparing the results against the locally stored header.
+type BlockVerification struct {
+	Valid      bool            `json:"valid"`
+	Mismatches []BlockMismatch `json:"mismatches,omitempty"`
+}
+
+// BlockMismatch is a single header field whose stored value differs from the one
+// obtained by locally re-executing the block.
+type BlockMismatch struct {
+	Field    string      `json:"field"`
+	Stored   interface{} `json:"stored"`
+	Computed interface{} `json:"computed"`
+}
+
+// VerifyBlock re-executes the block with the given number against its parent
+// state and checks that the resulting state root, receipts root and gas used
+// match the stored header. It is meant to detect local database corruption.
+func (api *PrivateDebugAPI) VerifyBlock(ctx context.Context, number rpc.BlockNumber) (*BlockVerification, error) {
+	var block *types.Block
+
+	switch number {
+	case rpc.PendingBlockNumber:
+		return nil, errors.New("pending block cannot be verified")
+	case rpc.LatestBlockNumber:
+		block = api.ccm.blockchain.CurrentBlock()
+	default:
+		block = api.ccm.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.verifyBlock(block)
+}
+
+// VerifyBlockByHash re-executes the block with the given hash against its parent
+// state and checks that the resulting state root, receipts root and gas used
+// match the stored header.
+func (api *PrivateDebugAPI) VerifyBlockByHash(ctx context.Context, hash common.Hash) (*BlockVerification, error) {
+	block := api.ccm.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	return api.verifyBlock(block)
+}
+
+// verifyBlock re-executes a block on top of its parent state and collects any
+// differences between the computed results and the block header.
+func (api *PrivateDebugAPI) verifyBlock(block *types.Block) (*BlockVerification, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not verifiable")
+	}
+	parent := api.ccm.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	receipts, _, usedGas, err := api.ccm.blockchain.Processor().Process(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-execute block #%d: %v", block.NumberU64(), err)
+	}
+	var (
+		header = block.Header()
+		result = &BlockVerification{Valid: true}
+	)
+	if header.GasUsed != usedGas {
+		result.Mismatches = append(result.Mismatches, BlockMismatch{"gasUsed", hexutil.Uint64(header.GasUsed), hexutil.Uint64(usedGas)})
+	}
+	if root := types.DeriveSha(receipts); header.ReceiptHash != root {
+		result.Mismatches = append(result.Mismatches, BlockMismatch{"receiptsRoot", header.ReceiptHash, root})
+	}
+	if root := statedb.IntermediateRoot(api.ccm.blockchain.Config().IsEIP158(header.Number)); header.Root != root {
+		result.Mismatches = append(result.Mismatches, BlockMismatch{"stateRoot", header.Root, root})
+	}
+	result.Valid = len(result.Mismatches) == 0
+	return result, nil
+}
+
 // traceBlock configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requestd tracer.
 func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	if err := validateTraceConfig(config); err != nil {
+		return nil, err
+	}
 	// Create the parent state database
 	if err := api.ccm.engine.VerifyHeader(api.ccm.blockchain, block.Header(), true); err != nil {
 		return nil, err
@@ -577,6 +675,9 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 		)
 		// If the transaction needs tracing, swap out the configs
 		if tx.Hash() == txHash || txHash == (common.Hash{}) {
+			if err := api.checkTraceGas(msg); err != nil {
+				return dumps, err
+			}
 			// Generate a unique temporary file to dump it into
 			prefix := fmt.Sprintf("block_%#x-%d-%#x-", block.Hash().Bytes()[:4], i, tx.Hash().Bytes()[:4])
 
@@ -702,6 +803,9 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 // TraceTransaction returns the structured logs created during the execution of EVM
 // and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	if err := validateTraceConfig(config); err != nil {
+		return nil, err
+	}
 	// Retrieve the transaction and assemble its EVM context
 	tx, blockHash, _, index := rawdb.ReadTransaction(api.ccm.ChainDb(), hash)
 	if tx == nil {
@@ -716,13 +820,154 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 		return nil, err
 	}
 	// Trace the transaction and return
+	ctx, done := api.ccm.APIBackend.Requests().Track(ctx, "debug_traceTransaction")
+	defer done()
+
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
 
+// TraceCall lets you trace a given ccm_call. It collects the structured logs created
+// during the execution of EVM if the given transaction was added on top of the
+// provided block and returns them as a JSON object.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ccmapi.CallArgs, blockNr rpc.BlockNumber, config *TraceConfig) (interface{}, error) {
+	if err := validateTraceConfig(config); err != nil {
+		return nil, err
+	}
+	if err := ccmapi.CheckCallDataSize(api.ccm.APIBackend, args); err != nil {
+		return nil, err
+	}
+	// Retrieve the state the call should be executed on top of
+	statedb, header, err := api.ccm.APIBackend.StateAndHeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	// Assemble the call message and its EVM context, funding the sender the same
+	// way ccm_call does so unpriced simulations don't run out of balance. The gas
+	// is capped by the trace cap instead of the call one, since traces may need
+	// to replay executions that legitimately exceed the latter.
+	gasCap := api.ccm.APIBackend.TraceGasCap()
+	if gasCap == nil {
+		gasCap = new(big.Int).SetUint64(header.GasLimit)
+	}
+	msg := args.ToMessage(api.ccm.APIBackend, gasCap)
+
+	ctx, done := api.ccm.APIBackend.Requests().Track(ctx, "debug_traceCall")
+	defer done()
+
+	// Execute on an EVM created by the backend, with the tracer added on top of
+	// the chain's VM configuration
+	return api.traceMessage(ctx, msg, config, func(tracer vm.Tracer) (*vm.EVM, error) {
+		vmConfig := *api.ccm.blockchain.GetVMConfig()
+		vmConfig.Debug, vmConfig.Tracer = true, tracer
+
+		evm, _, err := api.ccm.APIBackend.GetEVM(ctx, msg, statedb, header, &vmConfig)
+		return evm, err
+	})
+}
+
+// ReplayOverrides holds the transaction fields to change when replaying a
+// transaction. Unset fields retain the value of the original transaction.
+type ReplayOverrides struct {
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Reexec   *uint64         `json:"reexec"`
+}
+
+// ReplayResult is the outcome of replaying a transaction, along with the outcome
+// of its original execution for comparison.
+type ReplayResult struct {
+	Gas            hexutil.Uint64 `json:"gas"`
+	Failed         bool           `json:"failed"`
+	ReturnValue    hexutil.Bytes  `json:"returnValue"`
+	Error          string         `json:"error,omitempty"` // Reason the replay was rejected outright, e.g. insufficient funds
+	OriginalGas    hexutil.Uint64 `json:"originalGas"`
+	OriginalFailed bool           `json:"originalFailed"`
+}
+
+// ReplayTransaction re-executes a mined transaction on top of the state it was
+// originally executed on, but with the gas limit and gas price overridden. This
+// allows checking whether a failed transaction would have succeeded with e.g. a
+// higher gas allowance.
+func (api *PrivateDebugAPI) ReplayTransaction(ctx context.Context, hash common.Hash, overrides ReplayOverrides) (*ReplayResult, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(api.ccm.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	reexec := defaultTraceReexec
+	if overrides.Reexec != nil {
+		reexec = *overrides.Reexec
+	}
+	msg, vmctx, statedb, err := api.computeTxEnv(blockHash, int(index), reexec)
+	if err != nil {
+		return nil, err
+	}
+	gas, gasPrice := msg.Gas(), msg.GasPrice()
+	if overrides.Gas != nil {
+		gas = uint64(*overrides.Gas)
+	}
+	if overrides.GasPrice != nil {
+		gasPrice = overrides.GasPrice.ToInt()
+	}
+	msg = types.NewMessage(msg.From(), msg.To(), msg.Nonce(), msg.Value(), gas, gasPrice, msg.Data(), true)
+	vmctx.GasPrice = new(big.Int).Set(gasPrice)
+
+	ctx, done := api.ccm.APIBackend.Requests().Track(ctx, "debug_replayTransaction")
+	defer done()
+
+	vmenv := vm.NewEVM(vmctx, statedb, api.ccm.blockchain.Config(), vm.Config{})
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-execCtx.Done()
+		vmenv.Cancel()
+	}()
+	result := new(ReplayResult)
+	ret, used, failed, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(gas))
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("replay aborted: %v", ctx.Err())
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Gas, result.Failed, result.ReturnValue = hexutil.Uint64(used), failed, ret
+	}
+	if receipts := api.ccm.blockchain.GetReceiptsByHash(blockHash); int(index) < len(receipts) {
+		receipt := receipts[index]
+		result.OriginalGas = hexutil.Uint64(receipt.GasUsed)
+		result.OriginalFailed = receipt.Status == types.ReceiptStatusFailed
+	}
+	return result, nil
+}
+
+// checkTraceGas returns an error if tracing the message would exceed the trace
+// gas cap. Such executions are refused rather than run with less gas, as that
+// would not reproduce them.
+func (api *PrivateDebugAPI) checkTraceGas(msg core.Message) error {
+	if gasCap := api.ccm.APIBackend.TraceGasCap(); gasCap != nil && gasCap.IsUint64() && msg.Gas() > gasCap.Uint64() {
+		return fmt.Errorf("transaction gas %d exceeds trace gas cap %v", msg.Gas(), gasCap)
+	}
+	return nil
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	return api.traceMessage(ctx, message, config, func(tracer vm.Tracer) (*vm.EVM, error) {
+		return vm.NewEVM(vmctx, statedb, api.ccm.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer}), nil
+	})
+}
+
+// traceMessage executes the message on the EVM created by newEVM around the
+// tracer requested by the configuration. The return value will be tracer
+// dependent.
+func (api *PrivateDebugAPI) traceMessage(ctx context.Context, message core.Message, config *TraceConfig, newEVM func(vm.Tracer) (*vm.EVM, error)) (interface{}, error) {
+	if err := api.checkTraceGas(message); err != nil {
+		return nil, err
+	}
 	// Assemble the structured logger or the JavaScript tracer
 	var (
 		tracer vm.Tracer
@@ -756,12 +1001,25 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
 	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, api.ccm.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+	vmenv, err := newEVM(tracer)
+	if err != nil {
+		return nil, err
+	}
 
+	// Abort the execution if the request is cancelled, e.g. via debug_cancelRequest
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-execCtx.Done()
+		vmenv.Cancel()
+	}()
 	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %v", err)
 	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("tracing aborted: %v", ctx.Err())
+	}
 	// Depending on the tracer type, format and return the output
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
@@ -0,0 +1,402 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccm
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/rawdb"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/core/vm"
+	"github.com/ccmchain/go-ccmchain/crypto"
+	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
+	"github.com/ccmchain/go-ccmchain/params"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// newTestCcmchain creates a minimal full node around a chain built on the given
+// genesis allocation, extended by n blocks generated by gen.
+func newTestCcmchain(t testing.TB, alloc core.GenesisAlloc, n int, gen func(int, *core.BlockGen)) *Ccmchain {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ccmash.NewFaker()
+		gspec  = &core.Genesis{Config: params.TestChainConfig, Alloc: alloc, GasLimit: 1000000000}
+	)
+	genesis := gspec.MustCommit(db)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, n, gen)
+
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	config := DefaultConfig
+	ccm := &Ccmchain{config: &config, chainDb: db, logsDb: db, blockchain: chain, engine: engine}
+	ccm.APIBackend = &EthAPIBackend{ccm: ccm, requests: ccmapi.NewRequestRegistry(nil), states: ccmapi.NewStateReaders(ccmapi.MaxConcurrentStates)}
+	return ccm
+}
+
+// Tests that a running trace can be listed and cancelled through the request
+// registry, aborting the execution instead of running it to completion.
+func TestTraceCallCancel(t *testing.T) {
+	// The contract loops until it runs out of gas: JUMPDEST, PUSH1 0, JUMP
+	contract := common.HexToAddress("0x10")
+	ccm := newTestCcmchain(t, core.GenesisAlloc{contract: {Code: []byte{0x5b, 0x60, 0x00, 0x56}, Balance: new(big.Int)}}, 0, nil)
+	defer ccm.blockchain.Stop()
+
+	var (
+		api      = NewPrivateDebugAPI(ccm)
+		requests = ccm.APIBackend.Requests()
+		from     = common.HexToAddress("0x20")
+		gas      = hexutil.Uint64(1000000000)
+		errc     = make(chan error, 1)
+	)
+	go func() {
+		config := &TraceConfig{LogConfig: &vm.LogConfig{DisableMemory: true, DisableStack: true, DisableStorage: true, Limit: 1}}
+		_, err := api.TraceCall(context.Background(), ccmapi.CallArgs{From: &from, To: &contract, Gas: &gas}, rpc.LatestBlockNumber, config)
+		errc <- err
+	}()
+	// Wait for the trace to show up as running and abort it
+	var running []ccmapi.RunningRequest
+	for deadline := time.Now().Add(5 * time.Second); len(running) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("trace not listed as running")
+		}
+		time.Sleep(time.Millisecond)
+		running = requests.Running()
+	}
+	if len(running) != 1 || running[0].Method != "debug_traceCall" {
+		t.Fatalf("running requests mismatch: have %+v", running)
+	}
+	if !requests.Cancel(uint64(running[0].ID)) {
+		t.Fatalf("failed to cancel trace")
+	}
+	select {
+	case err := <-errc:
+		if err == nil || !strings.Contains(err.Error(), "aborted") {
+			t.Fatalf("trace error mismatch: have %v, want abort", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("trace not aborted")
+	}
+	if running := requests.Running(); len(running) != 0 {
+		t.Errorf("finished trace still listed: %+v", running)
+	}
+}
+
+// Tests that calls, gas estimations and call traces all reject input data above
+// the configured size limit, while accepting data right at it.
+func TestCallDataSizeLimit(t *testing.T) {
+	ccm := newTestCcmchain(t, core.GenesisAlloc{}, 0, nil)
+	defer ccm.blockchain.Stop()
+	ccm.config.RPCCallDataCap = 16
+
+	var (
+		chain  = ccmapi.NewPublicBlockChainAPI(ccm.APIBackend)
+		debug  = NewPrivateDebugAPI(ccm)
+		from   = common.HexToAddress("0x20")
+		to     = common.HexToAddress("0x10")
+		latest = rpc.LatestBlockNumber
+	)
+	methods := map[string]func(ccmapi.CallArgs) error{
+		"ccm_call": func(args ccmapi.CallArgs) error {
+			_, err := chain.Call(context.Background(), args, latest)
+			return err
+		},
+		"ccm_estimateGas": func(args ccmapi.CallArgs) error {
+			_, err := chain.EstimateGas(context.Background(), args, &latest)
+			return err
+		},
+		"debug_traceCall": func(args ccmapi.CallArgs) error {
+			_, err := debug.TraceCall(context.Background(), args, latest, nil)
+			return err
+		},
+	}
+	for method, call := range methods {
+		for size, oversized := range map[int]bool{16: false, 17: true} {
+			data := hexutil.Bytes(make([]byte, size))
+			err := call(ccmapi.CallArgs{From: &from, To: &to, Data: &data})
+
+			limited := err != nil && strings.Contains(err.Error(), "call data too large")
+			if limited != oversized {
+				t.Errorf("%s with %d bytes: limit enforcement mismatch: have %v, want limited %v", method, size, err, oversized)
+			}
+		}
+	}
+}
+
+// Tests that the trace gas cap clamps traced calls and refuses to trace mined
+// transactions above it, both individually and as part of a block.
+func TestTraceGasCap(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x10")
+		signer   = types.HomesteadSigner{}
+	)
+	// The contract loops until it runs out of gas: JUMPDEST, PUSH1 0, JUMP
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ccmchain)},
+		contract: {Code: []byte{0x5b, 0x60, 0x00, 0x56}, Balance: new(big.Int)},
+	}
+	var tx *types.Transaction
+	ccm := newTestCcmchain(t, alloc, 1, func(i int, gen *core.BlockGen) {
+		tx, _ = types.SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), 100000, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	defer ccm.blockchain.Stop()
+	ccm.config.TraceGasCap = big.NewInt(50000)
+
+	api := NewPrivateDebugAPI(ccm)
+	if _, err := api.TraceTransaction(context.Background(), tx.Hash(), nil); err == nil || !strings.Contains(err.Error(), "trace gas cap") {
+		t.Errorf("transaction trace error mismatch: have %v, want gas cap error", err)
+	}
+	results, err := api.TraceBlockByNumber(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Error, "trace gas cap") {
+		t.Errorf("block trace results mismatch: have %+v, want gas cap error", results)
+	}
+	// Calls are capped instead, running out of gas at the cap
+	gas := hexutil.Uint64(1000000)
+	res, err := api.TraceCall(context.Background(), ccmapi.CallArgs{From: &sender, To: &contract, Gas: &gas}, rpc.LatestBlockNumber, nil)
+	if err != nil {
+		t.Fatalf("failed to trace call: %v", err)
+	}
+	if result := res.(*ccmapi.ExecutionResult); !result.Failed || result.Gas != 50000 {
+		t.Errorf("call trace mismatch: have failed %v with gas %d, want failure at %d", result.Failed, result.Gas, 50000)
+	}
+	// Raising the cap above the transaction allows tracing it
+	ccm.config.TraceGasCap = big.NewInt(100000)
+	if _, err := api.TraceTransaction(context.Background(), tx.Hash(), nil); err != nil {
+		t.Errorf("failed to trace transaction within cap: %v", err)
+	}
+}
+
+// Tests that traced calls execute on an EVM created by the backend, honouring
+// the VM configuration of the chain besides the tracer.
+func TestTraceCallVMConfig(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0x10")
+		callee = common.HexToAddress("0x20")
+	)
+	// The caller calls the callee without arguments: PUSH1 0 (x5), PUSH1 0x20, GAS, CALL
+	// while the callee only pushes and pops a word: PUSH1 0, POP
+	alloc := core.GenesisAlloc{
+		caller: {Code: []byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x20, 0x5a, 0xf1, 0x00}, Balance: new(big.Int)},
+		callee: {Code: []byte{0x60, 0x00, 0x50, 0x00}, Balance: new(big.Int)},
+	}
+	ccm := newTestCcmchain(t, alloc, 0, nil)
+	defer ccm.blockchain.Stop()
+
+	var (
+		api  = NewPrivateDebugAPI(ccm)
+		from = common.HexToAddress("0x30")
+		gas  = hexutil.Uint64(100000)
+	)
+	// nested counts the opcodes traced within the callee
+	nested := func() int {
+		res, err := api.TraceCall(context.Background(), ccmapi.CallArgs{From: &from, To: &caller, Gas: &gas}, rpc.LatestBlockNumber, nil)
+		if err != nil {
+			t.Fatalf("failed to trace call: %v", err)
+		}
+		result := res.(*ccmapi.ExecutionResult)
+		if result.Failed || len(result.StructLogs) == 0 {
+			t.Fatalf("call trace mismatch: have failed %v with %d logs", result.Failed, len(result.StructLogs))
+		}
+		count := 0
+		for _, log := range result.StructLogs {
+			if log.Depth > 1 {
+				count++
+			}
+		}
+		return count
+	}
+	if n := nested(); n != 3 {
+		t.Errorf("nested opcode count mismatch: have %d, want 3", n)
+	}
+	// Disabling recursion in the chain's VM config must apply to traces too
+	ccm.blockchain.GetVMConfig().NoRecursion = true
+	if n := nested(); n != 0 {
+		t.Errorf("nested opcodes traced despite disabled recursion: have %d", n)
+	}
+}
+
+// BenchmarkConcurrentCalls measures the throughput of running read-only calls in
+// parallel at the same block, each executing on an independent state opened
+// through the bounded state readers of the backend.
+func BenchmarkConcurrentCalls(b *testing.B) {
+	// The contract reads a storage slot: PUSH1 0, SLOAD, POP, STOP
+	contract := common.HexToAddress("0x10")
+	ccm := newTestCcmchain(b, core.GenesisAlloc{contract: {Code: []byte{0x60, 0x00, 0x54, 0x50, 0x00}, Balance: new(big.Int)}}, 10, nil)
+	defer ccm.blockchain.Stop()
+
+	var (
+		backend = ccm.APIBackend
+		from    = common.HexToAddress("0x20")
+		threads = runtime.GOMAXPROCS(0)
+	)
+	if threads > ccmapi.MaxConcurrentStates {
+		threads = ccmapi.MaxConcurrentStates
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		states, header, release, err := backend.StatesAndHeaderByNumber(context.Background(), rpc.LatestBlockNumber, threads)
+		if err != nil {
+			b.Fatalf("failed to open states: %v", err)
+		}
+		var pend sync.WaitGroup
+		for _, statedb := range states {
+			pend.Add(1)
+			go func(statedb *state.StateDB) {
+				defer pend.Done()
+
+				msg := types.NewMessage(from, &contract, 0, new(big.Int), 100000, new(big.Int), nil, false)
+				evm, _, err := backend.GetEVM(context.Background(), msg, statedb, header, nil)
+				if err != nil {
+					b.Errorf("failed to create EVM: %v", err)
+					return
+				}
+				if _, _, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64)); err != nil || failed {
+					b.Errorf("call failed: failed %v, err %v", failed, err)
+				}
+			}(statedb)
+		}
+		pend.Wait()
+		release()
+	}
+}
+
+// Tests that re-executing stored blocks verifies them, and that a block whose
+// header disagrees with its execution is reported with the mismatching fields.
+func TestVerifyBlock(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.HomesteadSigner{}
+	)
+	ccm := newTestCcmchain(t, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ccmchain)}}, 2, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	defer ccm.blockchain.Stop()
+
+	api := NewPrivateDebugAPI(ccm)
+	for _, number := range []rpc.BlockNumber{1, rpc.LatestBlockNumber} {
+		result, err := api.VerifyBlock(context.Background(), number)
+		if err != nil {
+			t.Fatalf("block %d: failed to verify: %v", number, err)
+		}
+		if !result.Valid || len(result.Mismatches) != 0 {
+			t.Errorf("block %d: verification mismatch: have %+v, want valid", number, result)
+		}
+	}
+	for _, number := range []rpc.BlockNumber{0, 3, rpc.PendingBlockNumber} {
+		if _, err := api.VerifyBlock(context.Background(), number); err == nil {
+			t.Errorf("block %d: expected verification error", number)
+		}
+	}
+	// Store a copy of the head with a corrupted header and check it's detected
+	block := ccm.blockchain.CurrentBlock()
+	header := block.Header()
+	header.GasUsed++
+	header.Root = common.Hash{0x01}
+	corrupt := types.NewBlockWithHeader(header).WithBody(block.Transactions(), block.Uncles())
+	rawdb.WriteBlock(ccm.chainDb, corrupt)
+
+	result, err := api.VerifyBlockByHash(context.Background(), corrupt.Hash())
+	if err != nil {
+		t.Fatalf("failed to verify corrupt block: %v", err)
+	}
+	if result.Valid {
+		t.Fatalf("corrupt block reported valid")
+	}
+	fields := make(map[string]bool)
+	for _, mismatch := range result.Mismatches {
+		fields[mismatch.Field] = true
+	}
+	if len(fields) != 2 || !fields["gasUsed"] || !fields["stateRoot"] {
+		t.Errorf("mismatched fields: have %+v, want gasUsed and stateRoot", result.Mismatches)
+	}
+}
+
+// Tests that replaying a mined transaction without overrides reproduces its
+// receipt, and that replaying it with more gas turns the failure into success.
+func TestReplayTransaction(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x10")
+		signer   = types.HomesteadSigner{}
+	)
+	// The contract stores a word: PUSH1 1, PUSH1 0, SSTORE
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ccmchain)},
+		contract: {Code: []byte{0x60, 0x01, 0x60, 0x00, 0x55}, Balance: new(big.Int)},
+	}
+	// Precede the replayed transaction with another one, which it depends on
+	var tx *types.Transaction
+	ccm := newTestCcmchain(t, alloc, 1, func(i int, gen *core.BlockGen) {
+		transfer, _ := types.SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		gen.AddTx(transfer)
+		tx, _ = types.SignTx(types.NewTransaction(1, contract, new(big.Int), 30000, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	defer ccm.blockchain.Stop()
+
+	receipt := ccm.blockchain.GetReceiptsByHash(ccm.blockchain.CurrentBlock().Hash())[1]
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Fatalf("transaction didn't run out of gas")
+	}
+	api := NewPrivateDebugAPI(ccm)
+	result, err := api.ReplayTransaction(context.Background(), tx.Hash(), ReplayOverrides{})
+	if err != nil {
+		t.Fatalf("failed to replay transaction: %v", err)
+	}
+	if result.Error != "" || !result.Failed || uint64(result.Gas) != receipt.GasUsed {
+		t.Errorf("replay mismatch: have failed %v with gas %d (%s), want failure with gas %d", result.Failed, result.Gas, result.Error, receipt.GasUsed)
+	}
+	if !result.OriginalFailed || uint64(result.OriginalGas) != receipt.GasUsed {
+		t.Errorf("original outcome mismatch: have failed %v with gas %d, want failure with gas %d", result.OriginalFailed, result.OriginalGas, receipt.GasUsed)
+	}
+	gas := hexutil.Uint64(100000)
+	if result, err = api.ReplayTransaction(context.Background(), tx.Hash(), ReplayOverrides{Gas: &gas}); err != nil {
+		t.Fatalf("failed to replay transaction with more gas: %v", err)
+	}
+	if result.Error != "" || result.Failed || result.Gas <= 30000 {
+		t.Errorf("replay with more gas mismatch: have failed %v with gas %d (%s), want success above 30000", result.Failed, result.Gas, result.Error)
+	}
+	if _, err := api.ReplayTransaction(context.Background(), common.Hash{0x01}, ReplayOverrides{}); err == nil {
+		t.Errorf("unknown transaction: expected error")
+	}
+}
@@ -18,10 +18,14 @@
 package ccm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -79,6 +83,7 @@ type Ccmchain struct {
 
 	// DB interfaces
 	chainDb ccmdb.Database // Block chain database
+	logsDb  ccmdb.Database // Database serving log queries (replica or the chain database)
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
@@ -137,6 +142,21 @@ func New(ctx *node.ServiceContext, config *Config) (*Ccmchain, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Log queries are served from a read-only replica of the chain database if
+	// one is configured, so heavy filtering doesn't contend with block processing.
+	// The replica is opened once and never refreshed, so logs and bloom bits added
+	// to it after startup are not served until the node is restarted.
+	logsDb := chainDb
+	if config.LogsDatabase != "" {
+		path := ctx.ResolvePath(config.LogsDatabase)
+		if path == "" {
+			return nil, errors.New("logs database replica needs an absolute path on ephemeral nodes")
+		}
+		if logsDb, err = openLogsDatabase(path); err != nil {
+			return nil, err
+		}
+		log.Warn("Serving logs from database replica, data added after startup is not visible", "path", config.LogsDatabase)
+	}
 	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
@@ -146,6 +166,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ccmchain, error) {
 	ccm := &Ccmchain{
 		config:         config,
 		chainDb:        chainDb,
+		logsDb:         logsDb,
 		eventMux:       ctx.EventMux,
 		accountManager: ctx.AccountManager,
 		engine:         CreateConsensusEngine(ctx, chainConfig, &config.Ethash, config.Miner.Notify, config.Miner.Noverify, chainDb),
@@ -215,16 +236,27 @@ func New(ctx *node.ServiceContext, config *Config) (*Ccmchain, error) {
 	ccm.miner = miner.New(ccm, &config.Miner, chainConfig, ccm.EventMux(), ccm.engine, ccm.isLocalBlock)
 	ccm.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
-	ccm.APIBackend = &EthAPIBackend{ctx.ExtRPCEnabled(), ccm, nil}
+	ccm.APIBackend = &EthAPIBackend{ctx.ExtRPCEnabled(), ccm, nil, ccmapi.NewRequestRegistry(config.RPCTimeouts), ccmapi.NewStateReaders(ccmapi.MaxConcurrentStates)}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
 		gpoParams.Default = config.Miner.GasPrice
 	}
 	ccm.APIBackend.gpo = gasprice.NewOracle(ccm.APIBackend, gpoParams)
-
+	if err := ccm.APIBackend.gpo.Backfill(context.Background()); err != nil {
+		log.Warn("Failed to backfill gas price oracle", "err", err)
+	}
 	return ccm, nil
 }
 
+// openLogsDatabase opens an existing replica of the chain database, along with
+// its ancient store, for read-only access.
+func openLogsDatabase(path string) (ccmdb.Database, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("logs database replica unavailable: %v", err)
+	}
+	return rawdb.NewReadOnlyLevelDBDatabaseWithFreezer(path, 16, 16, filepath.Join(path, "ancient"), "ccm/db/logsdata/")
+}
+
 func makeExtraData(extra []byte) []byte {
 	if len(extra) == 0 {
 		// create default extradata
@@ -505,6 +537,33 @@ func (s *Ccmchain) Downloader() *downloader.Downloader { return s.protocolManage
 func (s *Ccmchain) Synced() bool                       { return atomic.LoadUint32(&s.protocolManager.acceptTxs) == 1 }
 func (s *Ccmchain) ArchiveMode() bool                  { return s.config.NoPruning }
 
+// PeerInfo returns the announced chain head of every connected peer, along with
+// its negotiated protocol version and how it relates to the local chain.
+func (s *Ccmchain) PeerInfo() []*PeerChainInfo {
+	current := s.blockchain.CurrentBlock()
+	localTd := s.blockchain.GetTd(current.Hash(), current.NumberU64())
+
+	peers := s.protocolManager.peers.AllPeers()
+	infos := make([]*PeerChainInfo, 0, len(peers))
+	for _, p := range peers {
+		hash, td := p.Head()
+		info := &PeerChainInfo{
+			ID:         p.ID().String(),
+			Name:       p.Name(),
+			Version:    p.version,
+			Head:       hash,
+			Difficulty: td,
+			Ahead:      localTd != nil && td.Cmp(localTd) > 0,
+		}
+		if number := rawdb.ReadHeaderNumber(s.chainDb, hash); number != nil {
+			info.HeadNumber = (*hexutil.Uint64)(number)
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *Ccmchain) Protocols() []p2p.Protocol {
@@ -560,6 +619,9 @@ func (s *Ccmchain) Stop() error {
 	s.miner.Stop()
 	s.eventMux.Stop()
 
+	if s.logsDb != s.chainDb {
+		s.logsDb.Close()
+	}
 	s.chainDb.Close()
 	close(s.shutdownChan)
 	return nil
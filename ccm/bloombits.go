@@ -18,6 +18,7 @@ package ccm
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ccmchain/go-ccmchain/common"
@@ -49,6 +50,8 @@ const (
 
 // startBloomHandlers starts a batch of goroutines to accept bloom bit database
 // retrievals from possibly a range of filters and serving the data to satisfy.
+// If a logs replica is configured, only the sections it contained at startup can
+// be served, later ones fail the retrieval with a missing data error.
 func (ccm *Ccmchain) startBloomHandlers(sectionSize uint64) {
 	for i := 0; i < bloomServiceThreads; i++ {
 		go func() {
@@ -61,8 +64,8 @@ func (ccm *Ccmchain) startBloomHandlers(sectionSize uint64) {
 					task := <-request
 					task.Bitsets = make([][]byte, len(task.Sections))
 					for i, section := range task.Sections {
-						head := rawdb.ReadCanonicalHash(ccm.chainDb, (section+1)*sectionSize-1)
-						if compVector, err := rawdb.ReadBloomBits(ccm.chainDb, task.Bit, section, head); err == nil {
+						head := rawdb.ReadCanonicalHash(ccm.logsDb, (section+1)*sectionSize-1)
+						if compVector, err := rawdb.ReadBloomBits(ccm.logsDb, task.Bit, section, head); err == nil {
 							if blob, err := bitutil.DecompressBytes(compVector, int(sectionSize/8)); err == nil {
 								task.Bitsets[i] = blob
 							} else {
@@ -136,3 +139,29 @@ func (b *BloomIndexer) Commit() error {
 	}
 	return batch.Write()
 }
+
+// rebuildBloomSection re-derives the bloom bits of an already indexed section
+// from the canonical headers and overwrites the index data stored for it.
+func rebuildBloomSection(ctx context.Context, db ccmdb.Database, chain *core.BlockChain, indexer *core.ChainIndexer, size, section uint64) error {
+	backend := &BloomIndexer{db: db, size: size}
+	if err := backend.Reset(ctx, section, common.Hash{}); err != nil {
+		return err
+	}
+	for number := section * size; number < (section+1)*size; number++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return fmt.Errorf("canonical header #%d missing", number)
+		}
+		if err := backend.Process(ctx, header); err != nil {
+			return err
+		}
+	}
+	// Make sure the chain didn't reorg away from the indexed section in between
+	if head := indexer.SectionHead(section); head != backend.head {
+		return fmt.Errorf("section %d head mismatch: indexed %x, canonical %x", section, head, backend.head)
+	}
+	return backend.Commit()
+}
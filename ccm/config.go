@@ -51,12 +51,16 @@ var DefaultConfig = Config{
 	TrieDirtyCache:     256,
 	TrieTimeout:        60 * time.Minute,
 	Miner: miner.Config{
-		GasFloor: 8000000,
-		GasCeil:  8000000,
-		GasPrice: big.NewInt(params.GWei),
-		Recommit: 3 * time.Second,
+		GasFloor:  8000000,
+		GasCeil:   8000000,
+		GasPrice:  big.NewInt(params.GWei),
+		Recommit:  3 * time.Second,
+		MaxUncles: 2,
 	},
-	TxPool: core.DefaultTxPoolConfig,
+	TxPool:         core.DefaultTxPoolConfig,
+	FinalityDepth:  12,
+	SafeDepth:      6,
+	RPCCallDataCap: 4 * 1024 * 1024,
 	GPO: gasprice.Config{
 		Blocks:     20,
 		Percentile: 60,
@@ -117,6 +121,7 @@ type Config struct {
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
 	DatabaseFreezer    string
+	LogsDatabase       string `toml:",omitempty"` // Read-only replica of the chain database to serve logs from (snapshot at startup)
 
 	TrieCleanCache int
 	TrieDirtyCache int
@@ -149,6 +154,32 @@ type Config struct {
 	// RPCGasCap is the global gas cap for ccm-call variants.
 	RPCGasCap *big.Int `toml:",omitempty"`
 
+	// TraceGasCap is the global gas cap for debug tracing. Traced calls are capped
+	// to it and mined transactions above it are refused. If unset, the gas limit
+	// of the block the execution is traced on top of is used.
+	TraceGasCap *big.Int `toml:",omitempty"`
+
+	// RPCCallDataCap is the maximum size in bytes of the input data accepted by
+	// ccm_call and its variants. Zero means no limit.
+	RPCCallDataCap uint64 `toml:",omitempty"`
+
+	// RPCTimeouts overrides the execution timeouts of heavy RPC methods, keyed by
+	// method name (e.g. "ccm_call") or class ("call", "estimate" or "trace").
+	RPCTimeouts map[string]time.Duration `toml:",omitempty"`
+
+	// RPCMinGasPrice is the minimum gas price enforced on transactions submitted
+	// through the RPC APIs, on top of the transaction pool's own price limit. It
+	// isn't applied to transactions entering the pool any other way.
+	RPCMinGasPrice *big.Int `toml:",omitempty"`
+
+	// FinalityDepth is the number of confirmations after which a block is
+	// considered final on chains without signer based finality (i.e. non-clique).
+	FinalityDepth uint64 `toml:",omitempty"`
+
+	// SafeDepth is the number of confirmations after which a block is resolved
+	// by the "safe" block tag, which is unlikely but not guaranteed to be final.
+	SafeDepth uint64 `toml:",omitempty"`
+
 	// Checkpoint is a hardcoded checkpoint which can be nil.
 	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
 
@@ -89,6 +89,7 @@ var (
 	errCancelContentProcessing = errors.New("content processing canceled (requested)")
 	errCanceled                = errors.New("syncing canceled (requested)")
 	errNoSyncActive            = errors.New("no sync active")
+	errPaused                  = errors.New("synchronisation paused")
 	errTooOld                  = errors.New("peer doesn't speak recent enough protocol version (need version >= 62)")
 )
 
@@ -126,6 +127,7 @@ type Downloader struct {
 	// Status
 	synchroniseMock func(id string, hash common.Hash) error // Replacement for synchronise during testing
 	synchronising   int32
+	paused          int32 // Flag whether new synchronisations are refused
 	notified        int32
 	committed       int32
 	ancientLimit    uint64 // The maximum block number which can be regarded as ancient data.
@@ -282,6 +284,37 @@ func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
 }
 
+// Pause aborts any synchronisation in progress and refuses new ones until the
+// downloader is resumed. It returns false if the downloader was already paused.
+func (d *Downloader) Pause() bool {
+	// Set the flag under the cancel lock so that a concurrently starting sync
+	// either sees it, or creates its cancel channel before it is closed below
+	d.cancelLock.Lock()
+	paused := atomic.CompareAndSwapInt32(&d.paused, 0, 1)
+	d.cancelLock.Unlock()
+
+	if paused {
+		log.Info("Block synchronisation paused")
+		d.Cancel()
+	}
+	return paused
+}
+
+// Resume allows new synchronisations to start after a pause. It returns false
+// if the downloader was not paused.
+func (d *Downloader) Resume() bool {
+	resumed := atomic.CompareAndSwapInt32(&d.paused, 1, 0)
+	if resumed {
+		log.Info("Block synchronisation resumed")
+	}
+	return resumed
+}
+
+// Paused returns whether new synchronisations are currently refused.
+func (d *Downloader) Paused() bool {
+	return atomic.LoadInt32(&d.paused) == 1
+}
+
 // RegisterPeer injects a new download peer into the set of block source to be
 // used for fetching hashes and blocks from.
 func (d *Downloader) RegisterPeer(id string, version int, peer Peer) error {
@@ -323,7 +356,7 @@ func (d *Downloader) Synchronise(id string, head common.Hash, td *big.Int, mode
 	err := d.synchronise(id, head, td, mode)
 	switch err {
 	case nil:
-	case errBusy, errCanceled:
+	case errBusy, errCanceled, errPaused:
 
 	case errTimeout, errBadPeer, errStallingPeer, errUnsyncedPeer,
 		errEmptyHeaderSet, errPeersUnavailable, errTooOld,
@@ -350,6 +383,9 @@ func (d *Downloader) synchronise(id string, hash common.Hash, td *big.Int, mode
 	if d.synchroniseMock != nil {
 		return d.synchroniseMock(id, hash)
 	}
+	if d.Paused() {
+		return errPaused
+	}
 	// Make sure only one goroutine is ever allowed past this point at once
 	if !atomic.CompareAndSwapInt32(&d.synchronising, 0, 1) {
 		return errBusy
@@ -394,6 +430,10 @@ func (d *Downloader) synchronise(id string, hash common.Hash, td *big.Int, mode
 	}
 	// Create cancel channel for aborting mid-flight and mark the master peer
 	d.cancelLock.Lock()
+	if d.Paused() {
+		d.cancelLock.Unlock()
+		return errPaused
+	}
 	d.cancelCh = make(chan struct{})
 	d.cancelPeer = id
 	d.cancelLock.Unlock()
@@ -789,6 +789,43 @@ func testCancel(t *testing.T, protocol int, mode SyncMode) {
 	}
 }
 
+// Tests that a paused downloader refuses to synchronise until it's resumed.
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	chain := testChainBase.shorten(MaxHeaderFetch)
+	tester.newPeer("peer", 64, chain)
+
+	if !tester.downloader.Pause() {
+		t.Fatalf("failed to pause pristine downloader")
+	}
+	if tester.downloader.Pause() {
+		t.Errorf("paused downloader paused again")
+	}
+	if !tester.downloader.Paused() {
+		t.Errorf("paused downloader reported running")
+	}
+	head := chain.headBlock().Hash()
+	if err := tester.downloader.synchronise("peer", head, chain.td(head), FullSync); err != errPaused {
+		t.Fatalf("synchronisation error mismatch: have %v, want %v", err, errPaused)
+	}
+	assertOwnChain(t, tester, 1)
+
+	if !tester.downloader.Resume() {
+		t.Fatalf("failed to resume paused downloader")
+	}
+	if tester.downloader.Resume() {
+		t.Errorf("running downloader resumed again")
+	}
+	if err := tester.sync("peer", nil, FullSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+}
+
 // Tests that synchronisation from multiple peers works as intended (multi thread sanity test).
 func TestMultiSynchronisation62(t *testing.T)      { testMultiSynchronisation(t, 62, FullSync) }
 func TestMultiSynchronisation63Full(t *testing.T)  { testMultiSynchronisation(t, 63, FullSync) }
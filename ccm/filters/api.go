@@ -330,13 +330,13 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 		filter = NewBlockFilter(api.backend, *crit.BlockHash, crit.Addresses, crit.Topics)
 	} else {
 		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
+		begin, err := api.resolveBlockNumber(ctx, crit.FromBlock)
+		if err != nil {
+			return nil, err
 		}
-		end := rpc.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
+		end, err := api.resolveBlockNumber(ctx, crit.ToBlock)
+		if err != nil {
+			return nil, err
 		}
 		// Construct the range filter
 		filter = NewRangeFilter(api.backend, begin, end, crit.Addresses, crit.Topics)
@@ -349,6 +349,28 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	return returnLogs(logs), err
 }
 
+// resolveBlockNumber converts an RPC block number of a filter range into the
+// internal representation, defaulting to the latest block. The range filter only
+// understands the latest tag, so the safe and finalized tags are resolved into
+// the number of the block they currently refer to.
+func (api *PublicFilterAPI) resolveBlockNumber(ctx context.Context, number *big.Int) (int64, error) {
+	if number == nil {
+		return rpc.LatestBlockNumber.Int64(), nil
+	}
+	tag := rpc.BlockNumber(number.Int64())
+	if tag != rpc.SafeBlockNumber && tag != rpc.FinalizedBlockNumber {
+		return number.Int64(), nil
+	}
+	header, err := api.backend.HeaderByNumber(ctx, tag)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, errors.New("unknown block")
+	}
+	return header.Number.Int64(), nil
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ccmchain/wiki/wiki/JSON-RPC#ccm_uninstallfilter
@@ -385,13 +407,13 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 		filter = NewBlockFilter(api.backend, *f.crit.BlockHash, f.crit.Addresses, f.crit.Topics)
 	} else {
 		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if f.crit.FromBlock != nil {
-			begin = f.crit.FromBlock.Int64()
+		begin, err := api.resolveBlockNumber(ctx, f.crit.FromBlock)
+		if err != nil {
+			return nil, err
 		}
-		end := rpc.LatestBlockNumber.Int64()
-		if f.crit.ToBlock != nil {
-			end = f.crit.ToBlock.Int64()
+		end, err := api.resolveBlockNumber(ctx, f.crit.ToBlock)
+		if err != nil {
+			return nil, err
 		}
 		// Construct the range filter
 		filter = NewRangeFilter(api.backend, begin, end, f.crit.Addresses, f.crit.Topics)
@@ -19,7 +19,9 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/core"
@@ -57,6 +59,7 @@ type Filter struct {
 
 	block      common.Hash // Block hash if filtering a single block
 	begin, end int64       // Range interval if filtering multiple blocks
+	limit      int         // Maximum number of logs to collect (0 = unlimited)
 
 	matcher *bloombits.Matcher
 }
@@ -114,6 +117,22 @@ func newFilter(backend Backend, addresses []common.Address, topics [][]common.Ha
 	}
 }
 
+// SetLimit caps the number of logs a search may collect. The search is aborted
+// with an error as soon as more logs are found, without scanning the rest of
+// the range. A zero limit disables the cap.
+func (f *Filter) SetLimit(limit int) {
+	f.limit = limit
+}
+
+// checkLimit returns an error if the number of collected logs exceeds the limit
+// set on the filter.
+func (f *Filter) checkLimit(collected int) error {
+	if f.limit > 0 && collected > f.limit {
+		return fmt.Errorf("query returned more than %d results", f.limit)
+	}
+	return nil
+}
+
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
@@ -126,7 +145,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
-		return f.blockLogs(ctx, header)
+		logs, err := f.blockLogs(ctx, header)
+		sortLogs(logs)
+		return logs, err
 	}
 	// Figure out the limits of the filter range
 	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
@@ -158,11 +179,28 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 			return logs, err
 		}
 	}
-	rest, err := f.unindexedLogs(ctx, end)
-	logs = append(logs, rest...)
+	logs, err = f.unindexedLogs(ctx, end, logs)
+	sortLogs(logs)
 	return logs, err
 }
 
+// sortLogs orders logs by block number, then transaction index, then log index.
+// Consumers indexing logs incrementally rely on this order, which must hold no
+// matter in which order blocks are delivered by the bloombits matcher. Logs with
+// equal positions (e.g. underived light client logs) keep their relative order.
+func sortLogs(logs []*types.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		a, b := logs[i], logs[j]
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		if a.TxIndex != b.TxIndex {
+			return a.TxIndex < b.TxIndex
+		}
+		return a.Index < b.Index
+	})
+}
+
 // indexedLogs returns the logs matching the filter criteria based on the bloom
 // bits indexed available locally or via the network.
 func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
@@ -203,6 +241,9 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 				return logs, err
 			}
 			logs = append(logs, found...)
+			if err := f.checkLimit(len(logs)); err != nil {
+				return logs, err
+			}
 
 		case <-ctx.Done():
 			return logs, ctx.Err()
@@ -210,11 +251,9 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 	}
 }
 
-// indexedLogs returns the logs matching the filter criteria based on raw block
-// iteration and bloom matching.
-func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
-	var logs []*types.Log
-
+// unindexedLogs appends the logs matching the filter criteria based on raw block
+// iteration and bloom matching to the already collected ones.
+func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logs []*types.Log) ([]*types.Log, error) {
 	for ; f.begin <= int64(end); f.begin++ {
 		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.begin))
 		if header == nil || err != nil {
@@ -225,6 +264,9 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 			return logs, err
 		}
 		logs = append(logs, found...)
+		if err := f.checkLimit(len(logs)); err != nil {
+			return logs, err
+		}
 	}
 	return logs, nil
 }
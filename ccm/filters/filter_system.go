@@ -71,7 +71,8 @@ const (
 )
 
 var (
-	ErrInvalidSubscriptionID = errors.New("invalid id")
+	ErrInvalidSubscriptionID    = errors.New("invalid id")
+	ErrConfirmedTagSubscription = errors.New("safe and finalized block tags are not supported by log subscriptions")
 )
 
 type subscription struct {
@@ -194,7 +195,9 @@ func (es *EventSystem) subscribe(sub *subscription) *Subscription {
 
 // SubscribeLogs creates a subscription that will write all logs matching the
 // given criteria to the given logs channel. Default value for the from and to
-// block is "latest". If the fromBlock > toBlock an error is returned.
+// block is "latest". If the fromBlock > toBlock an error is returned. The safe
+// and finalized tags move with the chain head and are rejected, since live logs
+// are delivered as soon as their block is imported.
 func (es *EventSystem) SubscribeLogs(crit ccmchain.FilterQuery, logs chan []*types.Log) (*Subscription, error) {
 	var from, to rpc.BlockNumber
 	if crit.FromBlock == nil {
@@ -207,6 +210,11 @@ func (es *EventSystem) SubscribeLogs(crit ccmchain.FilterQuery, logs chan []*typ
 	} else {
 		to = rpc.BlockNumber(crit.ToBlock.Int64())
 	}
+	for _, number := range []rpc.BlockNumber{from, to} {
+		if number == rpc.SafeBlockNumber || number == rpc.FinalizedBlockNumber {
+			return nil, ErrConfirmedTagSubscription
+		}
+	}
 
 	// only interested in pending logs
 	if from == rpc.PendingBlockNumber && to == rpc.PendingBlockNumber {
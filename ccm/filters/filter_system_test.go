@@ -56,7 +56,24 @@ func (b *testBackend) EventMux() *event.TypeMux {
 	return b.mux
 }
 
+// Depths the test backend resolves the safe and finalized block tags with.
+const (
+	testSafeDepth     = 1
+	testFinalityDepth = 2
+)
+
 func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	if blockNr == rpc.SafeBlockNumber || blockNr == rpc.FinalizedBlockNumber {
+		head, _ := b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if head == nil {
+			return nil, nil
+		}
+		depth := uint64(testSafeDepth)
+		if blockNr == rpc.FinalizedBlockNumber {
+			depth = testFinalityDepth
+		}
+		blockNr = rpc.BlockNumber(head.Number.Uint64() - depth)
+	}
 	var (
 		hash common.Hash
 		num  uint64
@@ -351,6 +368,38 @@ func TestInvalidLogFilterCreation(t *testing.T) {
 	}
 }
 
+// TestConfirmedTagLogFilterCreation tests whether log filters ranging over the safe
+// or finalized block tags are rejected with a clear error when created.
+func TestConfirmedTagLogFilterCreation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = rawdb.NewMemoryDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+
+		safe      = big.NewInt(rpc.SafeBlockNumber.Int64())
+		finalized = big.NewInt(rpc.FinalizedBlockNumber.Int64())
+	)
+	testCases := []FilterCriteria{
+		0: {FromBlock: safe},
+		1: {FromBlock: finalized},
+		2: {FromBlock: big.NewInt(1), ToBlock: safe},
+		3: {FromBlock: finalized, ToBlock: big.NewInt(rpc.LatestBlockNumber.Int64())},
+		4: {FromBlock: finalized, ToBlock: safe},
+	}
+	for i, test := range testCases {
+		if _, err := api.NewFilter(test); err != ErrConfirmedTagSubscription {
+			t.Errorf("case %d: error mismatch: have %v, want %v", i, err, ErrConfirmedTagSubscription)
+		}
+	}
+}
+
 func TestInvalidGetLogsRequest(t *testing.T) {
 	var (
 		mux        = new(event.TypeMux)
@@ -378,6 +427,62 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 	}
 }
 
+// TestGetLogsConfirmedTags tests that the safe and finalized block tags are
+// resolved to the blocks they refer to when used as the bounds of a log query.
+func TestGetLogsConfirmedTags(t *testing.T) {
+	var (
+		mux        = new(event.TypeMux)
+		db         = rawdb.NewMemoryDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+		addr       = common.HexToAddress("0xc0ffee")
+	)
+	// Create a chain of 4 blocks, each emitting a single log
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 4, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr}}
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	var (
+		safe      = big.NewInt(rpc.SafeBlockNumber.Int64())
+		finalized = big.NewInt(rpc.FinalizedBlockNumber.Int64())
+	)
+	tests := []struct {
+		from, to *big.Int
+		want     []uint64
+	}{
+		{from: safe, want: []uint64{3, 4}},
+		{from: finalized, to: safe, want: []uint64{2, 3}},
+		{from: big.NewInt(1), to: finalized, want: []uint64{1, 2}},
+		{from: safe, to: finalized},
+	}
+	for i, tt := range tests {
+		logs, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: tt.from, ToBlock: tt.to, Addresses: []common.Address{addr}})
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve logs: %v", i, err)
+		}
+		var have []uint64
+		for _, log := range logs {
+			have = append(have, log.BlockNumber)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: log blocks mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
 // TestLogFilter tests whccmer log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
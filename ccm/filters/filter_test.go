@@ -234,3 +234,124 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// Tests that logs are returned ordered by block number, transaction index and
+// log index, both for ranges and single blocks.
+func TestFilterLogOrder(t *testing.T) {
+	var (
+		db         = rawdb.NewMemoryDatabase()
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		addr       = common.HexToAddress("0xc0ffee")
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 4, func(i int, gen *core.BlockGen) {
+		// Every transaction emits several logs
+		for tx := 0; tx < 3; tx++ {
+			receipt := types.NewReceipt(nil, false, 0)
+			for log := 0; log < 3; log++ {
+				receipt.Logs = append(receipt.Logs, &types.Log{
+					Address: addr,
+					Topics:  []common.Hash{common.BigToHash(big.NewInt(int64(i*100 + tx*10 + log)))},
+				})
+			}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(tx), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		}
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	checkOrder := func(logs []*types.Log, want int) {
+		t.Helper()
+		if len(logs) != want {
+			t.Fatalf("log count mismatch: have %d, want %d", len(logs), want)
+		}
+		for i := 1; i < len(logs); i++ {
+			prev, cur := logs[i-1], logs[i]
+			if prev.BlockNumber > cur.BlockNumber ||
+				(prev.BlockNumber == cur.BlockNumber && prev.TxIndex > cur.TxIndex) ||
+				(prev.BlockNumber == cur.BlockNumber && prev.TxIndex == cur.TxIndex && prev.Index >= cur.Index) {
+				t.Fatalf("log %d out of order: block %d tx %d index %d after block %d tx %d index %d",
+					i, cur.BlockNumber, cur.TxIndex, cur.Index, prev.BlockNumber, prev.TxIndex, prev.Index)
+			}
+			if prev.Topics[0].Big().Cmp(cur.Topics[0].Big()) >= 0 {
+				t.Fatalf("log %d emitted out of order: %x after %x", i, cur.Topics[0], prev.Topics[0])
+			}
+		}
+	}
+	logs, err := NewRangeFilter(backend, 0, -1, []common.Address{addr}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(logs, 4*3*3)
+
+	logs, err = NewBlockFilter(backend, chain[2].Hash(), nil, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(logs, 3*3)
+
+	// Logs delivered out of order must be restored to chain order
+	shuffled := make([]*types.Log, len(logs))
+	for i, log := range logs {
+		shuffled[len(logs)-1-i] = log
+	}
+	sortLogs(shuffled)
+	checkOrder(shuffled, 3*3)
+}
+
+// Tests that a range filter with a limit aborts the search as soon as more logs
+// than allowed are collected, instead of scanning the remainder of the range.
+func TestFilterLimit(t *testing.T) {
+	var (
+		db         = rawdb.NewMemoryDatabase()
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		addr       = common.HexToAddress("0xc0ffee")
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {
+		// Every block emits two logs
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr}, {Address: addr}}
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// A limit covering all the logs must not interfere
+	filter := NewRangeFilter(backend, 1, 10, []common.Address{addr}, nil)
+	filter.SetLimit(20)
+	logs, err := filter.Logs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve logs within limit: %v", err)
+	}
+	if len(logs) != 20 {
+		t.Fatalf("log count mismatch: have %d, want %d", len(logs), 20)
+	}
+	// Exceeding the limit must fail and stop at the first offending block
+	filter = NewRangeFilter(backend, 1, 10, []common.Address{addr}, nil)
+	filter.SetLimit(5)
+	if _, err := filter.Logs(context.Background()); err == nil {
+		t.Fatalf("log limit not enforced")
+	}
+	if filter.begin != 3 {
+		t.Fatalf("search not aborted at limit: stopped before block %d, want %d", filter.begin, 3)
+	}
+}
@@ -18,6 +18,7 @@ package gasprice
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"sort"
 	"sync"
@@ -25,29 +26,53 @@ import (
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
+	"github.com/ccmchain/go-ccmchain/log"
 	"github.com/ccmchain/go-ccmchain/params"
 	"github.com/ccmchain/go-ccmchain/rpc"
 )
 
 var maxPrice = big.NewInt(500 * params.GWei)
 
+// Price tiers supported by SuggestPriceTier.
+const (
+	TierSafe     = "safe"     // Cheaper price, slower inclusion
+	TierStandard = "standard" // Configured percentile, same as SuggestPrice
+	TierFast     = "fast"     // Higher price, faster inclusion
+)
+
+// TxFilter reports whether a transaction should be sampled by the oracle.
+type TxFilter func(tx *types.Transaction) bool
+
+// DefaultTxFilter excludes the transactions not priced by the fee market, i.e.
+// the ones paying no gas price at all (e.g. system or deposit transactions),
+// which would otherwise drag the suggestions down.
+func DefaultTxFilter(tx *types.Transaction) bool {
+	return tx.GasPrice().Sign() > 0
+}
+
 type Config struct {
 	Blocks     int
 	Percentile int
 	Default    *big.Int `toml:",omitempty"`
+	SampleAll  bool     `toml:",omitempty"` // Disables transaction filtering, sampling every transaction
+	Filter     TxFilter `toml:"-"`          // Transactions to sample, nil means DefaultTxFilter
 }
 
 // Oracle recommends gas prices based on the content of recent
 // blocks. Suitable for both light and full clients.
 type Oracle struct {
-	backend   ccmapi.Backend
-	lastHead  common.Hash
-	lastPrice *big.Int
-	cacheLock sync.RWMutex
-	fetchLock sync.Mutex
+	backend     ccmapi.Backend
+	lastHead    common.Hash
+	lastPrice   *big.Int
+	lastPrices  []*big.Int // Sorted block prices the last price was picked from
+	lastTipHead common.Hash
+	lastTip     *big.Int
+	cacheLock   sync.RWMutex
+	fetchLock   sync.Mutex
 
 	checkBlocks, maxEmpty, maxBlocks int
 	percentile                       int
+	filter                           TxFilter // Transactions to sample, nil if all are
 }
 
 // NewOracle returns a new oracle.
@@ -63,6 +88,13 @@ func NewOracle(backend ccmapi.Backend, params Config) *Oracle {
 	if percent > 100 {
 		percent = 100
 	}
+	filter := params.Filter
+	if filter == nil {
+		filter = DefaultTxFilter
+	}
+	if params.SampleAll {
+		filter = nil
+	}
 	return &Oracle{
 		backend:     backend,
 		lastPrice:   params.Default,
@@ -70,9 +102,21 @@ func NewOracle(backend ccmapi.Backend, params Config) *Oracle {
 		maxEmpty:    blocks / 2,
 		maxBlocks:   blocks * 5,
 		percentile:  percent,
+		filter:      filter,
 	}
 }
 
+// Filtering reports whether the oracle only samples the transactions accepted
+// by a filter, as opposed to every transaction in the checked blocks.
+func (gpo *Oracle) Filtering() bool {
+	return gpo.filter != nil
+}
+
+// sampled reports whether the given transaction is considered by the oracle.
+func (gpo *Oracle) sampled(tx *types.Transaction) bool {
+	return gpo.filter == nil || gpo.filter(tx)
+}
+
 // SuggestPrice returns the recommended gas price.
 func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	gpo.cacheLock.RLock()
@@ -143,10 +187,113 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	gpo.cacheLock.Lock()
 	gpo.lastHead = headHash
 	gpo.lastPrice = price
+	gpo.lastPrices = blockPrices
 	gpo.cacheLock.Unlock()
 	return price, nil
 }
 
+// Backfill eagerly samples the recently stored blocks, so that the first price
+// suggestion after a restart doesn't have to wait for the chain head to advance
+// or fall back to the configured default.
+func (gpo *Oracle) Backfill(ctx context.Context) error {
+	price, err := gpo.SuggestPrice(ctx)
+	if err != nil {
+		return err
+	}
+	gpo.cacheLock.RLock()
+	samples := len(gpo.lastPrices)
+	gpo.cacheLock.RUnlock()
+
+	log.Debug("Backfilled gas price oracle", "price", price, "samples", samples)
+	return nil
+}
+
+// SuggestPriceTier returns the recommended gas price for the given speed tier.
+// The standard tier is the configured percentile of recent block prices (i.e.
+// the same as SuggestPrice), while the safe and fast tiers use the percentiles
+// halfway below and above it respectively.
+func (gpo *Oracle) SuggestPriceTier(ctx context.Context, tier string) (*big.Int, error) {
+	var percentile int
+	switch tier {
+	case TierSafe:
+		percentile = gpo.percentile / 2
+	case TierStandard:
+		percentile = gpo.percentile
+	case TierFast:
+		percentile = gpo.percentile + (100-gpo.percentile)/2
+	default:
+		return nil, fmt.Errorf("unknown price tier %q, want %s, %s or %s", tier, TierSafe, TierStandard, TierFast)
+	}
+	price, err := gpo.SuggestPrice(ctx)
+	if err != nil || tier == TierStandard {
+		return price, err
+	}
+	gpo.cacheLock.RLock()
+	prices := gpo.lastPrices
+	gpo.cacheLock.RUnlock()
+
+	// Without recent block prices, all tiers collapse onto the standard one
+	if len(prices) == 0 {
+		return price, nil
+	}
+	price = prices[(len(prices)-1)*percentile/100]
+	if price.Cmp(maxPrice) > 0 {
+		price = new(big.Int).Set(maxPrice)
+	}
+	return price, nil
+}
+
+// SuggestPriorityFee returns a recommended priority fee (tip) to pay on top of
+// the minimum price for timely inclusion. Without a base fee the full gas price
+// of a transaction acts as its tip, so the estimate is the configured percentile
+// of the gas prices of all transactions included in the recently checked blocks.
+// Unlike SuggestPrice, it considers all transactions rather than only the
+// cheapest one of each block.
+func (gpo *Oracle) SuggestPriorityFee(ctx context.Context) (*big.Int, error) {
+	head, _ := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	headHash := head.Hash()
+
+	gpo.cacheLock.RLock()
+	lastTipHead, lastTip := gpo.lastTipHead, gpo.lastTip
+	gpo.cacheLock.RUnlock()
+	if headHash == lastTipHead && lastTip != nil {
+		return lastTip, nil
+	}
+	var prices []*big.Int
+	for number := head.Number.Uint64(); number > 0 && head.Number.Uint64()-number < uint64(gpo.checkBlocks); number-- {
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+		signer := types.MakeSigner(gpo.backend.ChainConfig(), block.Number())
+		for _, tx := range block.Transactions() {
+			if !gpo.sampled(tx) {
+				continue
+			}
+			if sender, err := types.Sender(signer, tx); err == nil && sender != block.Coinbase() {
+				prices = append(prices, tx.GasPrice())
+			}
+		}
+	}
+	// Without any recent transactions, fall back to the regular price suggestion
+	if len(prices) == 0 {
+		return gpo.SuggestPrice(ctx)
+	}
+	sort.Sort(bigIntArray(prices))
+	tip := prices[(len(prices)-1)*gpo.percentile/100]
+	if tip.Cmp(maxPrice) > 0 {
+		tip = new(big.Int).Set(maxPrice)
+	}
+	gpo.cacheLock.Lock()
+	gpo.lastTipHead = headHash
+	gpo.lastTip = tip
+	gpo.cacheLock.Unlock()
+	return tip, nil
+}
+
 type getBlockPricesResult struct {
 	price *big.Int
 	err   error
@@ -173,6 +320,9 @@ func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, bloc
 	sort.Sort(transactionsByGasPrice(txs))
 
 	for _, tx := range txs {
+		if !gpo.sampled(tx) {
+			continue
+		}
 		sender, err := types.Sender(signer, tx)
 		if err == nil && sender != block.Coinbase() {
 			ch <- getBlockPricesResult{tx.GasPrice(), nil}
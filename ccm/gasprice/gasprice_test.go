@@ -0,0 +1,252 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/rawdb"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/crypto"
+	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
+	"github.com/ccmchain/go-ccmchain/params"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// testBackend serves the blocks of a pre-generated chain to the oracle. All the
+// backend methods not needed by the oracle are left unimplemented.
+type testBackend struct {
+	ccmapi.Backend
+	blocks []*types.Block
+}
+
+func (b *testBackend) block(number rpc.BlockNumber) *types.Block {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.blocks[len(b.blocks)-1]
+	}
+	if int(number) >= len(b.blocks) {
+		return nil
+	}
+	return b.blocks[number]
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if block := b.block(number); block != nil {
+		return block.Header(), nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	return b.block(number), nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+// newTestBackend creates a chain of the given length in which every block holds
+// a single transaction paying (block number) gwei per gas.
+func newTestBackend(t *testing.T, n int) *testBackend {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		db     = rawdb.NewMemoryDatabase()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ccmchain)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, n, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+
+		price := new(big.Int).Mul(big.NewInt(int64(i+1)), big.NewInt(params.GWei))
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{2}, big.NewInt(1), params.TxGas, price, nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		b.AddTx(tx)
+	})
+	return &testBackend{blocks: append([]*types.Block{genesis}, blocks...)}
+}
+
+// Tests that an oracle backfilled right after a restart suggests the same price
+// as one that has been running on the same chain all along.
+func TestBackfill(t *testing.T) {
+	backend := newTestBackend(t, 32)
+	config := Config{Blocks: 20, Percentile: 60, Default: big.NewInt(params.GWei)}
+
+	// Calculate the steady state suggestion of a long running oracle
+	running := NewOracle(backend, config)
+	want, err := running.SuggestPrice(context.Background())
+	if err != nil {
+		t.Fatalf("failed to suggest price: %v", err)
+	}
+	if want.Cmp(config.Default) == 0 {
+		t.Fatalf("steady state price equals the default")
+	}
+	// Simulate a restart and check that the backfilled oracle agrees without
+	// needing any further sampling
+	restarted := NewOracle(backend, config)
+	if err := restarted.Backfill(context.Background()); err != nil {
+		t.Fatalf("failed to backfill oracle: %v", err)
+	}
+	restarted.cacheLock.RLock()
+	lastHead, lastPrice := restarted.lastHead, restarted.lastPrice
+	restarted.cacheLock.RUnlock()
+
+	if head := backend.blocks[len(backend.blocks)-1].Hash(); lastHead != head {
+		t.Errorf("backfilled head mismatch: have %x, want %x", lastHead, head)
+	}
+	if lastPrice.Cmp(want) != 0 {
+		t.Errorf("backfilled price mismatch: have %v, want %v", lastPrice, want)
+	}
+	have, err := restarted.SuggestPrice(context.Background())
+	if err != nil {
+		t.Fatalf("failed to suggest price: %v", err)
+	}
+	if have.Cmp(want) != 0 {
+		t.Errorf("price mismatch after restart: have %v, want %v", have, want)
+	}
+}
+
+// Tests that zero gas price transactions are excluded from the samples by
+// default, but included if the oracle is configured to sample everything.
+func TestTxFilter(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		db     = rawdb.NewMemoryDatabase()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ccmchain)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	// Every block holds a free transaction next to one paying 10 gwei per gas
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 8, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+		for _, price := range []*big.Int{new(big.Int), big.NewInt(10 * params.GWei)} {
+			tx, err := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{2}, big.NewInt(1), params.TxGas, price, nil), signer, key)
+			if err != nil {
+				t.Fatalf("failed to sign transaction: %v", err)
+			}
+			b.AddTx(tx)
+		}
+	})
+	backend := &testBackend{blocks: append([]*types.Block{genesis}, blocks...)}
+
+	tests := []struct {
+		config    Config
+		filtering bool
+		want      *big.Int
+	}{
+		{Config{Blocks: 4, Percentile: 50}, true, big.NewInt(10 * params.GWei)},
+		{Config{Blocks: 4, Percentile: 50, SampleAll: true}, false, new(big.Int)},
+		{Config{Blocks: 4, Percentile: 50, Filter: func(tx *types.Transaction) bool { return true }}, true, new(big.Int)},
+	}
+	for i, tt := range tests {
+		oracle := NewOracle(backend, tt.config)
+		if oracle.Filtering() != tt.filtering {
+			t.Errorf("test %d: filtering mismatch: have %v, want %v", i, oracle.Filtering(), tt.filtering)
+		}
+		price, err := oracle.SuggestPrice(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to suggest price: %v", i, err)
+		}
+		if price.Cmp(tt.want) != 0 {
+			t.Errorf("test %d: price mismatch: have %v, want %v", i, price, tt.want)
+		}
+	}
+}
+
+// Tests that the priority fee is the configured percentile of the prices of all
+// transactions in the recently checked blocks, falling back to the regular price
+// suggestion without any.
+func TestSuggestPriorityFee(t *testing.T) {
+	config := Config{Blocks: 20, Percentile: 60, Default: big.NewInt(params.GWei)}
+
+	// Blocks 13..32 are checked, paying 13..32 gwei, the 60th percentile is 24
+	backend := newTestBackend(t, 32)
+	oracle := NewOracle(backend, config)
+	tip, err := oracle.SuggestPriorityFee(context.Background())
+	if err != nil {
+		t.Fatalf("failed to suggest priority fee: %v", err)
+	}
+	if want := big.NewInt(24 * params.GWei); tip.Cmp(want) != 0 {
+		t.Errorf("priority fee mismatch: have %v, want %v", tip, want)
+	}
+	oracle.cacheLock.RLock()
+	lastTipHead := oracle.lastTipHead
+	oracle.cacheLock.RUnlock()
+	if head := backend.blocks[len(backend.blocks)-1].Hash(); lastTipHead != head {
+		t.Errorf("cached head mismatch: have %x, want %x", lastTipHead, head)
+	}
+	// Without any transactions, the regular suggestion is used
+	empty := NewOracle(&testBackend{blocks: backend.blocks[:1]}, config)
+	tip, err = empty.SuggestPriorityFee(context.Background())
+	if err != nil {
+		t.Fatalf("failed to suggest priority fee: %v", err)
+	}
+	if tip.Cmp(config.Default) != 0 {
+		t.Errorf("fallback priority fee mismatch: have %v, want %v", tip, config.Default)
+	}
+}
+
+// Tests that the price tiers pick their percentiles from the recent block prices,
+// with the standard tier matching the regular price suggestion.
+func TestSuggestPriceTier(t *testing.T) {
+	// Blocks 13..32 are checked, paying 13..32 gwei
+	backend := newTestBackend(t, 32)
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, Default: big.NewInt(params.GWei)})
+
+	tests := []struct {
+		tier string
+		want int64
+	}{
+		{TierSafe, 18},     // 30th percentile
+		{TierStandard, 24}, // 60th percentile
+		{TierFast, 28},     // 80th percentile
+	}
+	for _, tt := range tests {
+		price, err := oracle.SuggestPriceTier(context.Background(), tt.tier)
+		if err != nil {
+			t.Fatalf("%s: failed to suggest price: %v", tt.tier, err)
+		}
+		if want := big.NewInt(tt.want * params.GWei); price.Cmp(want) != 0 {
+			t.Errorf("%s: price mismatch: have %v, want %v", tt.tier, price, want)
+		}
+	}
+	price, err := oracle.SuggestPrice(context.Background())
+	if err != nil {
+		t.Fatalf("failed to suggest price: %v", err)
+	}
+	if want := big.NewInt(24 * params.GWei); price.Cmp(want) != 0 {
+		t.Errorf("standard price mismatch: have %v, want %v", price, want)
+	}
+	if _, err := oracle.SuggestPriceTier(context.Background(), "instant"); err == nil {
+		t.Errorf("unknown tier accepted")
+	}
+}
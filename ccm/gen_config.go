@@ -35,6 +35,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DatabaseHandles         int                    `toml:"-"`
 		DatabaseCache           int
 		DatabaseFreezer         string
+		LogsDatabase            string `toml:",omitempty"`
 		TrieCleanCache          int
 		TrieDirtyCache          int
 		TrieTimeout             time.Duration
@@ -47,6 +48,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EWASMInterpreter        string
 		EVMInterpreter          string
 		RPCGasCap               *big.Int                       `toml:",omitempty"`
+		TraceGasCap             *big.Int                       `toml:",omitempty"`
+		RPCCallDataCap          uint64                         `toml:",omitempty"`
+		RPCTimeouts             map[string]time.Duration       `toml:",omitempty"`
+		RPCMinGasPrice          *big.Int                       `toml:",omitempty"`
+		FinalityDepth           uint64                         `toml:",omitempty"`
+		SafeDepth               uint64                         `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
 	}
@@ -68,6 +75,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
 	enc.DatabaseFreezer = c.DatabaseFreezer
+	enc.LogsDatabase = c.LogsDatabase
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
@@ -80,6 +88,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EWASMInterpreter = c.EWASMInterpreter
 	enc.EVMInterpreter = c.EVMInterpreter
 	enc.RPCGasCap = c.RPCGasCap
+	enc.TraceGasCap = c.TraceGasCap
+	enc.RPCCallDataCap = c.RPCCallDataCap
+	enc.RPCTimeouts = c.RPCTimeouts
+	enc.RPCMinGasPrice = c.RPCMinGasPrice
+	enc.FinalityDepth = c.FinalityDepth
+	enc.SafeDepth = c.SafeDepth
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
 	return &enc, nil
@@ -105,6 +119,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DatabaseHandles         *int                   `toml:"-"`
 		DatabaseCache           *int
 		DatabaseFreezer         *string
+		LogsDatabase            *string `toml:",omitempty"`
 		TrieCleanCache          *int
 		TrieDirtyCache          *int
 		TrieTimeout             *time.Duration
@@ -117,6 +132,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EWASMInterpreter        *string
 		EVMInterpreter          *string
 		RPCGasCap               *big.Int                       `toml:",omitempty"`
+		TraceGasCap             *big.Int                       `toml:",omitempty"`
+		RPCCallDataCap          *uint64                        `toml:",omitempty"`
+		RPCTimeouts             map[string]time.Duration       `toml:",omitempty"`
+		RPCMinGasPrice          *big.Int                       `toml:",omitempty"`
+		FinalityDepth           *uint64                        `toml:",omitempty"`
+		SafeDepth               *uint64                        `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
 	}
@@ -175,6 +196,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseFreezer != nil {
 		c.DatabaseFreezer = *dec.DatabaseFreezer
 	}
+	if dec.LogsDatabase != nil {
+		c.LogsDatabase = *dec.LogsDatabase
+	}
 	if dec.TrieCleanCache != nil {
 		c.TrieCleanCache = *dec.TrieCleanCache
 	}
@@ -211,6 +235,24 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCGasCap != nil {
 		c.RPCGasCap = dec.RPCGasCap
 	}
+	if dec.TraceGasCap != nil {
+		c.TraceGasCap = dec.TraceGasCap
+	}
+	if dec.RPCCallDataCap != nil {
+		c.RPCCallDataCap = *dec.RPCCallDataCap
+	}
+	if dec.RPCTimeouts != nil {
+		c.RPCTimeouts = dec.RPCTimeouts
+	}
+	if dec.RPCMinGasPrice != nil {
+		c.RPCMinGasPrice = dec.RPCMinGasPrice
+	}
+	if dec.FinalityDepth != nil {
+		c.FinalityDepth = *dec.FinalityDepth
+	}
+	if dec.SafeDepth != nil {
+		c.SafeDepth = *dec.SafeDepth
+	}
 	if dec.Checkpoint != nil {
 		c.Checkpoint = dec.Checkpoint
 	}
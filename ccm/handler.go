@@ -27,8 +27,10 @@ import (
 	"time"
 
 	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
 	"github.com/ccmchain/go-ccmchain/consensus"
 	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/forkid"
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/ccm/downloader"
 	"github.com/ccmchain/go-ccmchain/ccm/fetcher"
@@ -829,16 +831,25 @@ type NodeInfo struct {
 	Genesis    common.Hash         `json:"genesis"`    // SHA3 hash of the host's genesis block
 	Config     *params.ChainConfig `json:"config"`     // Chain configuration for the fork rules
 	Head       common.Hash         `json:"head"`       // SHA3 hash of the host's best owned block
+	ForkID     NodeForkID          `json:"forkId"`     // Fork identifier currently advertised (EIP-2124)
+}
+
+// NodeForkID is the human readable representation of an EIP-2124 fork identifier.
+type NodeForkID struct {
+	Hash hexutil.Bytes `json:"hash"` // CRC32 checksum of the genesis block and passed fork block numbers
+	Next uint64        `json:"next"` // Block number of the next upcoming fork, or 0 if no forks are known
 }
 
 // NodeInfo retrieves some protocol metadata about the running host node.
 func (pm *ProtocolManager) NodeInfo() *NodeInfo {
 	currentBlock := pm.blockchain.CurrentBlock()
+	id := forkid.NewID(pm.blockchain)
 	return &NodeInfo{
 		Network:    pm.networkID,
 		Difficulty: pm.blockchain.GetTd(currentBlock.Hash(), currentBlock.NumberU64()),
 		Genesis:    pm.blockchain.Genesis().Hash(),
 		Config:     pm.blockchain.Config(),
 		Head:       currentBlock.Hash(),
+		ForkID:     NodeForkID{Hash: id.Hash[:], Next: id.Next},
 	}
 }
@@ -25,6 +25,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/p2p"
 	"github.com/ccmchain/go-ccmchain/rlp"
@@ -66,6 +67,18 @@ type PeerInfo struct {
 	Head       string   `json:"head"`       // SHA3 hash of the peer's best owned block
 }
 
+// PeerChainInfo is a summary of the chain a connected peer announced, used to
+// diagnose whether it is ahead of, behind or forked off the local chain.
+type PeerChainInfo struct {
+	ID         string          `json:"id"`         // Unique node identifier of the peer
+	Name       string          `json:"name"`       // Name of the node, including client type, version, OS, custom data
+	Version    int             `json:"version"`    // Ccmchain protocol version negotiated
+	Head       common.Hash     `json:"head"`       // Hash of the peer's announced head block
+	HeadNumber *hexutil.Uint64 `json:"headNumber"` // Number of the head block, nil if unknown locally
+	Difficulty *big.Int        `json:"difficulty"` // Total difficulty of the peer's blockchain
+	Ahead      bool            `json:"ahead"`      // Whether the peer's total difficulty exceeds the local one
+}
+
 // propEvent is a block propagation, waiting for its turn in the broadcast queue.
 type propEvent struct {
 	block *types.Block
@@ -516,6 +529,18 @@ func (ps *peerSet) PeersWithoutTx(hash common.Hash) []*peer {
 	return list
 }
 
+// AllPeers retrieves a list of all the peers in the set.
+func (ps *peerSet) AllPeers() []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		list = append(list, p)
+	}
+	return list
+}
+
 // BestPeer retrieves the known peer with the currently highest total difficulty.
 func (ps *peerSet) BestPeer() *peer {
 	ps.lock.RLock()
@@ -304,6 +304,33 @@ type Tracer struct {
 	reason    error  // Textual reason for the interruption
 }
 
+// Validate checks that code, either the name of a built-in tracer or a Javascript
+// snippet, compiles and evaluates to an object exposing the 'step', 'fault' and
+// 'result' functions. It does so in a throwaway JSVM without tracing anything, so
+// broken tracers can be rejected before any expensive execution begins. Syntax
+// errors are annotated with the offending line of the snippet.
+func Validate(code string) error {
+	if tracer, ok := tracer(code); ok {
+		code = tracer
+	}
+	vm := duktape.New()
+	defer vm.DestroyHeap()
+
+	if err := vm.PevalString("(" + code + ")"); err != nil {
+		if jserr, ok := err.(*duktape.Error); ok && jserr.LineNumber > 0 {
+			return fmt.Errorf("invalid tracer at line %d: %v", jserr.LineNumber, jserr)
+		}
+		return fmt.Errorf("invalid tracer: %v", err)
+	}
+	for _, method := range []string{"step", "fault", "result"} {
+		if !vm.GetPropString(-1, method) || !vm.IsFunction(-1) {
+			return fmt.Errorf("Trace object must expose a function %s()", method)
+		}
+		vm.Pop()
+	}
+	return nil
+}
+
 // New instantiates a new tracer instance. code specifies a Javascript snippet,
 // which must evaluate to an expression returning an object with 'step', 'fault'
 // and 'result' functions.
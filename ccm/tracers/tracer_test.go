@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
@@ -108,6 +109,31 @@ func TestOpcodes(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		code string
+		fail string
+	}{
+		{code: "callTracer"},
+		{code: "{step: function() {}, fault: function() {}, result: function() { return null; }}"},
+		{code: "{step: function() {}, fault: function() {}, result: function() { return null; }", fail: "invalid tracer"},
+		{code: "{step: function() {},\n fault: function() {},\n result: function() { return nul l; }}", fail: "line 3"},
+		{code: "{step: function() {}, result: function() { return null; }}", fail: "fault()"},
+		{code: "{step: function() {}, fault: 1, result: function() { return null; }}", fail: "fault()"},
+	}
+	for i, tt := range tests {
+		err := Validate(tt.code)
+		switch {
+		case tt.fail == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.fail != "" && err == nil:
+			t.Errorf("test %d: expected error containing %q, got none", i, tt.fail)
+		case tt.fail != "" && !strings.Contains(err.Error(), tt.fail):
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.fail)
+		}
+	}
+}
+
 func TestHalt(t *testing.T) {
 	t.Skip("duktape doesn't support abortion")
 
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ccmchain/go-ccmchain"
 	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
 	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
 	"github.com/ccmchain/go-ccmchain/core"
 	"github.com/ccmchain/go-ccmchain/core/rawdb"
@@ -335,3 +336,99 @@ func TestChainID(t *testing.T) {
 		t.Fatalf("ChainID returned wrong number: %+v", id)
 	}
 }
+
+// Tests that gas can be estimated against the pending state, such that a call
+// depending on the effects of a pending transaction succeeds.
+func TestEstimateGasPending(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+	ec := NewClient(client)
+
+	// Deploy a contract via a transaction that is only pending
+	var (
+		ctx    = context.Background()
+		target = crypto.CreateAddress(testAddr, 0)
+		signer = types.NewEIP155Signer(params.AllEthashProtocolChanges.ChainID)
+		deploy = common.FromHex("0x600160005360016000f3") // returns the runtime code 0x01
+		tx, _  = types.SignTx(types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), deploy), signer, testKey)
+	)
+	if err := ec.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		code, err := ec.PendingCodeAt(ctx, target)
+		if err != nil {
+			t.Fatalf("failed to retrieve pending code: %v", err)
+		}
+		if len(code) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("transaction not included in the pending state")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Estimate a creation that reverts unless the above contract exists. Doing
+	// so against the latest state must fail, against the pending one succeed.
+	check := append(append([]byte{0x73}, target.Bytes()...), common.FromHex("0x3b601d57600080fd5b00")...)
+	call := toCallArg(ccmchain.CallMsg{From: testAddr, Data: check})
+
+	var gas hexutil.Uint64
+	if err := client.CallContext(ctx, &gas, "ccm_estimateGas", call, "latest"); err == nil {
+		t.Fatalf("estimation against latest state succeeded with %d gas", gas)
+	}
+	if err := client.CallContext(ctx, &gas, "ccm_estimateGas", call, "pending"); err != nil {
+		t.Fatalf("failed to estimate against pending state: %v", err)
+	}
+	// Omitting the block must default to the pending state
+	if err := client.CallContext(ctx, &gas, "ccm_estimateGas", call); err != nil {
+		t.Fatalf("failed to estimate against default state: %v", err)
+	}
+}
+
+func TestAvailableBlockRange(t *testing.T) {
+	backend, chain := newTestBackend(t)
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+
+	var blockRange map[string]hexutil.Uint64
+	if err := client.CallContext(context.Background(), &blockRange, "ccm_availableBlockRange"); err != nil {
+		t.Fatalf("failed to retrieve block range: %v", err)
+	}
+	if blockRange["lowest"] != 0 || uint64(blockRange["highest"]) != chain[len(chain)-1].NumberU64() {
+		t.Fatalf("block range mismatch: have %v, want [0, %d]", blockRange, chain[len(chain)-1].NumberU64())
+	}
+}
+
+func TestGetAccount(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+
+	var account struct {
+		Balance     *hexutil.Big   `json:"balance"`
+		Nonce       hexutil.Uint64 `json:"nonce"`
+		CodeHash    common.Hash    `json:"codeHash"`
+		CodeSize    hexutil.Uint64 `json:"codeSize"`
+		StorageRoot common.Hash    `json:"storageRoot"`
+	}
+	if err := client.CallContext(context.Background(), &account, "ccm_getAccount", testAddr, "latest"); err != nil {
+		t.Fatalf("failed to retrieve account: %v", err)
+	}
+	if account.Balance.ToInt().Cmp(testBalance) != 0 {
+		t.Errorf("balance mismatch: have %v, want %v", account.Balance.ToInt(), testBalance)
+	}
+	if account.Nonce != 0 || account.CodeSize != 0 {
+		t.Errorf("nonce or code size mismatch: have %d, %d, want 0, 0", account.Nonce, account.CodeSize)
+	}
+	if account.CodeHash != crypto.Keccak256Hash(nil) {
+		t.Errorf("code hash mismatch: have %x, want %x", account.CodeHash, crypto.Keccak256Hash(nil))
+	}
+	if account.StorageRoot != types.EmptyRootHash {
+		t.Errorf("storage root mismatch: have %x, want %x", account.StorageRoot, types.EmptyRootHash)
+	}
+}
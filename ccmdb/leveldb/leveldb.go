@@ -80,6 +80,20 @@ type Database struct {
 // New returns a wrapped LevelDB object. The namespace is the prefix that the
 // metrics reporting should use for surfacing internal stats.
 func New(file string, cache int, handles int, namespace string) (*Database, error) {
+	return open(file, cache, handles, namespace, false)
+}
+
+// NewReadOnly returns a wrapped LevelDB object opened in read-only mode. The
+// database must already exist and is never modified or recovered. Note, only
+// the data present at the time of opening is visible, later writes by another
+// process are not picked up.
+func NewReadOnly(file string, cache int, handles int, namespace string) (*Database, error) {
+	return open(file, cache, handles, namespace, true)
+}
+
+// open opens the LevelDB database at the given path, optionally read-only, and
+// wraps it with the metrics collection.
+func open(file string, cache int, handles int, namespace string, readonly bool) (*Database, error) {
 	// Ensure we have some minimal caching and file guarantees
 	if cache < minCache {
 		cache = minCache
@@ -96,8 +110,10 @@ func New(file string, cache int, handles int, namespace string) (*Database, erro
 		BlockCacheCapacity:     cache / 2 * opt.MiB,
 		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
 		Filter:                 filter.NewBloomFilter(10),
+		ReadOnly:               readonly,
+		ErrorIfMissing:         readonly,
 	})
-	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+	if _, corrupted := err.(*errors.ErrCorrupted); corrupted && !readonly {
 		db, err = leveldb.RecoverFile(file, nil)
 	}
 	if err != nil {
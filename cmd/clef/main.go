@@ -118,6 +118,10 @@ var (
 		Name:  "rules",
 		Usage: "Path to the rule file to auto-authorize requests with",
 	}
+	manualDeployFlag = cli.BoolFlag{
+		Name:  "rules.manualdeploy",
+		Usage: "Require manual approval for all contract deployments, regardless of the rules",
+	}
 	stdiouiFlag = cli.BoolFlag{
 		Name: "stdio-ui",
 		Usage: "Use STDIN/STDOUT as a channel for an external UI. " +
@@ -215,6 +219,7 @@ func init() {
 		customDBFlag,
 		auditLogFlag,
 		ruleFlag,
+		manualDeployFlag,
 		stdiouiFlag,
 		testFlag,
 		advancedMode,
@@ -462,6 +467,7 @@ func signer(c *cli.Context) error {
 						utils.Fatalf(err.Error())
 					}
 					ruleEngine.Init(string(ruleJS))
+					ruleEngine.RequireManualDeployments(c.GlobalBool(manualDeployFlag.Name))
 					ui = ruleEngine
 					log.Info("Rule engine configured", "file", c.String(ruleFlag.Name))
 				}
@@ -32,7 +32,7 @@ import (
 )
 
 var (
-	consoleFlags = []cli.Flag{utils.JSpathFlag, utils.ExecFlag, utils.PreloadJSFlag}
+	consoleFlags = []cli.Flag{utils.JSpathFlag, utils.ExecFlag, utils.PreloadJSFlag, utils.JSModulesFlag}
 
 	consoleCommand = cli.Command{
 		Action:   utils.MigrateFlags(localConsole),
@@ -91,6 +91,7 @@ func localConsole(ctx *cli.Context) error {
 		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
 		Client:  client,
 		Preload: utils.MakeConsolePreloads(ctx),
+		Modules: utils.MakeConsoleModules(ctx),
 	}
 
 	console, err := console.New(config)
@@ -139,6 +140,7 @@ func remoteConsole(ctx *cli.Context) error {
 		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
 		Client:  client,
 		Preload: utils.MakeConsolePreloads(ctx),
+		Modules: utils.MakeConsoleModules(ctx),
 	}
 
 	console, err := console.New(config)
@@ -192,6 +194,7 @@ func ephemeralConsole(ctx *cli.Context) error {
 		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
 		Client:  client,
 		Preload: utils.MakeConsolePreloads(ctx),
+		Modules: utils.MakeConsoleModules(ctx),
 	}
 
 	console, err := console.New(config)
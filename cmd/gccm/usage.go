@@ -70,6 +70,7 @@ var AppHelpFlagGroups = []flagGroup{
 			configFileFlag,
 			utils.DataDirFlag,
 			utils.AncientFlag,
+			utils.LogsDatabaseFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.SmartCardDaemonPathFlag,
@@ -171,6 +172,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
 			utils.RPCGlobalGasCap,
+			utils.RPCTraceGasCap,
+			utils.RPCMinGasPriceFlag,
+			utils.RPCCallDataCapFlag,
+			utils.RPCTimeoutsFlag,
+			utils.RPCMaxSubscriptionsFlag,
 			utils.RPCCORSDomainFlag,
 			utils.RPCVirtualHostsFlag,
 			utils.WSEnabledFlag,
@@ -186,6 +192,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
+			utils.JSModulesFlag,
 		},
 	},
 	{
@@ -217,6 +224,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MinerCcmchainbaseFlag,
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
+			utils.MinerMaxUnclesFlag,
 			utils.MinerNoVerfiyFlag,
 		},
 	},
@@ -225,6 +233,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.GpoBlocksFlag,
 			utils.GpoPercentileFlag,
+			utils.GpoSampleAllFlag,
 		},
 	},
 	{
@@ -123,6 +123,10 @@ var (
 		Name:  "datadir.ancient",
 		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
 	}
+	LogsDatabaseFlag = DirectoryFlag{
+		Name:  "datadir.logs",
+		Usage: "Read-only replica of the chain database to serve log queries from, only data present at startup is visible (default = chaindata)",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -450,6 +454,11 @@ var (
 		Usage: "Time interval to recreate the block being mined",
 		Value: ccm.DefaultConfig.Miner.Recommit,
 	}
+	MinerMaxUnclesFlag = cli.IntFlag{
+		Name:  "miner.maxuncles",
+		Usage: "Maximum number of uncles to include in mined blocks (0 = default of 2, -1 = none)",
+		Value: ccm.DefaultConfig.Miner.MaxUncles,
+	}
 	MinerNoVerfiyFlag = cli.BoolFlag{
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
@@ -482,6 +491,27 @@ var (
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in ccm_call/estimateGas",
 	}
+	RPCMinGasPriceFlag = BigFlag{
+		Name:  "rpc.mingasprice",
+		Usage: "Minimum gas price of transactions submitted via the RPC APIs (not enforced by the transaction pool)",
+	}
+	RPCMaxSubscriptionsFlag = cli.IntFlag{
+		Name:  "rpc.maxsubscriptions",
+		Usage: "Maximum number of subscriptions per websocket/IPC connection (0 = unlimited)",
+	}
+	RPCTraceGasCap = cli.Uint64Flag{
+		Name:  "rpc.tracegascap",
+		Usage: "Sets a cap on gas that can be used in debug tracing, calls are capped and transactions above it refused (default = block gas limit)",
+	}
+	RPCCallDataCapFlag = cli.Uint64Flag{
+		Name:  "rpc.calldatacap",
+		Usage: "Sets a cap on the input data size in bytes accepted by ccm_call/estimateGas (0 = no cap)",
+		Value: ccm.DefaultConfig.RPCCallDataCap,
+	}
+	RPCTimeoutsFlag = cli.StringFlag{
+		Name:  "rpc.timeouts",
+		Usage: "Comma separated execution timeouts of heavy RPC methods by method or class (call, estimate, trace), e.g. call=5s,trace=1m (0 = no timeout)",
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ccmstats",
@@ -589,6 +619,10 @@ var (
 		Name:  "preload",
 		Usage: "Comma separated list of JavaScript files to preload into the console",
 	}
+	JSModulesFlag = cli.StringFlag{
+		Name:  "jsmodules",
+		Usage: "Comma separated list of JavaScript files with web3.js extensions or helpers to load into the console, each named after its module (e.g. mymodule.js)",
+	}
 
 	// Network Settings
 	MaxPeersFlag = cli.IntFlag{
@@ -665,6 +699,10 @@ var (
 		Usage: "Suggested gas price is the given percentile of a set of recent transaction gas prices",
 		Value: ccm.DefaultConfig.GPO.Percentile,
 	}
+	GpoSampleAllFlag = cli.BoolFlag{
+		Name:  "gposampleall",
+		Usage: "Sample all transactions for gas prices, including the ones paying no gas price",
+	}
 	WhisperEnabledFlag = cli.BoolFlag{
 		Name:  "shh",
 		Usage: "Enable Whisper",
@@ -946,6 +984,9 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = splitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCMaxSubscriptionsFlag.Name) {
+		cfg.RPCMaxSubscriptions = ctx.GlobalInt(RPCMaxSubscriptionsFlag.Name)
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -1218,6 +1259,9 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 	if ctx.GlobalIsSet(GpoPercentileFlag.Name) {
 		cfg.Percentile = ctx.GlobalInt(GpoPercentileFlag.Name)
 	}
+	if ctx.GlobalIsSet(GpoSampleAllFlag.Name) {
+		cfg.SampleAll = ctx.GlobalBool(GpoSampleAllFlag.Name)
+	}
 }
 
 func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
@@ -1312,6 +1356,9 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerRecommitIntervalFlag.Name) {
 		cfg.Recommit = ctx.Duration(MinerRecommitIntervalFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerMaxUnclesFlag.Name) {
+		cfg.MaxUncles = ctx.GlobalInt(MinerMaxUnclesFlag.Name)
+	}
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.Noverify = ctx.Bool(MinerNoVerfiyFlag.Name)
 	}
@@ -1426,6 +1473,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ccm.Config) {
 	if ctx.GlobalIsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
 	}
+	if ctx.GlobalIsSet(LogsDatabaseFlag.Name) {
+		cfg.LogsDatabase = ctx.GlobalString(LogsDatabaseFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1457,6 +1507,29 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ccm.Config) {
 	if ctx.GlobalIsSet(RPCGlobalGasCap.Name) {
 		cfg.RPCGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(RPCGlobalGasCap.Name))
 	}
+	if ctx.GlobalIsSet(RPCMinGasPriceFlag.Name) {
+		cfg.RPCMinGasPrice = GlobalBig(ctx, RPCMinGasPriceFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTraceGasCap.Name) {
+		cfg.TraceGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(RPCTraceGasCap.Name))
+	}
+	if ctx.GlobalIsSet(RPCCallDataCapFlag.Name) {
+		cfg.RPCCallDataCap = ctx.GlobalUint64(RPCCallDataCapFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTimeoutsFlag.Name) {
+		cfg.RPCTimeouts = make(map[string]time.Duration)
+		for _, entry := range splitAndTrim(ctx.GlobalString(RPCTimeoutsFlag.Name)) {
+			parts := strings.SplitN(entry, "=", 2)
+			if len(parts) != 2 {
+				Fatalf("Option %s: invalid timeout %q, want <method or class>=<duration>", RPCTimeoutsFlag.Name, entry)
+			}
+			timeout, err := time.ParseDuration(parts[1])
+			if err != nil {
+				Fatalf("Option %s: invalid timeout %q: %v", RPCTimeoutsFlag.Name, entry, err)
+			}
+			cfg.RPCTimeouts[parts[0]] = timeout
+		}
+	}
 
 	// Override any default configs for hard coded networks.
 	switch {
@@ -1722,6 +1795,21 @@ func MakeConsolePreloads(ctx *cli.Context) []string {
 	return preloads
 }
 
+// MakeConsoleModules retrieves the absolute paths for the custom web3.js extensions
+// to load into the console, each named after its module.
+func MakeConsoleModules(ctx *cli.Context) []string {
+	if ctx.GlobalString(JSModulesFlag.Name) == "" {
+		return nil
+	}
+	var modules []string
+
+	assets := ctx.GlobalString(JSpathFlag.Name)
+	for _, file := range strings.Split(ctx.GlobalString(JSModulesFlag.Name), ",") {
+		modules = append(modules, common.AbsolutePath(assets, strings.TrimSpace(file)))
+	}
+	return modules
+}
+
 // MigrateFlags sets the global flag from a local flag when it's set.
 // This is a temporary function used for migrating old command/flags to the
 // new format.
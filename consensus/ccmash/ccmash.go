@@ -450,11 +450,13 @@ type Ethash struct {
 	hashrate metrics.Meter // Meter tracking the average hashrate
 
 	// Remote sealer related fields
-	workCh       chan *sealTask   // Notification channel to push new work and relative result channel to remote sealer
-	fetchWorkCh  chan *sealWork   // Channel used for remote sealer to fetch mining work
-	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
-	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
-	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	workCh        chan *sealTask     // Notification channel to push new work and relative result channel to remote sealer
+	fetchWorkCh   chan *sealWork     // Channel used for remote sealer to fetch mining work
+	submitWorkCh  chan *mineResult   // Channel used for remote sealer to submit their mining result
+	fetchRateCh   chan chan uint64   // Channel used to gather submitted hash rate for local or remote sealer.
+	submitRateCh  chan *hashrate     // Channel used for remote sealer to submit their mining hashrate
+	workTimeoutCh chan time.Duration // Channel used to update the retention period of pending work packages
+	fetchStaleCh  chan chan int      // Channel used to count the stale work packages tracked by the remote sealer
 
 	// The fields below are hooks for testing
 	shared    *Ethash       // Shared PoW verifier to avoid cache regeneration
@@ -481,17 +483,19 @@ func New(config Config, notify []string, noverify bool) *Ethash {
 		log.Info("Disk storage enabled for ccmash DAGs", "dir", config.DatasetDir, "count", config.DatasetsOnDisk)
 	}
 	ccmash := &Ethash{
-		config:       config,
-		caches:       newlru("cache", config.CachesInMem, newCache),
-		datasets:     newlru("dataset", config.DatasetsInMem, newDataset),
-		update:       make(chan struct{}),
-		hashrate:     metrics.NewMeterForced(),
-		workCh:       make(chan *sealTask),
-		fetchWorkCh:  make(chan *sealWork),
-		submitWorkCh: make(chan *mineResult),
-		fetchRateCh:  make(chan chan uint64),
-		submitRateCh: make(chan *hashrate),
-		exitCh:       make(chan chan error),
+		config:        config,
+		caches:        newlru("cache", config.CachesInMem, newCache),
+		datasets:      newlru("dataset", config.DatasetsInMem, newDataset),
+		update:        make(chan struct{}),
+		hashrate:      metrics.NewMeterForced(),
+		workCh:        make(chan *sealTask),
+		fetchWorkCh:   make(chan *sealWork),
+		submitWorkCh:  make(chan *mineResult),
+		fetchRateCh:   make(chan chan uint64),
+		submitRateCh:  make(chan *hashrate),
+		workTimeoutCh: make(chan time.Duration),
+		fetchStaleCh:  make(chan chan int),
+		exitCh:        make(chan chan error),
 	}
 	go ccmash.remote(notify, noverify)
 	return ccmash
@@ -501,17 +505,19 @@ func New(config Config, notify []string, noverify bool) *Ethash {
 // purposes.
 func NewTester(notify []string, noverify bool) *Ethash {
 	ccmash := &Ethash{
-		config:       Config{PowMode: ModeTest},
-		caches:       newlru("cache", 1, newCache),
-		datasets:     newlru("dataset", 1, newDataset),
-		update:       make(chan struct{}),
-		hashrate:     metrics.NewMeterForced(),
-		workCh:       make(chan *sealTask),
-		fetchWorkCh:  make(chan *sealWork),
-		submitWorkCh: make(chan *mineResult),
-		fetchRateCh:  make(chan chan uint64),
-		submitRateCh: make(chan *hashrate),
-		exitCh:       make(chan chan error),
+		config:        Config{PowMode: ModeTest},
+		caches:        newlru("cache", 1, newCache),
+		datasets:      newlru("dataset", 1, newDataset),
+		update:        make(chan struct{}),
+		hashrate:      metrics.NewMeterForced(),
+		workCh:        make(chan *sealTask),
+		fetchWorkCh:   make(chan *sealWork),
+		submitWorkCh:  make(chan *mineResult),
+		fetchRateCh:   make(chan chan uint64),
+		submitRateCh:  make(chan *hashrate),
+		workTimeoutCh: make(chan time.Duration),
+		fetchStaleCh:  make(chan chan int),
+		exitCh:        make(chan chan error),
 	}
 	go ccmash.remote(notify, noverify)
 	return ccmash
@@ -690,6 +696,41 @@ func (ccmash *Ethash) Hashrate() float64 {
 	return ccmash.hashrate.Rate1() + float64(<-res)
 }
 
+// SetWorkTimeout sets how long work packages handed out to remote miners are
+// retained awaiting a solution, after which submissions for them are rejected as
+// stale. A zero timeout reverts to retaining packages based on the chain depth
+// only. Note, solutions for blocks too deep in the chain are always rejected.
+func (ccmash *Ethash) SetWorkTimeout(timeout time.Duration) error {
+	if ccmash.config.PowMode != ModeNormal && ccmash.config.PowMode != ModeTest {
+		return errors.New("not supported")
+	}
+	if timeout < 0 {
+		return errors.New("negative work timeout")
+	}
+	select {
+	case ccmash.workTimeoutCh <- timeout:
+		return nil
+	case <-ccmash.exitCh:
+		return errEthashStopped
+	}
+}
+
+// StaleWorks returns the number of previously handed out work packages that are
+// still tracked awaiting a solution, excluding the current one.
+func (ccmash *Ethash) StaleWorks() (int, error) {
+	if ccmash.config.PowMode != ModeNormal && ccmash.config.PowMode != ModeTest {
+		return 0, errors.New("not supported")
+	}
+	var res = make(chan int, 1)
+
+	select {
+	case ccmash.fetchStaleCh <- res:
+	case <-ccmash.exitCh:
+		return 0, errEthashStopped
+	}
+	return <-res, nil
+}
+
 // APIs implements consensus.Engine, returning the user facing RPC APIs.
 func (ccmash *Ethash) APIs(chain consensus.ChainReader) []rpc.API {
 	// In order to ensure backward compatibility, we exposes ccmash RPC APIs
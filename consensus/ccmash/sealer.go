@@ -192,6 +192,9 @@ func (ccmash *Ethash) remote(notify []string, noverify bool) {
 		works = make(map[common.Hash]*types.Block)
 		rates = make(map[common.Hash]hashrate)
 
+		workTimes   = make(map[common.Hash]time.Time) // Time each work package was created
+		workTimeout time.Duration                     // Retention period of work packages, zero if depth based
+
 		results      chan<- *types.Block
 		currentBlock *types.Block
 		currentWork  [4]string
@@ -246,8 +249,23 @@ func (ccmash *Ethash) remote(notify []string, noverify bool) {
 
 		// Trace the seal work fetched by remote sealer.
 		currentBlock = block
+		if _, ok := works[hash]; !ok {
+			workTimes[hash] = time.Now()
+		}
 		works[hash] = block
 	}
+	// staleWork reports whether a tracked work package should be dropped: either
+	// it exceeded its retention period or, if no period is configured, it's too
+	// deep in the chain to be relevant.
+	staleWork := func(hash common.Hash, block *types.Block) bool {
+		if hash == common.HexToHash(currentWork[0]) {
+			return false
+		}
+		if workTimeout > 0 {
+			return time.Since(workTimes[hash]) > workTimeout
+		}
+		return block.NumberU64()+staleThreshold <= currentBlock.NumberU64()
+	}
 	// submitWork verifies the submitted pow solution, returning
 	// whccmer the solution was accepted or not (not can be both a bad pow as well as
 	// any other error, like no pending work or stale mining result).
@@ -262,6 +280,10 @@ func (ccmash *Ethash) remote(notify []string, noverify bool) {
 			log.Warn("Work submitted but none pending", "sealhash", sealhash, "curnumber", currentBlock.NumberU64())
 			return false
 		}
+		if workTimeout > 0 && staleWork(sealhash, block) {
+			log.Warn("Work submitted after timeout", "sealhash", sealhash, "age", common.PrettyDuration(time.Since(workTimes[sealhash])), "timeout", workTimeout)
+			return false
+		}
 		// Verify the correctness of submitted result.
 		header := block.Header()
 		header.Nonce = nonce
@@ -345,6 +367,18 @@ func (ccmash *Ethash) remote(notify []string, noverify bool) {
 			}
 			req <- total
 
+		case timeout := <-ccmash.workTimeoutCh:
+			// Update the retention period of pending work packages.
+			workTimeout = timeout
+
+		case req := <-ccmash.fetchStaleCh:
+			// Count all tracked work packages but the current one.
+			stale := len(works)
+			if _, ok := works[common.HexToHash(currentWork[0])]; ok {
+				stale--
+			}
+			req <- stale
+
 		case <-ticker.C:
 			// Clear stale submitted hash rate.
 			for id, rate := range rates {
@@ -355,8 +389,9 @@ func (ccmash *Ethash) remote(notify []string, noverify bool) {
 			// Clear stale pending blocks
 			if currentBlock != nil {
 				for hash, block := range works {
-					if block.NumberU64()+staleThreshold <= currentBlock.NumberU64() {
+					if staleWork(hash, block) {
 						delete(works, hash)
+						delete(workTimes, hash)
 					}
 				}
 			}
@@ -225,3 +225,34 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that work packages handed out longer ago than the configured submission
+// timeout are rejected, and that outstanding stale works are counted.
+func TestWorkSubmissionTimeout(t *testing.T) {
+	ccmash := NewTester(nil, true)
+	defer ccmash.Close()
+	api := &API{ccmash}
+
+	if err := ccmash.SetWorkTimeout(50 * time.Millisecond); err != nil {
+		t.Fatalf("failed to set work timeout: %v", err)
+	}
+	results := make(chan *types.Block, 16)
+
+	first := &types.Header{ParentHash: common.BytesToHash([]byte{0xa}), Number: big.NewInt(1), Difficulty: big.NewInt(100000000)}
+	second := &types.Header{ParentHash: common.BytesToHash([]byte{0xa}), Number: big.NewInt(1), Difficulty: big.NewInt(100000001)}
+	ccmash.Seal(nil, types.NewBlockWithHeader(first), results, nil)
+	ccmash.Seal(nil, types.NewBlockWithHeader(second), results, nil)
+
+	if _, err := api.GetWork(); err != nil {
+		t.Fatalf("failed to fetch work: %v", err)
+	}
+	if n, err := ccmash.StaleWorks(); err != nil || n != 1 {
+		t.Fatalf("stale work count mismatch: have %d (%v), want 1", n, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	fakeNonce, fakeDigest := types.BlockNonce{0x01, 0x02, 0x03}, common.HexToHash("deadbeef")
+	if api.SubmitWork(fakeNonce, ccmash.SealHash(first), fakeDigest) {
+		t.Errorf("expired work accepted")
+	}
+}
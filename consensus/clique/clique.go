@@ -569,6 +569,15 @@ func (c *Clique) FinalizeAndAssemble(chain consensus.ChainReader, header *types.
 	return types.NewBlock(header, txs, nil, receipts), nil
 }
 
+// Signers retrieves the list of authorized signers at the specified header.
+func (c *Clique) Signers(chain consensus.ChainReader, header *types.Header) ([]common.Address, error) {
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.signers(), nil
+}
+
 // Authorize injects a private key into the consensus engine to mint new blocks
 // with.
 func (c *Clique) Authorize(signer common.Address, signFn SignerFn) {
@@ -58,6 +58,7 @@ type Config struct {
 	Prompter UserPrompter // Input prompter to allow interactive user feedback (defaults to TerminalPrompter)
 	Printer  io.Writer    // Output writer to serialize any display strings to (defaults to os.Stdout)
 	Preload  []string     // Absolute paths to JavaScript files to preload
+	Modules  []string     // Absolute paths to JavaScript files with web3 extensions of custom modules
 }
 
 // Console is a JavaScript interpreted runtime environment. It is a fully fledged
@@ -71,6 +72,8 @@ type Console struct {
 	histPath string       // Absolute path to the console scrollback history
 	history  []string     // Scroll history maintained by the console
 	printer  io.Writer    // Output writer to serialize any display strings to
+	bridge   *bridge      // JavaScript <-> Go RPC bridge the modules are instrumented with
+	modules  []string     // Absolute paths to the custom module extensions, re-read on every load
 }
 
 // New initializes a JavaScript interpreted runtime environment and sets defaults
@@ -94,6 +97,7 @@ func New(config Config) (*Console, error) {
 		prompter: config.Prompter,
 		printer:  config.Printer,
 		histPath: filepath.Join(config.DataDir, HistoryFile),
+		modules:  config.Modules,
 	}
 	if err := os.MkdirAll(config.DataDir, 0700); err != nil {
 		return nil, err
@@ -108,12 +112,12 @@ func New(config Config) (*Console, error) {
 // the console's JavaScript namespaces based on the exposed modules.
 func (c *Console) init(preload []string) error {
 	// Initialize the JavaScript <-> Go RPC bridge
-	bridge := newBridge(c.client, c.prompter, c.printer)
+	c.bridge = newBridge(c.client, c.prompter, c.printer)
 	c.jsre.Set("jccm", struct{}{})
 
 	jccmObj, _ := c.jsre.Get("jccm")
-	jccmObj.Object().Set("send", bridge.Send)
-	jccmObj.Object().Set("sendAsync", bridge.Send)
+	jccmObj.Object().Set("send", c.bridge.Send)
+	jccmObj.Object().Set("sendAsync", c.bridge.Send)
 
 	consoleObj, _ := c.jsre.Get("console")
 	consoleObj.Object().Set("log", c.consoleOutput)
@@ -129,14 +133,60 @@ func (c *Console) init(preload []string) error {
 	if _, err := c.jsre.Run("var Web3 = require('web3');"); err != nil {
 		return fmt.Errorf("web3 require: %v", err)
 	}
-	if _, err := c.jsre.Run("var web3 = new Web3(jccm);"); err != nil {
+	// Load the supported APIs into the JavaScript runtime environment
+	var err error
+	c.jsre.Do(func(vm *otto.Otto) { err = c.loadModules(vm) })
+	if err != nil {
+		return err
+	}
+	// Preload any JavaScript files before starting the console
+	for _, path := range preload {
+		if err := c.jsre.Exec(path); err != nil {
+			failure := err.Error()
+			if ottoErr, ok := err.(*otto.Error); ok {
+				failure = ottoErr.String()
+			}
+			return fmt.Errorf("%s: %v", path, failure)
+		}
+	}
+	// Configure the console's input prompter for scrollback and tab completion
+	if c.prompter != nil {
+		if content, err := ioutil.ReadFile(c.histPath); err != nil {
+			c.prompter.SetHistory(nil)
+		} else {
+			c.history = strings.Split(string(content), "\n")
+			c.prompter.SetHistory(c.history)
+		}
+		c.prompter.SetWordCompleter(c.AutoCompleteInput)
+	}
+	return nil
+}
+
+// loadModules retrieves the available APIs from the RPC provider, registers the
+// web3 extensions of the exposed modules and instruments the mccmods offered by
+// the console itself. It must be run on the JavaScript event loop.
+func (c *Console) loadModules(vm *otto.Otto) error {
+	// Create a fresh web3 instance, since extensions can't be redefined in place
+	if _, err := vm.Run("var web3 = new Web3(jccm);"); err != nil {
 		return fmt.Errorf("web3 provider: %v", err)
 	}
-	// Load the supported APIs into the JavaScript runtime environment
 	apis, err := c.client.SupportedModules()
 	if err != nil {
 		return fmt.Errorf("api modules: %v", err)
 	}
+	// Read the custom extensions from disk, so changes are picked up on reload
+	custom := make(map[string]string)
+	for _, path := range c.modules {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if _, ok := web3ext.Modules[name]; ok {
+			return fmt.Errorf("%s: module %q already defined", path, name)
+		}
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		custom[name] = string(source)
+	}
 	flatten := "var ccm = web3.ccm; var personal = web3.personal; "
 	for api := range apis {
 		if api == "web3" {
@@ -144,25 +194,43 @@ func (c *Console) init(preload []string) error {
 		}
 		if file, ok := web3ext.Modules[api]; ok {
 			// Load our extension for the module.
-			if err = c.jsre.Compile(fmt.Sprintf("%s.js", api), file); err != nil {
+			script, err := vm.Compile(fmt.Sprintf("%s.js", api), file)
+			if err == nil {
+				_, err = vm.Run(script)
+			}
+			if err != nil {
 				return fmt.Errorf("%s.js: %v", api, err)
 			}
 			flatten += fmt.Sprintf("var %s = web3.%s; ", api, api)
-		} else if obj, err := c.jsre.Run("web3." + api); err == nil && obj.IsObject() {
+		} else if obj, err := vm.Run("web3." + api); err == nil && obj.IsObject() {
 			// Enable web3.js built-in extension if available.
 			flatten += fmt.Sprintf("var %s = web3.%s; ", api, api)
 		}
 	}
-	if _, err = c.jsre.Run(flatten); err != nil {
+	// Load the custom extensions regardless of the node's APIs, since they may only
+	// provide JavaScript helpers
+	for name, file := range custom {
+		script, err := vm.Compile(fmt.Sprintf("%s.js", name), file)
+		if err == nil {
+			_, err = vm.Run(script)
+		}
+		if err != nil {
+			return fmt.Errorf("%s.js: %v", name, err)
+		}
+		if obj, err := vm.Run("web3." + name); err == nil && obj.IsObject() {
+			flatten += fmt.Sprintf("var %s = web3.%s; ", name, name)
+		}
+	}
+	if _, err = vm.Run(flatten); err != nil {
 		return fmt.Errorf("namespace flattening: %v", err)
 	}
 	// Initialize the global name register (disabled for now)
-	//c.jsre.Run(`var GlobalRegistrar = ccm.contract(` + registrar.GlobalRegistrarAbi + `);   registrar = GlobalRegistrar.at("` + registrar.GlobalRegistrarAddr + `");`)
+	//vm.Run(`var GlobalRegistrar = ccm.contract(` + registrar.GlobalRegistrarAbi + `);   registrar = GlobalRegistrar.at("` + registrar.GlobalRegistrarAddr + `");`)
 
 	// If the console is in interactive mode, instrument password related mccmods to query the user
 	if c.prompter != nil {
 		// Retrieve the account management object to instrument
-		personal, err := c.jsre.Get("personal")
+		personal, err := vm.Get("personal")
 		if err != nil {
 			return err
 		}
@@ -172,57 +240,48 @@ func (c *Console) init(preload []string) error {
 		// they got the password from the user and send the original web3 request to
 		// the backend.
 		if obj := personal.Object(); obj != nil { // make sure the personal api is enabled over the interface
-			if _, err = c.jsre.Run(`jccm.openWallet = personal.openWallet;`); err != nil {
+			if _, err = vm.Run(`jccm.openWallet = personal.openWallet;`); err != nil {
 				return fmt.Errorf("personal.openWallet: %v", err)
 			}
-			if _, err = c.jsre.Run(`jccm.unlockAccount = personal.unlockAccount;`); err != nil {
+			if _, err = vm.Run(`jccm.unlockAccount = personal.unlockAccount;`); err != nil {
 				return fmt.Errorf("personal.unlockAccount: %v", err)
 			}
-			if _, err = c.jsre.Run(`jccm.newAccount = personal.newAccount;`); err != nil {
+			if _, err = vm.Run(`jccm.newAccount = personal.newAccount;`); err != nil {
 				return fmt.Errorf("personal.newAccount: %v", err)
 			}
-			if _, err = c.jsre.Run(`jccm.sign = personal.sign;`); err != nil {
+			if _, err = vm.Run(`jccm.sign = personal.sign;`); err != nil {
 				return fmt.Errorf("personal.sign: %v", err)
 			}
-			obj.Set("openWallet", bridge.OpenWallet)
-			obj.Set("unlockAccount", bridge.UnlockAccount)
-			obj.Set("newAccount", bridge.NewAccount)
-			obj.Set("sign", bridge.Sign)
+			obj.Set("openWallet", c.bridge.OpenWallet)
+			obj.Set("unlockAccount", c.bridge.UnlockAccount)
+			obj.Set("newAccount", c.bridge.NewAccount)
+			obj.Set("sign", c.bridge.Sign)
 		}
 	}
-	// The admin.sleep and admin.sleepBlocks are offered by the console and not by the RPC layer.
-	admin, err := c.jsre.Get("admin")
+	// The admin.sleep, admin.sleepBlocks and admin.reloadModules are offered by the console and not by the RPC layer.
+	admin, err := vm.Get("admin")
 	if err != nil {
 		return err
 	}
 	if obj := admin.Object(); obj != nil { // make sure the admin api is enabled over the interface
-		obj.Set("sleepBlocks", bridge.SleepBlocks)
-		obj.Set("sleep", bridge.Sleep)
+		obj.Set("sleepBlocks", c.bridge.SleepBlocks)
+		obj.Set("sleep", c.bridge.Sleep)
 		obj.Set("clearHistory", c.clearHistory)
-	}
-	// Preload any JavaScript files before starting the console
-	for _, path := range preload {
-		if err := c.jsre.Exec(path); err != nil {
-			failure := err.Error()
-			if ottoErr, ok := err.(*otto.Error); ok {
-				failure = ottoErr.String()
-			}
-			return fmt.Errorf("%s: %v", path, failure)
-		}
-	}
-	// Configure the console's input prompter for scrollback and tab completion
-	if c.prompter != nil {
-		if content, err := ioutil.ReadFile(c.histPath); err != nil {
-			c.prompter.SetHistory(nil)
-		} else {
-			c.history = strings.Split(string(content), "\n")
-			c.prompter.SetHistory(c.history)
-		}
-		c.prompter.SetWordCompleter(c.AutoCompleteInput)
+		obj.Set("reloadModules", c.reloadModules)
 	}
 	return nil
 }
 
+// reloadModules re-registers the web3 extensions of the modules exposed by the
+// node, re-reading the custom ones from disk so that changes to their definitions
+// are picked up without restarting the console.
+func (c *Console) reloadModules(call otto.FunctionCall) otto.Value {
+	if err := c.loadModules(call.Otto); err != nil {
+		throwJSException(err.Error())
+	}
+	return otto.TrueValue()
+}
+
 func (c *Console) clearHistory() {
 	c.history = nil
 	c.prompter.ClearHistory()
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -196,6 +197,64 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+// Tests that the web3 extensions can be reloaded at runtime, keeping the console
+// provided mccmods in place.
+func TestReloadModules(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	tester.console.Evaluate("admin.reloadModules() && typeof admin.sleep")
+	if output := tester.output.String(); !strings.Contains(output, "function") {
+		t.Fatalf("module reload failed: have %s, want %s", output, "function")
+	}
+}
+
+// Tests that custom modules are loaded even if the node doesn't expose an API of
+// the same name, and that they are re-read from disk when reloaded.
+func TestCustomModules(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	module := filepath.Join(tester.workspace, "helpers.js")
+	write := func(factor int) {
+		source := fmt.Sprintf("web3._extend({property: 'helpers'}); web3.helpers.scale = function(x) { return %d * x; };", factor)
+		if err := ioutil.WriteFile(module, []byte(source), 0600); err != nil {
+			t.Fatalf("failed to write custom module: %v", err)
+		}
+	}
+	write(2)
+
+	client, err := tester.stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to attach to node: %v", err)
+	}
+	printer := new(bytes.Buffer)
+	console, err := New(Config{
+		DataDir:  tester.stack.DataDir(),
+		DocRoot:  "testdata",
+		Client:   client,
+		Prompter: &hookedPrompter{scheduler: make(chan string)},
+		Printer:  printer,
+		Modules:  []string{module},
+	})
+	if err != nil {
+		t.Fatalf("failed to create JavaScript console: %v", err)
+	}
+	defer console.Stop(false)
+
+	console.Evaluate("helpers.scale(21)")
+	if output := printer.String(); !strings.Contains(output, "42") {
+		t.Fatalf("custom module not loaded: have %s, want %s", output, "42")
+	}
+	write(3)
+	printer.Reset()
+
+	console.Evaluate("admin.reloadModules() && helpers.scale(21)")
+	if output := printer.String(); !strings.Contains(output, "63") {
+		t.Fatalf("custom module not reloaded: have %s, want %s", output, "63")
+	}
+}
+
 // Tests that the console can be used in interactive mode.
 func TestInteractive(t *testing.T) {
 	// Create a tester and run an interactive console in the background
@@ -141,10 +141,15 @@ type BlockChain struct {
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
+	allLogsFeed   event.Feed // Added and removed logs in a single, ordered stream
 	blockProcFeed event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
+	allLogsQueue [][]*types.Log // Log batches waiting to be sent on allLogsFeed
+	allLogsLock  sync.Mutex     // Protects allLogsQueue
+	allLogsWake  chan struct{}  // Notifies the log sender of queued batches
+
 	chainmu sync.RWMutex // blockchain insertion lock
 
 	currentBlock     atomic.Value // Current head of the block chain
@@ -199,6 +204,7 @@ func NewBlockChain(db ccmdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		triegc:         prque.New(nil),
 		stateCache:     state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit),
 		quit:           make(chan struct{}),
+		allLogsWake:    make(chan struct{}, 1),
 		shouldPreserve: shouldPreserve,
 		bodyCache:      bodyCache,
 		bodyRLPCache:   bodyRLPCache,
@@ -282,6 +288,7 @@ func NewBlockChain(db ccmdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	}
 	// Take ownership of this particular state
 	go bc.update()
+	go bc.allLogsLoop()
 	return bc, nil
 }
 
@@ -503,6 +510,22 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache)
 }
 
+// StatesAt returns n independent states based on the same point in time. The
+// states share nothing but the thread safe caching database underneath (trie
+// node and code caches), so they may be used concurrently from separate
+// goroutines, e.g. to run multiple read-only calls in parallel.
+func (bc *BlockChain) StatesAt(root common.Hash, n int) ([]*state.StateDB, error) {
+	states := make([]*state.StateDB, n)
+	for i := range states {
+		statedb, err := state.New(root, bc.stateCache)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = statedb
+	}
+	return states, nil
+}
+
 // StateCache returns the caching database underpinning the blockchain instance.
 func (bc *BlockChain) StateCache() state.Database {
 	return bc.stateCache
@@ -1960,6 +1983,17 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 		rawdb.DeleteCanonicalHash(batch, i)
 	}
 	batch.Write()
+
+	// Announce the removals and the rebirths in one event, so subscribers of the
+	// unified stream see them in the order they should be applied. The batch is
+	// queued rather than sent, as the chain lock is held, but still precedes the
+	// logs of the new head.
+	if len(deletedLogs) > 0 || len(rebirthLogs) > 0 {
+		logs := make([]*types.Log, 0, len(deletedLogs)+len(rebirthLogs))
+		logs = append(logs, deletedLogs...)
+		logs = append(logs, rebirthLogs...)
+		bc.queueAllLogs(logs)
+	}
 	// If any logs need to be fired, do it now. In theory we could avoid creating
 	// this goroutine if there are no events to fire, but realistcally that only
 	// ever happens if we're reorging empty blocks, which will only happen on idle
@@ -1989,6 +2023,7 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 	// post event logs for further processing
 	if logs != nil {
+		bc.queueAllLogs(logs)
 		bc.logsFeed.Send(logs)
 	}
 	for _, event := range events {
@@ -2005,6 +2040,44 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 	}
 }
 
+// queueAllLogs schedules a batch of logs for delivery on the unified log stream.
+// Batches are sent in order from a dedicated goroutine, so a slow subscriber can
+// not block chain processing, even if queued while holding the chain lock.
+func (bc *BlockChain) queueAllLogs(logs []*types.Log) {
+	bc.allLogsLock.Lock()
+	bc.allLogsQueue = append(bc.allLogsQueue, logs)
+	bc.allLogsLock.Unlock()
+
+	select {
+	case bc.allLogsWake <- struct{}{}:
+	default:
+	}
+}
+
+// allLogsLoop delivers the queued log batches on the unified log stream until
+// the blockchain is stopped.
+func (bc *BlockChain) allLogsLoop() {
+	for {
+		select {
+		case <-bc.allLogsWake:
+			for {
+				bc.allLogsLock.Lock()
+				if len(bc.allLogsQueue) == 0 {
+					bc.allLogsLock.Unlock()
+					break
+				}
+				logs := bc.allLogsQueue[0]
+				bc.allLogsQueue = bc.allLogsQueue[1:]
+				bc.allLogsLock.Unlock()
+
+				bc.allLogsFeed.Send(logs)
+			}
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
 func (bc *BlockChain) update() {
 	futureTimer := time.NewTicker(5 * time.Second)
 	defer futureTimer.Stop()
@@ -2177,6 +2250,13 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeAllLogsEvent registers a subscription of []*types.Log delivering both
+// newly added logs and logs dropped by a chain reorganisation. Dropped logs have
+// their Removed field set and are always delivered before the logs replacing them.
+func (bc *BlockChain) SubscribeAllLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return bc.scope.Track(bc.allLogsFeed.Subscribe(ch))
+}
+
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
@@ -975,6 +975,164 @@ func TestLogReorgs(t *testing.T) {
 	}
 }
 
+func TestAllLogsReorg(t *testing.T) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		// this code generates a log
+		code    = common.Hex2Bytes("60606040525b7f24ec1d3ff24c2f6ff210738839dbc339cd45a5294d85c79361016243157aae7b60405180905060405180910390a15b600a8060416000396000f360606040526008565b00")
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr1: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ccmash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	logsCh := make(chan []*types.Log, 10)
+	blockchain.SubscribeAllLogsEvent(logsCh)
+
+	chain, _ := GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 2, func(i int, gen *BlockGen) {
+		if i == 1 {
+			tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr1), new(big.Int), 1000000, new(big.Int), code), signer, key1)
+			if err != nil {
+				t.Fatalf("failed to create tx: %v", err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	checkAllLogsEvent(t, logsCh, 1, false)
+
+	chain, _ = GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 3, func(i int, gen *BlockGen) {})
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	checkAllLogsEvent(t, logsCh, 1, true)
+}
+
+// Tests that logs removed by a reorg are delivered on the unified stream before
+// the logs added by the new head.
+func TestAllLogsReorgOrder(t *testing.T) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		// this code generates a log
+		code    = common.Hex2Bytes("60606040525b7f24ec1d3ff24c2f6ff210738839dbc339cd45a5294d85c79361016243157aae7b60405180905060405180910390a15b600a8060416000396000f360606040526008565b00")
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr1: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ccmash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	logsCh := make(chan []*types.Log, 10)
+	blockchain.SubscribeAllLogsEvent(logsCh)
+
+	// Generate two forks emitting a log in their last block, the second one longer
+	logAt := func(n int) func(int, *BlockGen) {
+		return func(i int, gen *BlockGen) {
+			if i == n {
+				tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr1), new(big.Int), 1000000, new(big.Int), code), signer, key1)
+				if err != nil {
+					t.Fatalf("failed to create tx: %v", err)
+				}
+				gen.AddTx(tx)
+			}
+		}
+	}
+	chain, _ := GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 2, logAt(1))
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	checkAllLogsEvent(t, logsCh, 1, false)
+
+	fork, _ := GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 3, logAt(2))
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	checkAllLogsEvent(t, logsCh, 1, true)
+	checkAllLogsEvent(t, logsCh, 1, false)
+}
+
+// Tests that a subscriber of the unified log stream which never reads its
+// channel does not block chain insertions, even across reorgs.
+func TestAllLogsStuckSubscriber(t *testing.T) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		// this code generates a log
+		code    = common.Hex2Bytes("60606040525b7f24ec1d3ff24c2f6ff210738839dbc339cd45a5294d85c79361016243157aae7b60405180905060405180910390a15b600a8060416000396000f360606040526008565b00")
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr1: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ccmash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	// Subscribe with an unbuffered channel that is never read
+	blockchain.SubscribeAllLogsEvent(make(chan []*types.Log))
+
+	gen := func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr1), new(big.Int), 1000000, new(big.Int), code), signer, key1)
+		if err != nil {
+			t.Fatalf("failed to create tx: %v", err)
+		}
+		gen.AddTx(tx)
+	}
+	chain, _ := GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 2, gen)
+	fork, _ := GenerateChain(params.TestChainConfig, genesis, ccmash.NewFaker(), db, 3, gen)
+
+	done := make(chan error, 1)
+	go func() {
+		if _, err := blockchain.InsertChain(chain); err != nil {
+			done <- err
+			return
+		}
+		_, err := blockchain.InsertChain(fork)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to insert chains: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("chain insertion blocked by unread log subscription")
+	}
+	if head := blockchain.CurrentBlock(); head.Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", head.Hash(), fork[len(fork)-1].Hash())
+	}
+}
+
+func checkAllLogsEvent(t *testing.T, logsCh <-chan []*types.Log, wantLen int, wantRemoved bool) {
+	t.Helper()
+
+	timeout := time.NewTimer(1 * time.Second)
+	defer timeout.Stop()
+
+	select {
+	case logs := <-logsCh:
+		if len(logs) != wantLen {
+			t.Fatalf("wrong number of logs: got %d, want %d", len(logs), wantLen)
+		}
+		for i, log := range logs {
+			if log.Removed != wantRemoved {
+				t.Errorf("log %d: removed flag mismatch: got %v, want %v", i, log.Removed, wantRemoved)
+			}
+		}
+	case <-timeout.C:
+		t.Fatal("timeout waiting for logs event")
+	}
+}
+
 func TestLogRebirth(t *testing.T) {
 	var (
 		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
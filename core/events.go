@@ -24,6 +24,17 @@ import (
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
 type NewTxsEvent struct{ Txs []*types.Transaction }
 
+// DroppedTx is a transaction removed from the transaction pool without being
+// included in a block, along with the reason of its removal.
+type DroppedTx struct {
+	Hash   common.Hash `json:"hash"`
+	Reason string      `json:"reason"`
+}
+
+// DroppedTxsEvent is posted when a batch of transactions is dropped from the
+// transaction pool without being mined.
+type DroppedTxsEvent struct{ Txs []DroppedTx }
+
 // PendingLogsEvent is posted pre mining and notifies of pending logs.
 type PendingLogsEvent struct {
 	Logs []*types.Log
@@ -46,3 +57,10 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// FinalityHeadEvent is posted for every new chain head, along with the latest
+// block considered final at that point (nil if no block is final yet).
+type FinalityHeadEvent struct {
+	Header    *types.Header
+	Finalized *types.Header
+}
@@ -59,6 +59,12 @@ func NewID(chain *core.BlockChain) ID {
 	)
 }
 
+// NewIDFromHead calculates the Ccmchain fork ID from the chain config, genesis
+// hash and head number, for chains not backed by a full core.BlockChain.
+func NewIDFromHead(config *params.ChainConfig, genesis common.Hash, head uint64) ID {
+	return newID(config, genesis, head)
+}
+
 // newID is the internal version of NewID, which takes extracted values as its
 // arguments instead of a chain. The reason is to allow testing the IDs without
 // having to simulate an entire blockchain.
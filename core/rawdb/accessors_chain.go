@@ -297,6 +297,35 @@ func ReadBody(db ccmdb.Reader, hash common.Hash, number uint64) *types.Body {
 	return body
 }
 
+// ReadBodyTxCount retrieves the number of transactions in the block body
+// corresponding to the hash, without decoding the transactions themselves.
+func ReadBodyTxCount(db ccmdb.Reader, hash common.Hash, number uint64) (int, bool) {
+	data := ReadBodyRLP(db, hash, number)
+	if len(data) == 0 {
+		return 0, false
+	}
+	count, err := CountBodyTxs(data)
+	if err != nil {
+		log.Error("Invalid block body RLP", "hash", hash, "err", err)
+		return 0, false
+	}
+	return count, true
+}
+
+// CountBodyTxs counts the transactions in an RLP encoded block body by walking
+// the list headers only, without decoding the transactions.
+func CountBodyTxs(body rlp.RawValue) (int, error) {
+	content, _, err := rlp.SplitList(body)
+	if err != nil {
+		return 0, err
+	}
+	txs, _, err := rlp.SplitList(content)
+	if err != nil {
+		return 0, err
+	}
+	return rlp.CountValues(txs)
+}
+
 // WriteBody stores a block body into the database.
 func WriteBody(db ccmdb.KeyValueWriter, hash common.Hash, number uint64, body *types.Body) {
 	data, err := rlp.EncodeToBytes(body)
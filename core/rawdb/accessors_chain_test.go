@@ -84,6 +84,9 @@ func TestBodyStorage(t *testing.T) {
 	} else if types.DeriveSha(types.Transactions(entry.Transactions)) != types.DeriveSha(types.Transactions(body.Transactions)) || types.CalcUncleHash(entry.Uncles) != types.CalcUncleHash(body.Uncles) {
 		t.Fatalf("Retrieved body mismatch: have %v, want %v", entry, body)
 	}
+	if count, ok := ReadBodyTxCount(db, hash, 0); !ok || count != len(body.Transactions) {
+		t.Fatalf("Retrieved body tx count mismatch: have %d (%v), want %d", count, ok, len(body.Transactions))
+	}
 	if entry := ReadBodyRLP(db, hash, 0); entry == nil {
 		t.Fatalf("Stored body RLP not found")
 	} else {
@@ -105,8 +105,14 @@ func NewDatabase(db ccmdb.KeyValueStore) ccmdb.Database {
 // value data store with a freezer moving immutable chain segments into cold
 // storage.
 func NewDatabaseWithFreezer(db ccmdb.KeyValueStore, freezer string, namespace string) (ccmdb.Database, error) {
+	return newDatabaseWithFreezer(db, freezer, namespace, false)
+}
+
+// newDatabaseWithFreezer attaches a freezer to the key-value store, only ever
+// moving chain segments into it if the freezer is not read-only.
+func newDatabaseWithFreezer(db ccmdb.KeyValueStore, freezer string, namespace string, readonly bool) (ccmdb.Database, error) {
 	// Create the idle freezer instance
-	frdb, err := newFreezer(freezer, namespace)
+	frdb, err := newFreezer(freezer, namespace, readonly)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +179,9 @@ func NewDatabaseWithFreezer(db ccmdb.KeyValueStore, freezer string, namespace st
 		}
 	}
 	// Freezer is consistent with the key-value database, permit combining the two
-	go frdb.freeze(db)
+	if !readonly {
+		go frdb.freeze(db)
+	}
 
 	return &freezerdb{
 		KeyValueStore: db,
@@ -219,6 +227,22 @@ func NewLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer
 	return frdb, nil
 }
 
+// NewReadOnlyLevelDBDatabaseWithFreezer opens an existing persistent key-value
+// database and its freezer for reading only. Nothing is ever written to either
+// store, and only the data present at the time of opening is visible.
+func NewReadOnlyLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer string, namespace string) (ccmdb.Database, error) {
+	kvdb, err := leveldb.NewReadOnly(file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	frdb, err := newDatabaseWithFreezer(kvdb, freezer, namespace, true)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 // InspectDatabase traverses the entire database and checks the size
 // of all different categories of data.
 func InspectDatabase(db ccmdb.Database) error {
@@ -45,6 +45,10 @@ var (
 	// errSymlinkDatadir is returned if the ancient directory specified by user
 	// is a symbolic link.
 	errSymlinkDatadir = errors.New("symbolic link datadir is not supported")
+
+	// errReadOnly is returned if the user attempts to modify a freezer opened
+	// in read-only mode.
+	errReadOnly = errors.New("read only")
 )
 
 const (
@@ -71,13 +75,15 @@ type freezer struct {
 	// so take advantage of that (https://golang.org/pkg/sync/atomic/#pkg-note-BUG).
 	frozen uint64 // Number of blocks already frozen
 
+	readonly     bool                     // Whether the freezer only serves retrievals
 	tables       map[string]*freezerTable // Data tables for storing everything
 	instanceLock fileutil.Releaser        // File-system lock to prevent double opens
 }
 
 // newFreezer creates a chain freezer that moves ancient chain data into
-// append-only flat file containers.
-func newFreezer(datadir string, namespace string) (*freezer, error) {
+// append-only flat file containers. A read-only freezer neither locks nor
+// repairs the data files, it only serves retrievals from them.
+func newFreezer(datadir string, namespace string, readonly bool) (*freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter   = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -93,31 +99,29 @@ func newFreezer(datadir string, namespace string) (*freezer, error) {
 	}
 	// Leveldb uses LOCK as the filelock filename. To prevent the
 	// name collision, we use FLOCK as the lock name.
-	lock, _, err := fileutil.Flock(filepath.Join(datadir, "FLOCK"))
-	if err != nil {
-		return nil, err
+	var lock fileutil.Releaser
+	if !readonly {
+		var err error
+		if lock, _, err = fileutil.Flock(filepath.Join(datadir, "FLOCK")); err != nil {
+			return nil, err
+		}
 	}
 	// Open all the supported data tables
 	freezer := &freezer{
+		readonly:     readonly,
 		tables:       make(map[string]*freezerTable),
 		instanceLock: lock,
 	}
 	for name, disableSnappy := range freezerNoSnappy {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeCounter, disableSnappy)
+		table, err := newTable(datadir, name, readMeter, writeMeter, sizeCounter, disableSnappy, readonly)
 		if err != nil {
-			for _, table := range freezer.tables {
-				table.Close()
-			}
-			lock.Release()
+			freezer.Close()
 			return nil, err
 		}
 		freezer.tables[name] = table
 	}
 	if err := freezer.repair(); err != nil {
-		for _, table := range freezer.tables {
-			table.Close()
-		}
-		lock.Release()
+		freezer.Close()
 		return nil, err
 	}
 	log.Info("Opened ancient database", "database", datadir)
@@ -132,8 +136,10 @@ func (f *freezer) Close() error {
 			errs = append(errs, err)
 		}
 	}
-	if err := f.instanceLock.Release(); err != nil {
-		errs = append(errs, err)
+	if f.instanceLock != nil {
+		if err := f.instanceLock.Release(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	if errs != nil {
 		return fmt.Errorf("%v", errs)
@@ -178,6 +184,9 @@ func (f *freezer) AncientSize(kind string) (uint64, error) {
 // injection will be rejected. But if two injections with same number happen at
 // the same time, we can get into the trouble.
 func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
+	if f.readonly {
+		return errReadOnly
+	}
 	// Ensure the binary blobs we are appending is continuous with freezer.
 	if atomic.LoadUint64(&f.frozen) != number {
 		return errOutOrderInsertion
@@ -220,6 +229,9 @@ func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td
 
 // Truncate discards any recent data above the provided threshold number.
 func (f *freezer) TruncateAncients(items uint64) error {
+	if f.readonly {
+		return errReadOnly
+	}
 	if atomic.LoadUint64(&f.frozen) <= items {
 		return nil
 	}
@@ -234,6 +246,9 @@ func (f *freezer) TruncateAncients(items uint64) error {
 
 // sync flushes all data tables to disk.
 func (f *freezer) Sync() error {
+	if f.readonly {
+		return errReadOnly
+	}
 	var errs []error
 	for _, table := range f.tables {
 		if err := table.Sync(); err != nil {
@@ -383,6 +398,11 @@ func (f *freezer) repair() error {
 			min = items
 		}
 	}
+	if f.readonly {
+		// Tables can't be truncated, only expose the items present in all
+		atomic.StoreUint64(&f.frozen, min)
+		return nil
+	}
 	for _, table := range f.tables {
 		if err := table.truncate(min); err != nil {
 			return err
@@ -80,6 +80,7 @@ type freezerTable struct {
 	items uint64 // Number of items stored in the table (including items removed from tail)
 
 	noCompression bool   // if true, disables snappy compression. Note: does not work retroactively
+	readonly      bool   // if true, files are opened read-only and never repaired
 	maxFileSize   uint32 // Max file size for data-files
 	name          string
 	path          string
@@ -104,8 +105,8 @@ type freezerTable struct {
 }
 
 // newTable opens a freezer table with default settings - 2G files
-func newTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeCounter metrics.Counter, disableSnappy bool) (*freezerTable, error) {
-	return newCustomTable(path, name, readMeter, writeMeter, sizeCounter, 2*1000*1000*1000, disableSnappy)
+func newTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeCounter metrics.Counter, disableSnappy bool, readonly bool) (*freezerTable, error) {
+	return openTable(path, name, readMeter, writeMeter, sizeCounter, 2*1000*1000*1000, disableSnappy, readonly)
 }
 
 // openFreezerFileForAppend opens a freezer table file and seeks to the end
@@ -150,8 +151,18 @@ func truncateFreezerFile(file *os.File, size int64) error {
 // non existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
 func newCustomTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeCounter metrics.Counter, maxFilesize uint32, noCompression bool) (*freezerTable, error) {
+	return openTable(path, name, readMeter, writeMeter, sizeCounter, maxFilesize, noCompression, false)
+}
+
+// openTable opens a freezer table either for appending, or, if readonly is set,
+// for retrieval only. A read-only table must already exist and be consistent as
+// it cannot be repaired.
+func openTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeCounter metrics.Counter, maxFilesize uint32, noCompression bool, readonly bool) (*freezerTable, error) {
 	// Ensure the containing directory exists and open the indexEntry file
-	if err := os.MkdirAll(path, 0755); err != nil {
+	opener := openFreezerFileForAppend
+	if readonly {
+		opener = openFreezerFileForReadOnly
+	} else if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
 	}
 	var idxName string
@@ -162,7 +173,7 @@ func newCustomTable(path string, name string, readMeter metrics.Meter, writeMete
 		// Compressed idx
 		idxName = fmt.Sprintf("%s.cidx", name)
 	}
-	offsets, err := openFreezerFileForAppend(filepath.Join(path, idxName))
+	offsets, err := opener(filepath.Join(path, idxName))
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +188,7 @@ func newCustomTable(path string, name string, readMeter metrics.Meter, writeMete
 		path:          path,
 		logger:        log.New("database", path, "table", name),
 		noCompression: noCompression,
+		readonly:      readonly,
 		maxFileSize:   maxFilesize,
 	}
 	if err := tab.repair(); err != nil {
@@ -206,12 +218,18 @@ func (t *freezerTable) repair() error {
 		return err
 	}
 	if stat.Size() == 0 {
+		if t.readonly {
+			return errors.New("empty read-only index")
+		}
 		if _, err := t.index.Write(buffer); err != nil {
 			return err
 		}
 	}
 	// Ensure the index is a multiple of indexEntrySize bytes
 	if overflow := stat.Size() % indexEntrySize; overflow != 0 {
+		if t.readonly {
+			return errors.New("misaligned read-only index")
+		}
 		truncateFreezerFile(t.index, stat.Size()-overflow) // New file can't trigger this path
 	}
 	// Retrieve the file sizes and prepare for truncation
@@ -220,6 +238,11 @@ func (t *freezerTable) repair() error {
 	}
 	offsetsSize := stat.Size()
 
+	opener := openFreezerFileForAppend
+	if t.readonly {
+		opener = openFreezerFileForReadOnly
+	}
+
 	// Open the head file
 	var (
 		firstIndex  indexEntry
@@ -237,7 +260,7 @@ func (t *freezerTable) repair() error {
 
 	t.index.ReadAt(buffer, offsetsSize-indexEntrySize)
 	lastIndex.unmarshalBinary(buffer)
-	t.head, err = t.openFile(lastIndex.filenum, openFreezerFileForAppend)
+	t.head, err = t.openFile(lastIndex.filenum, opener)
 	if err != nil {
 		return err
 	}
@@ -248,6 +271,9 @@ func (t *freezerTable) repair() error {
 
 	// Keep truncating both files until they come in sync
 	contentExp = int64(lastIndex.offset)
+	if t.readonly && contentExp != contentSize {
+		return fmt.Errorf("read-only table out of sync: indexed %d, stored %d", contentExp, contentSize)
+	}
 
 	for contentExp != contentSize {
 		// Truncate the head file to the last offset pointer
@@ -287,11 +313,13 @@ func (t *freezerTable) repair() error {
 		}
 	}
 	// Ensure all reparation changes have been written to disk
-	if err := t.index.Sync(); err != nil {
-		return err
-	}
-	if err := t.head.Sync(); err != nil {
-		return err
+	if !t.readonly {
+		if err := t.index.Sync(); err != nil {
+			return err
+		}
+		if err := t.head.Sync(); err != nil {
+			return err
+		}
 	}
 	// Update the item and byte counters and return
 	t.items = uint64(t.itemOffset) + uint64(offsetsSize/indexEntrySize-1) // last indexEntry points to the end of the data file
@@ -319,8 +347,12 @@ func (t *freezerTable) preopen() (err error) {
 			return err
 		}
 	}
-	// Open head in read/write
-	t.head, err = t.openFile(t.headId, openFreezerFileForAppend)
+	// Open head in read/write, unless the whole table is read-only
+	if t.readonly {
+		t.head, err = t.openFile(t.headId, openFreezerFileForReadOnly)
+	} else {
+		t.head, err = t.openFile(t.headId, openFreezerFileForAppend)
+	}
 	return err
 }
 
@@ -78,6 +78,15 @@ var (
 	ErrOversizedData = errors.New("oversized data")
 )
 
+// Reasons reported in DroppedTxsEvent for transactions leaving the pool unmined.
+const (
+	TxDropReplaced    = "replaced"    // Superseded by a transaction with the same nonce
+	TxDropUnderpriced = "underpriced" // Evicted by better paying transactions or the price limit
+	TxDropNoFunds     = "nofunds"     // Sender can't pay for the transaction anymore
+	TxDropLimit       = "limit"       // Exceeded the account or global slot limits
+	TxDropExpired     = "expired"     // Queued for longer than the configured lifetime
+)
+
 var (
 	evictionInterval    = time.Minute     // Time interval to check for evictable transactions
 	statsReportInterval = 8 * time.Second // Time interval to report transaction pool stats
@@ -116,6 +125,20 @@ const (
 	TxStatusIncluded
 )
 
+// String implements fmt.Stringer.
+func (status TxStatus) String() string {
+	switch status {
+	case TxStatusQueued:
+		return "queued"
+	case TxStatusPending:
+		return "pending"
+	case TxStatusIncluded:
+		return "included"
+	default:
+		return "unknown"
+	}
+}
+
 // blockChain provides the state of blockchain and current gas limit to do
 // some pre checks in tx pool and event subscribers.
 type blockChain interface {
@@ -213,6 +236,7 @@ type TxPool struct {
 	chain       blockChain
 	gasPrice    *big.Int
 	txFeed      event.Feed
+	dropFeed    event.Feed
 	scope       event.SubscriptionScope
 	signer      types.Signer
 	mu          sync.RWMutex
@@ -223,6 +247,7 @@ type TxPool struct {
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
+	drops   []DroppedTx // Transactions dropped since the last announcement
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -357,10 +382,13 @@ func (pool *TxPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					for _, tx := range pool.queue[addr].Flatten() {
 						pool.removeTx(tx.Hash(), true)
+						pool.dropped(tx.Hash(), TxDropExpired)
 					}
 				}
 			}
+			drops := pool.takeDrops()
 			pool.mu.Unlock()
+			pool.announceDrops(drops)
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
@@ -396,6 +424,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTxsEvent registers a subscription of DroppedTxsEvent, fired for
+// transactions removed from the pool without being mined.
+func (pool *TxPool) SubscribeDroppedTxsEvent(ch chan<- DroppedTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -408,17 +442,23 @@ func (pool *TxPool) GasPrice() *big.Int {
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
-
 	pool.gasPrice = price
 	for _, tx := range pool.priced.Cap(price, pool.locals) {
 		pool.removeTx(tx.Hash(), false)
+		pool.dropped(tx.Hash(), TxDropUnderpriced)
 	}
+	drops := pool.takeDrops()
+	pool.mu.Unlock()
+
+	pool.announceDrops(drops)
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
 // Nonce returns the next nonce of an account, with all transactions executable
-// by the pool already applied on top.
+// by the pool already applied on top. This is the nonce a new transaction needs
+// to be immediately executable: queued transactions are not accounted for, so
+// if they are blocked by a nonce gap, the returned nonce fills the gap instead of
+// pointing past the highest queued one.
 func (pool *TxPool) Nonce(addr common.Address) uint64 {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -426,6 +466,20 @@ func (pool *TxPool) Nonce(addr common.Address) uint64 {
 	return pool.pendingNonces.get(addr)
 }
 
+// NonceGap returns the next nonce of an account (see Nonce) and whether the pool
+// holds queued transactions of the account that are blocked from execution by a
+// missing nonce. If so, the returned next nonce is the lowest missing one.
+func (pool *TxPool) NonceGap(addr common.Address) (uint64, bool) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	next := pool.pendingNonces.get(addr)
+	if list := pool.queue[addr]; list != nil && !list.Empty() {
+		return next, true
+	}
+	return next, false
+}
+
 // Stats retrieves the current pool stats, namely the number of pending and the
 // number of queued (non-executable) transactions.
 func (pool *TxPool) Stats() (int, int) {
@@ -586,6 +640,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxMeter.Mark(1)
 			pool.removeTx(tx.Hash(), false)
+			pool.dropped(tx.Hash(), TxDropUnderpriced)
 		}
 	}
 
@@ -602,6 +657,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
+			pool.dropped(old.Hash(), TxDropReplaced)
 			pendingReplaceMeter.Mark(1)
 		}
 		pool.all.Add(tx)
@@ -653,6 +709,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.dropped(old.Hash(), TxDropReplaced)
 		queuedReplaceMeter.Mark(1)
 	} else {
 		// Nothing was replaced, bump the queued counter
@@ -693,6 +750,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.dropped(hash, TxDropReplaced)
 
 		pendingDiscardMeter.Mark(1)
 		return false
@@ -701,6 +759,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.dropped(old.Hash(), TxDropReplaced)
 
 		pendingReplaceMeter.Mark(1)
 	} else {
@@ -823,6 +882,12 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
 }
 
+// Has returns an indicator whether txpool has a transaction cached with the
+// given hash.
+func (pool *TxPool) Has(hash common.Hash) bool {
+	return pool.all.Get(hash) != nil
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -1024,8 +1089,13 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		txs := list.Flatten() // Heavy but will be cached and is needed by the miner anyway
 		pool.pendingNonces.set(addr, txs[len(txs)-1].Nonce()+1)
 	}
+	drops := pool.takeDrops()
 	pool.mu.Unlock()
 
+	// Notify subsystems for transactions dropped during the run or since the
+	// last one, e.g. by replacements when adding new transactions
+	pool.announceDrops(drops)
+
 	// Notify subsystems for newly added transactions
 	if len(events) > 0 {
 		var txs []*types.Transaction
@@ -1120,6 +1190,31 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.addTxsLocked(reinject, false)
 }
 
+// dropped records a transaction as removed from the pool without being mined,
+// to be announced once the pool lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) dropped(hash common.Hash, reason string) {
+	pool.drops = append(pool.drops, DroppedTx{Hash: hash, Reason: reason})
+}
+
+// takeDrops returns and clears the transactions dropped since the last call.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) takeDrops() []DroppedTx {
+	drops := pool.drops
+	pool.drops = nil
+	return drops
+}
+
+// announceDrops notifies subscribers of the given dropped transactions. It must
+// be called without holding the pool lock, as subscribers may block.
+func (pool *TxPool) announceDrops(drops []DroppedTx) {
+	if len(drops) > 0 {
+		pool.dropFeed.Send(DroppedTxsEvent{drops})
+	}
+}
+
 // promoteExecutables moves transactions that have become processable from the
 // future queue to the set of pending transactions. During this process, all
 // invalidated transactions (low nonce, low balance) are deleted.
@@ -1145,6 +1240,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.dropped(hash, TxDropNoFunds)
 			log.Trace("Removed unpayable queued transaction", "hash", hash)
 		}
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1167,6 +1263,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			for _, tx := range caps {
 				hash := tx.Hash()
 				pool.all.Remove(hash)
+				pool.dropped(hash, TxDropLimit)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1228,6 +1325,7 @@ func (pool *TxPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.dropped(hash, TxDropLimit)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1255,6 +1353,7 @@ func (pool *TxPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.dropped(hash, TxDropLimit)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1302,6 +1401,7 @@ func (pool *TxPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true)
+				pool.dropped(tx.Hash(), TxDropLimit)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1311,6 +1411,7 @@ func (pool *TxPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true)
+			pool.dropped(txs[i].Hash(), TxDropLimit)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1338,6 +1439,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			pool.dropped(hash, TxDropNoFunds)
 		}
 		pool.priced.Removed(len(olds) + len(drops))
 		pendingNofundsMeter.Mark(int64(len(drops)))
@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -719,6 +720,41 @@ func TestTransactionGapFilling(t *testing.T) {
 	}
 }
 
+// Tests that nonce gaps blocking queued transactions are correctly reported.
+func TestTransactionNonceGap(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account, _ := deriveSender(transaction(0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	if next, gap := pool.NonceGap(account); next != 0 || gap {
+		t.Fatalf("empty pool gap mismatch: have (%d, %v), want (0, false)", next, gap)
+	}
+	// Add a few executable transactions, followed by a gapped one
+	for _, nonce := range []uint64{0, 1, 3} {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", nonce, err)
+		}
+	}
+	if next, gap := pool.NonceGap(account); next != 2 || !gap {
+		t.Fatalf("gapped pool mismatch: have (%d, %v), want (2, true)", next, gap)
+	}
+	// The next nonce must fill the gap, not point past the queued transaction
+	if nonce := pool.Nonce(account); nonce != 2 {
+		t.Fatalf("gapped pool nonce mismatch: have %d, want 2", nonce)
+	}
+	// Fill the gap and ensure everything gets promoted
+	if err := pool.addRemoteSync(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add gap filling transaction: %v", err)
+	}
+	if next, gap := pool.NonceGap(account); next != 4 || gap {
+		t.Fatalf("filled pool mismatch: have (%d, %v), want (4, false)", next, gap)
+	}
+}
+
 // Tests that if the transaction count belonging to a single account goes above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 func TestTransactionQueueAccountLimiting(t *testing.T) {
@@ -1518,6 +1554,262 @@ func TestTransactionReplacement(t *testing.T) {
 	}
 }
 
+// Tests that transactions leaving the pool without being mined are announced
+// along with the reason of their removal.
+func TestTransactionDropEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsEvent, 32)
+	sub := pool.SubscribeDroppedTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	expect := func(want ...DroppedTx) {
+		t.Helper()
+
+		var have []DroppedTx
+		for len(have) < len(want) {
+			select {
+			case ev := <-drops:
+				have = append(have, ev.Txs...)
+			case <-time.After(time.Second):
+				t.Fatalf("drop event #%d not fired", len(have))
+			}
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("dropped transactions mismatch: have %v, want %v", have, want)
+		}
+	}
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	// Replace a pending transaction, the original one should be reported
+	original := pricedTransaction(0, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(original); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+	replacement := pricedTransaction(0, 100000, big.NewInt(2), key)
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+	expect(DroppedTx{Hash: original.Hash(), Reason: TxDropReplaced})
+
+	// Raise the price limit above the replacement, it should be evicted
+	pool.SetGasPrice(big.NewInt(3))
+	expect(DroppedTx{Hash: replacement.Hash(), Reason: TxDropUnderpriced})
+
+	select {
+	case ev := <-drops:
+		t.Fatalf("unexpected drop event: %v", ev.Txs)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// collectDrops gathers the transactions announced as dropped until no more events
+// arrive for a while, failing if any transaction is announced twice.
+func collectDrops(t *testing.T, drops <-chan DroppedTxsEvent) map[common.Hash]string {
+	t.Helper()
+
+	have := make(map[common.Hash]string)
+	for {
+		select {
+		case ev := <-drops:
+			for _, tx := range ev.Txs {
+				if _, ok := have[tx.Hash]; ok {
+					t.Errorf("transaction %x dropped twice", tx.Hash)
+				}
+				have[tx.Hash] = tx.Reason
+			}
+		case <-time.After(100 * time.Millisecond):
+			return have
+		}
+	}
+}
+
+// checkDropAccounting verifies that every transaction added to the pool is either
+// still tracked by it or was announced as dropped for the given reason.
+func checkDropAccounting(t *testing.T, pool *TxPool, txs types.Transactions, drops map[common.Hash]string, reason string) {
+	t.Helper()
+
+	for _, tx := range txs {
+		known, dropped := pool.all.Get(tx.Hash()) != nil, drops[tx.Hash()]
+		switch {
+		case known && dropped != "":
+			t.Errorf("transaction %x both in the pool and dropped", tx.Hash())
+		case !known && dropped == "":
+			t.Errorf("transaction %x removed without announcement", tx.Hash())
+		case !known && dropped != reason:
+			t.Errorf("transaction %x drop reason mismatch: have %s, want %s", tx.Hash(), dropped, reason)
+		}
+	}
+	if len(drops) == 0 {
+		t.Errorf("no transactions dropped")
+	}
+}
+
+// Tests that transactions dropped for lack of funds are announced, but those
+// removed for being mined are not.
+func TestTransactionDropEventsNoFunds(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsEvent, 32)
+	sub := pool.SubscribeDroppedTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	account, _ := deriveSender(transaction(0, 0, key))
+	pool.currentState.AddBalance(account, big.NewInt(1000))
+
+	var (
+		tx0  = transaction(0, 100, key)
+		tx1  = transaction(1, 200, key)
+		tx2  = transaction(2, 300, key)
+		tx10 = transaction(10, 100, key)
+		tx12 = transaction(12, 300, key)
+	)
+	pool.promoteTx(account, tx0.Hash(), tx0)
+	pool.promoteTx(account, tx1.Hash(), tx1)
+	pool.promoteTx(account, tx2.Hash(), tx2)
+	pool.enqueueTx(tx10.Hash(), tx10)
+	pool.enqueueTx(tx12.Hash(), tx12)
+
+	// Reduce the balance of the account, the now unpayable ones should be reported
+	pool.currentState.AddBalance(account, big.NewInt(-650))
+	<-pool.requestReset(nil, nil)
+
+	want := map[common.Hash]string{tx2.Hash(): TxDropNoFunds, tx12.Hash(): TxDropNoFunds}
+	if have := collectDrops(t, drops); !reflect.DeepEqual(have, want) {
+		t.Fatalf("dropped transactions mismatch: have %v, want %v", have, want)
+	}
+	// Mine the remaining pending transactions, nothing should be reported
+	pool.currentState.SetNonce(account, 2)
+	<-pool.requestReset(nil, nil)
+
+	if pool.all.Get(tx0.Hash()) != nil || pool.all.Get(tx1.Hash()) != nil {
+		t.Fatalf("mined transactions still in the pool")
+	}
+	if have := collectDrops(t, drops); len(have) != 0 {
+		t.Fatalf("mined transactions reported as dropped: %v", have)
+	}
+}
+
+// Tests that transactions evicted by the global pending limit are all announced.
+func TestTransactionDropEventsPendingLimit(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = config.AccountSlots * 10
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsEvent, 1024)
+	sub := pool.SubscribeDroppedTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := 0; j < int(config.GlobalSlots)/len(keys)*2; j++ {
+			txs = append(txs, transaction(uint64(j), 100000, key))
+		}
+	}
+	pool.AddRemotesSync(txs)
+
+	checkDropAccounting(t, pool, txs, collectDrops(t, drops), TxDropLimit)
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that transactions evicted by the account and global queue limits are all
+// announced.
+func TestTransactionDropEventsQueueLimit(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalQueue = config.AccountQueue*3 - 1
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsEvent, 1024)
+	sub := pool.SubscribeDroppedTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	// Queue up more transactions than both the account and the global limits allow
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := uint64(1); j <= config.AccountQueue+5; j++ {
+			txs = append(txs, transaction(j, 100000, key))
+		}
+	}
+	pool.AddRemotesSync(txs)
+
+	checkDropAccounting(t, pool, txs, collectDrops(t, drops), TxDropLimit)
+	if _, queued := pool.Stats(); queued > int(config.GlobalQueue) {
+		t.Fatalf("total transactions overflow allowance: %d > %d", queued, config.GlobalQueue)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that queued transactions expiring are announced as dropped.
+func TestTransactionDropEventsExpired(t *testing.T) {
+	// Reduce the eviction interval to a testable amount
+	defer func(old time.Duration) { evictionInterval = old }(evictionInterval)
+	evictionInterval = time.Second
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.Lifetime = time.Second
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsEvent, 32)
+	sub := pool.SubscribeDroppedTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	remote, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	tx := pricedTransaction(1, 100000, big.NewInt(1), remote)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	select {
+	case ev := <-drops:
+		want := []DroppedTx{{Hash: tx.Hash(), Reason: TxDropExpired}}
+		if !reflect.DeepEqual(ev.Txs, want) {
+			t.Fatalf("dropped transactions mismatch: have %v, want %v", ev.Txs, want)
+		}
+	case <-time.After(4 * config.Lifetime):
+		t.Fatalf("expired transaction not announced")
+	}
+}
+
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
 func TestTransactionJournaling(t *testing.T)         { testTransactionJournaling(t, false) }
@@ -20,7 +20,6 @@ package graphql
 import (
 	"context"
 	"errors"
-	"time"
 
 	"github.com/ccmchain/go-ccmchain"
 	"github.com/ccmchain/go-ccmchain/common"
@@ -847,7 +846,7 @@ func (b *Block) Call(ctx context.Context, args struct {
 		}
 	}
 
-	result, gas, failed, err := ccmapi.DoCall(ctx, b.backend, args.Data, *b.num, vm.Config{}, 5*time.Second, b.backend.RPCGasCap())
+	result, gas, failed, err := ccmapi.DoCall(ctx, b.backend, args.Data, *b.num, vm.Config{}, b.backend.Requests().Timeout("ccm_call"), b.backend.RPCGasCap())
 	status := hexutil.Uint64(1)
 	if failed {
 		status = 0
@@ -918,7 +917,7 @@ func (p *Pending) Account(ctx context.Context, args struct {
 func (p *Pending) Call(ctx context.Context, args struct {
 	Data ccmapi.CallArgs
 }) (*CallResult, error) {
-	result, gas, failed, err := ccmapi.DoCall(ctx, p.backend, args.Data, rpc.PendingBlockNumber, vm.Config{}, 5*time.Second, p.backend.RPCGasCap())
+	result, gas, failed, err := ccmapi.DoCall(ctx, p.backend, args.Data, rpc.PendingBlockNumber, vm.Config{}, p.backend.Requests().Timeout("ccm_call"), p.backend.RPCGasCap())
 	status := hexutil.Uint64(1)
 	if failed {
 		status = 0
@@ -0,0 +1,60 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/crypto"
+)
+
+// emptyCodeHash is the code hash of accounts without code.
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// AccountInfo is the aggregated state of a single account, as returned by
+// ccm_getAccount.
+type AccountInfo struct {
+	Balance     *hexutil.Big   `json:"balance"`
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	CodeSize    hexutil.Uint64 `json:"codeSize"`
+	StorageRoot common.Hash    `json:"storageRoot"`
+}
+
+// ReadAccountInfo collects the state of the given account from an already opened
+// state. Accounts that do not exist are reported with the empty code hash and the
+// empty storage root, in line with ccm_getProof.
+func ReadAccountInfo(state *state.StateDB, addr common.Address) (*AccountInfo, error) {
+	info := &AccountInfo{
+		Balance:     (*hexutil.Big)(state.GetBalance(addr)),
+		Nonce:       hexutil.Uint64(state.GetNonce(addr)),
+		CodeHash:    emptyCodeHash,
+		StorageRoot: types.EmptyRootHash,
+	}
+	if storageTrie := state.StorageTrie(addr); storageTrie != nil {
+		info.CodeHash = state.GetCodeHash(addr)
+		info.StorageRoot = storageTrie.Hash()
+	}
+	// The state looks up the size of the empty code in the database too, failing
+	// for every account without code, so only ask for contracts.
+	if info.CodeHash != emptyCodeHash {
+		info.CodeSize = hexutil.Uint64(state.GetCodeSize(addr))
+	}
+	return info, state.Error()
+}
@@ -22,13 +22,19 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ccmchain/go-ccmchain/accounts"
+	"github.com/ccmchain/go-ccmchain/accounts/abi"
 	"github.com/ccmchain/go-ccmchain/accounts/keystore"
 	"github.com/ccmchain/go-ccmchain/accounts/scwallet"
+	"github.com/ccmchain/go-ccmchain/accounts/usbwallet"
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/common/hexutil"
 	"github.com/ccmchain/go-ccmchain/common/math"
@@ -68,6 +74,26 @@ func (s *PublicCcmchainAPI) GasPrice(ctx context.Context) (*hexutil.Big, error)
 	return (*hexutil.Big)(price), err
 }
 
+// GasPriceFiltering reports whether the gas price suggestions skip the sampled
+// transactions not priced by the fee market, such as zero gas price ones.
+func (s *PublicCcmchainAPI) GasPriceFiltering() bool {
+	return s.b.GasPriceFiltering()
+}
+
+// GasPriceTier returns a suggestion for a gas price matching the requested speed
+// tier: "safe", "standard" (same as GasPrice) or "fast".
+func (s *PublicCcmchainAPI) GasPriceTier(ctx context.Context, tier string) (*hexutil.Big, error) {
+	price, err := s.b.SuggestPriceTier(ctx, tier)
+	return (*hexutil.Big)(price), err
+}
+
+// MaxPriorityFeePerGas returns a suggestion for the priority fee (tip) to pay on
+// top of the minimum gas price, based on recently included transactions.
+func (s *PublicCcmchainAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := s.b.SuggestPriorityFee(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
 // ProtocolVersion returns the current Ccmchain protocol version this node supports
 func (s *PublicCcmchainAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
@@ -143,6 +169,120 @@ func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
+// txPoolStatusDebounce is the minimum time between two txpool status updates
+// delivered to a subscriber.
+const txPoolStatusDebounce = 250 * time.Millisecond
+
+// StatusUpdates creates a subscription that is notified with the number of
+// pending and queued transactions in the pool each time they change. Updates
+// are debounced, so bursts of pool activity result in a single notification.
+func (s *PublicTxPoolAPI) StatusUpdates(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		// Transactions enter the pool via new tx events and leave it either when
+		// dropped or when mined on a new chain head, so watch all for potential
+		// size changes. Light clients don't report drops, rely on the rest there.
+		txs := make(chan core.NewTxsEvent, 128)
+		txsSub := s.b.SubscribeNewTxsEvent(txs)
+		defer txsSub.Unsubscribe()
+
+		drops := make(chan core.DroppedTxsEvent, 128)
+		if dropsSub, err := s.b.SubscribeDroppedTxsEvent(drops); err == nil {
+			defer dropsSub.Unsubscribe()
+		}
+		heads := make(chan core.ChainHeadEvent, 10)
+		headsSub := s.b.SubscribeChainHeadEvent(heads)
+		defer headsSub.Unsubscribe()
+
+		var (
+			lastPending, lastQueued = s.b.Stats()
+			debounce                = time.NewTimer(0)
+			scheduled               bool
+		)
+		<-debounce.C
+		defer debounce.Stop()
+
+		for {
+			select {
+			case <-txs:
+			case <-drops:
+			case <-heads:
+			case <-debounce.C:
+				scheduled = false
+				if pending, queued := s.b.Stats(); pending != lastPending || queued != lastQueued {
+					lastPending, lastQueued = pending, queued
+					notifier.Notify(rpcSub.ID, map[string]hexutil.Uint{
+						"pending": hexutil.Uint(pending),
+						"queued":  hexutil.Uint(queued),
+					})
+				}
+				continue
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+			if !scheduled {
+				debounce.Reset(txPoolStatusDebounce)
+				scheduled = true
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// DroppedTransactions creates a subscription that is notified with the hash and
+// the drop reason of every transaction removed from the pool without being mined,
+// e.g. when replaced, evicted for better paying ones or exceeding the pool limits.
+func (s *PublicTxPoolAPI) DroppedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	drops := make(chan core.DroppedTxsEvent, 128)
+	dropsSub, err := s.b.SubscribeDroppedTxsEvent(drops)
+	if err != nil {
+		return &rpc.Subscription{}, err
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer dropsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-drops:
+				for _, tx := range ev.Txs {
+					notifier.Notify(rpcSub.ID, tx)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// PendingFeeHistogram returns the number of pending transactions within each of
+// the given number of equally wide gas price ranges.
+func (s *PublicTxPoolAPI) PendingFeeHistogram(buckets *hexutil.Uint64) ([]*FeeBucket, error) {
+	n := DefaultFeeHistogramBuckets
+	if buckets != nil {
+		n = int(*buckets)
+	}
+	if n < 1 || n > MaxFeeHistogramBuckets {
+		return nil, fmt.Errorf("invalid bucket count %d, must be between 1 and %d", n, MaxFeeHistogramBuckets)
+	}
+	return s.b.PendingFeeHistogram(n)
+}
+
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
 func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
@@ -207,6 +347,7 @@ type PrivateAccountAPI struct {
 	am        *accounts.Manager
 	nonceLock *AddrLocker
 	b         Backend
+	ksLock    sync.Mutex // Serializes keystore registrations
 }
 
 // NewPrivateAccountAPI create a new PrivateAccountAPI.
@@ -257,6 +398,54 @@ func (s *PrivateAccountAPI) ListWallets() []rawWallet {
 	return wallets
 }
 
+// accountInfo is an account annotated with the provenance of its backing wallet.
+type accountInfo struct {
+	Address  common.Address `json:"address"`
+	URL      string         `json:"url"`
+	Type     string         `json:"type"`
+	Path     string         `json:"path,omitempty"`
+	Modified *time.Time     `json:"modified,omitempty"`
+}
+
+// walletType classifies a wallet by the backend it originates from.
+func walletType(url accounts.URL) string {
+	switch url.Scheme {
+	case keystore.KeyStoreScheme:
+		return "keystore"
+	case usbwallet.LedgerScheme, usbwallet.TrezorScheme:
+		return "hardware"
+	case scwallet.Scheme:
+		return "smartcard"
+	default:
+		return url.Scheme
+	}
+}
+
+// AccountsInfo will return all the accounts this node manages, each annotated
+// with the type of its backing wallet and, for keystore accounts, the path and
+// last modification time of the key file.
+func (s *PrivateAccountAPI) AccountsInfo() []accountInfo {
+	infos := make([]accountInfo, 0) // return [] instead of nil if empty
+	for _, wallet := range s.am.Wallets() {
+		for _, account := range wallet.Accounts() {
+			info := accountInfo{
+				Address: account.Address,
+				URL:     account.URL.String(),
+				Type:    walletType(wallet.URL()),
+			}
+			if account.URL.Scheme == keystore.KeyStoreScheme {
+				info.Path = account.URL.Path
+				if stat, err := os.Stat(info.Path); err == nil {
+					modified := stat.ModTime()
+					info.Modified = &modified
+				}
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
 // OpenWallet initiates a hardware wallet opening procedure, establishing a USB
 // connection and attempting to authenticate via the provided passphrase. Note,
 // the mccmod may return an extra challenge requiring a second open (e.g. the
@@ -307,6 +496,37 @@ func fetchKeystore(am *accounts.Manager) *keystore.KeyStore {
 	return am.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 }
 
+// AddKeystore registers the keystore directory at the given path as an additional
+// account backend and returns the number of accounts discovered in it. New accounts
+// are still created in the primary keystore.
+func (s *PrivateAccountAPI) AddKeystore(path string) (int, error) {
+	keydir, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(keydir)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("%s is not a directory", keydir)
+	}
+	s.ksLock.Lock()
+	defer s.ksLock.Unlock()
+
+	for _, backend := range s.am.Backends(keystore.KeyStoreType) {
+		if backend.(*keystore.KeyStore).Dir() == keydir {
+			return 0, fmt.Errorf("keystore %s already registered", keydir)
+		}
+	}
+	ks := keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP)
+	s.am.AddBackend(ks)
+
+	count := len(ks.Accounts())
+	log.Info("Added keystore directory", "path", keydir, "accounts", count)
+	return count, nil
+}
+
 // ImportRawKey stores the given hex encoded ECDSA key into the key directory,
 // encrypting it with the passphrase.
 func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (common.Address, error) {
@@ -449,19 +669,17 @@ func (s *PrivateAccountAPI) Sign(ctx context.Context, data hexutil.Bytes, addr c
 // hash = keccak256("\x19Ccmchain Signed Message:\n"${message length}${message})
 // addr = ecrecover(hash, signature)
 //
-// Note, the signature must conform to the secp256k1 curve R, S and V values, where
-// the V value must be 27 or 28 for legacy reasons.
+// Note, the signature must conform to the secp256k1 curve R, S and V values. Both
+// the yellow paper V values 27/28 (as produced by ccm_sign and most wallets) and
+// the raw recovery ids 0/1 (as produced by some signing libraries) are accepted,
+// and normalized to 0/1 before recovery.
 //
 // https://github.com/ccmchain/go-ccmchain/wiki/Management-APIs#personal_ecRecover
 func (s *PrivateAccountAPI) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error) {
-	if len(sig) != 65 {
-		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
-	}
-	if sig[64] != 27 && sig[64] != 28 {
-		return common.Address{}, fmt.Errorf("invalid Ccmchain signature (V is not 27 or 28)")
+	sig, err := normalizeSignature(sig)
+	if err != nil {
+		return common.Address{}, err
 	}
-	sig[64] -= 27 // Transform yellow paper V from 27/28 to 0/1
-
 	rpk, err := crypto.SigToPub(accounts.TextHash(data), sig)
 	if err != nil {
 		return common.Address{}, err
@@ -469,6 +687,28 @@ func (s *PrivateAccountAPI) EcRecover(ctx context.Context, data, sig hexutil.Byt
 	return crypto.PubkeyToAddress(*rpk), nil
 }
 
+// normalizeSignature validates a 65 byte [R || S || V] signature and returns a
+// copy of it with V transformed into the 0/1 recovery id, accepting both the
+// yellow paper 27/28 and the raw 0/1 conventions.
+func normalizeSignature(sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes long, got %d", len(sig))
+	}
+	sig = common.CopyBytes(sig)
+	switch sig[64] {
+	case 27, 28:
+		sig[64] -= 27 // Transform yellow paper V from 27/28 to 0/1
+	case 0, 1:
+	default:
+		return nil, fmt.Errorf("invalid Ccmchain signature (V is %d, not 0, 1, 27 or 28)", sig[64])
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[64], r, s, false) {
+		return nil, errors.New("invalid Ccmchain signature (R or S out of range)")
+	}
+	return sig, nil
+}
+
 // SignAndSendTransaction was renamed to SendTransaction. This mccmod is deprecated
 // and will be removed in the future. It primary goal is to give clients time to update.
 func (s *PrivateAccountAPI) SignAndSendTransaction(ctx context.Context, args SendTxArgs, passwd string) (common.Hash, error) {
@@ -533,12 +773,33 @@ func (s *PublicBlockChainAPI) ChainId() *hexutil.Big {
 	return (*hexutil.Big)(s.b.ChainConfig().ChainID)
 }
 
+// ForkSchedule returns the chain configuration along with the ordered schedule
+// of its forks and the latest one active at the chain head.
+func (s *PublicBlockChainAPI) ForkSchedule() *ChainConfigFull {
+	return s.b.ChainConfigFull()
+}
+
 // BlockNumber returns the block number of the chain head.
 func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
 	header, _ := s.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber) // latest header should always be available
 	return hexutil.Uint64(header.Number.Uint64())
 }
 
+// AvailableBlockRange returns the lowest and highest block numbers whose full
+// block data can be queried from this node.
+func (s *PublicBlockChainAPI) AvailableBlockRange() map[string]hexutil.Uint64 {
+	lowest, highest := s.b.BlockRange()
+	return map[string]hexutil.Uint64{
+		"lowest":  hexutil.Uint64(lowest),
+		"highest": hexutil.Uint64(highest),
+	}
+}
+
+// TotalDifficulty returns the total difficulty of the chain head.
+func (s *PublicBlockChainAPI) TotalDifficulty() *hexutil.Big {
+	return (*hexutil.Big)(s.b.CurrentTd())
+}
+
 // GetBalance returns the amount of wei for the given address in the state of the
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
@@ -550,6 +811,31 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
 }
 
+// GetBalances returns the amount of wei for each of the given addresses in the
+// state of the given block number, in the same order as the addresses.
+func (s *PublicBlockChainAPI) GetBalances(ctx context.Context, addresses []common.Address, blockNr rpc.BlockNumber) ([]*hexutil.Big, error) {
+	if len(addresses) > MaxBalanceBatch {
+		return nil, fmt.Errorf("too many addresses: %d, max %d", len(addresses), MaxBalanceBatch)
+	}
+	balances, err := s.b.BalancesAt(ctx, addresses, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*hexutil.Big, len(balances))
+	for i, balance := range balances {
+		result[i] = (*hexutil.Big)(balance)
+	}
+	return result, nil
+}
+
+// GetAccount returns the balance, nonce, code hash, code size and storage root of
+// the given address in the state of the given block number. All fields are read
+// from the same state, sparing callers the separate getBalance, getTransactionCount
+// and getCode round trips.
+func (s *PublicBlockChainAPI) GetAccount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*AccountInfo, error) {
+	return s.b.GetAccount(ctx, address, blockNr)
+}
+
 // Result structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -616,6 +902,43 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 	}, state.Error()
 }
 
+// NewFinalityHeads creates a subscription that is triggered each time a new head
+// is appended to the chain. Every notification carries the header along with
+// the number and hash of the latest final block, which is the confirmation depth
+// behind the head, based on the signer set on clique chains or the configured
+// depth otherwise. The finalized flag reports whether any block is final yet.
+func (s *PublicBlockChainAPI) NewFinalityHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		heads := make(chan core.FinalityHeadEvent)
+		headsSub := s.b.SubscribeFinalityHeadEvent(heads)
+		defer headsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-heads:
+				fields := RPCMarshalHeader(ev.Header)
+				fields["finalized"] = ev.Finalized != nil
+				if ev.Finalized != nil {
+					fields["finalizedNumber"] = (*hexutil.Big)(ev.Finalized.Number)
+					fields["finalizedHash"] = ev.Finalized.Hash()
+				}
+				notifier.Notify(rpcSub.ID, fields)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // GetHeaderByNumber returns the requested canonical block header.
 // * When blockNr is -1 the chain head is returned.
 // * When blockNr is -2 the pending chain head is returned.
@@ -692,17 +1015,15 @@ func (s *PublicBlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context,
 // GetUncleByBlockHashAndIndex returns the uncle block for the given block hash and index. When fullTx is true
 // all transactions in the block are returned in full detail, otherwise only the transaction hash is returned.
 func (s *PublicBlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (map[string]interface{}, error) {
-	block, err := s.b.GetBlock(ctx, blockHash)
-	if block != nil {
-		uncles := block.Uncles()
-		if index >= hexutil.Uint(len(uncles)) {
-			log.Debug("Requested uncle not found", "number", block.Number(), "hash", blockHash, "index", index)
-			return nil, nil
-		}
-		block = types.NewBlockWithHeader(uncles[index])
-		return s.rpcMarshalBlock(block, false, false)
+	uncle, err := s.b.GetUncle(ctx, blockHash, int(index))
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	if uncle == nil {
+		log.Debug("Requested uncle not found", "hash", blockHash, "index", index)
+		return nil, nil
+	}
+	return s.rpcMarshalBlock(types.NewBlockWithHeader(uncle), false, false)
 }
 
 // GetUncleCountByBlockNumber returns number of uncles in the block for the given block number
@@ -733,6 +1054,23 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 	return code, state.Error()
 }
 
+// GetCodeSize returns the size of the code stored at the given address in the
+// state for the given block number, which is 0 for externally owned accounts.
+func (s *PublicBlockChainAPI) GetCodeSize(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
+	size, err := s.b.GetCodeSize(ctx, address, blockNr)
+	return hexutil.Uint64(size), err
+}
+
+// GasUsageStats returns the gas limit of the current head together with the
+// average gas usage ratio and its trend over the given number of recent blocks.
+func (s *PublicBlockChainAPI) GasUsageStats(ctx context.Context, blocks *hexutil.Uint64) (*GasUsageStats, error) {
+	n := DefaultGasUsageBlocks
+	if blocks != nil {
+		n = int(*blocks)
+	}
+	return s.b.GasUsageStats(ctx, n)
+}
+
 // GetStorageAt returns the storage from the state at the given address, key and
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
@@ -755,13 +1093,10 @@ type CallArgs struct {
 	Data     *hexutil.Bytes  `json:"data"`
 }
 
-func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
-
-	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
-	if state == nil || err != nil {
-		return nil, 0, false, err
-	}
+// ToMessage converts the call arguments into a message that can be executed by
+// the EVM, filling in defaults for any unset fields and capping the gas allowance
+// to the given global cap.
+func (args *CallArgs) ToMessage(b Backend, globalGasCap *big.Int) types.Message {
 	// Set sender address or use a default if none specified
 	var addr common.Address
 	if args.From == nil {
@@ -797,8 +1132,35 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 		data = []byte(*args.Data)
 	}
 
+	return types.NewMessage(addr, args.To, 0, value, gas, gasPrice, data, false)
+}
+
+// CheckCallDataSize returns an error if the input data of the call exceeds the
+// size limit configured on the backend.
+func CheckCallDataSize(b Backend, args CallArgs) error {
+	limit := b.RPCCallDataCap()
+	if limit == 0 || args.Data == nil {
+		return nil
+	}
+	if size := uint64(len(*args.Data)); size > limit {
+		return fmt.Errorf("call data too large: %d bytes, max %d", size, limit)
+	}
+	return nil
+}
+
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	if err := CheckCallDataSize(b, args); err != nil {
+		return nil, 0, false, err
+	}
+
+	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, 0, false, err
+	}
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, data, false)
+	msg := args.ToMessage(b, globalGasCap)
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -813,7 +1175,7 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 	defer cancel()
 
 	// Get a new instance of the EVM.
-	evm, vmError, err := b.GetEVM(ctx, msg, state, header)
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header, nil)
 	if err != nil {
 		return nil, 0, false, err
 	}
@@ -841,11 +1203,22 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := DoCall(ctx, s.b, args, blockNr, vm.Config{}, 5*time.Second, s.b.RPCGasCap())
+	ctx, done := s.b.Requests().Track(ctx, "ccm_call")
+	defer done()
+
+	result, _, _, err := DoCall(ctx, s.b, args, blockNr, vm.Config{}, s.b.Requests().Timeout("ccm_call"), s.b.RPCGasCap())
 	return (hexutil.Bytes)(result), err
 }
 
 func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, gasCap *big.Int) (hexutil.Uint64, error) {
+	if err := CheckCallDataSize(b, args); err != nil {
+		return 0, err
+	}
+	// Retrieve the block to estimate against, also acting as the gas ceiling
+	header, err := b.HeaderByNumber(ctx, blockNr)
+	if err != nil || header == nil {
+		return 0, fmt.Errorf("block %d not found", blockNr)
+	}
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -855,12 +1228,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.Bl
 	if args.Gas != nil && uint64(*args.Gas) >= params.TxGas {
 		hi = uint64(*args.Gas)
 	} else {
-		// Retrieve the block to act as the gas ceiling
-		block, err := b.BlockByNumber(ctx, blockNr)
-		if err != nil {
-			return 0, err
-		}
-		hi = block.GasLimit()
+		hi = header.GasLimit
 	}
 	if gasCap != nil && hi > gasCap.Uint64() {
 		log.Warn("Caller gas above allowance, capping", "requested", hi, "cap", gasCap)
@@ -872,7 +1240,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.Bl
 	executable := func(gas uint64) bool {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		_, _, failed, err := DoCall(ctx, b, args, rpc.PendingBlockNumber, vm.Config{}, 0, gasCap)
+		_, _, failed, err := DoCall(ctx, b, args, blockNr, vm.Config{}, 0, gasCap)
 		if err != nil || failed {
 			return false
 		}
@@ -897,9 +1265,22 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.Bl
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
-	return DoEstimateGas(ctx, s.b, args, rpc.PendingBlockNumber, s.b.RPCGasCap())
+// given transaction against the state of the requested block, defaulting to the
+// current pending block if none is given.
+//
+// Note, estimates against the pending block are inherently racy: the pending
+// state changes whenever the transaction pool is updated, so the transactions
+// the estimate depends on may be reordered, replaced or dropped before the
+// estimated transaction is included.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, blockNr *rpc.BlockNumber) (hexutil.Uint64, error) {
+	ctx, done := s.b.Requests().Track(ctx, "ccm_estimateGas")
+	defer done()
+
+	number := rpc.PendingBlockNumber
+	if blockNr != nil {
+		number = *blockNr
+	}
+	return DoEstimateGas(ctx, s.b, args, number, s.b.RPCGasCap())
 }
 
 // ExecutionResult groups all structured logs emitted by the EVM
@@ -994,19 +1375,21 @@ func RPCMarshalBlock(block *types.Block, inclTx bool, fullTx bool) (map[string]i
 	fields["size"] = block.Size()
 
 	if inclTx {
-		formatTx := func(tx *types.Transaction) (interface{}, error) {
+		formatTx := func(index int, tx *types.Transaction) (interface{}, error) {
 			return tx.Hash(), nil
 		}
 		if fullTx {
-			formatTx = func(tx *types.Transaction) (interface{}, error) {
-				return newRPCTransactionFromBlockHash(block, tx.Hash()), nil
+			// Resolve full transactions by position: looking them up by hash would
+			// be quadratic in the number of transactions in the block
+			formatTx = func(index int, tx *types.Transaction) (interface{}, error) {
+				return newRPCTransactionFromBlockIndex(block, uint64(index)), nil
 			}
 		}
 		txs := block.Transactions()
 		transactions := make([]interface{}, len(txs))
 		var err error
 		for i, tx := range txs {
-			if transactions[i], err = formatTx(tx); err != nil {
+			if transactions[i], err = formatTx(i, tx); err != nil {
 				return nil, err
 			}
 		}
@@ -1137,8 +1520,8 @@ func NewPublicTransactionPoolAPI(b Backend, nonceLock *AddrLocker) *PublicTransa
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
 func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
-	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		n := hexutil.Uint(len(block.Transactions()))
+	if count, err := s.b.BlockTransactionCountByNumber(ctx, blockNr); err == nil {
+		n := hexutil.Uint(count)
 		return &n
 	}
 	return nil
@@ -1146,8 +1529,8 @@ func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
 func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
-	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
-		n := hexutil.Uint(len(block.Transactions()))
+	if count, err := s.b.BlockTransactionCount(ctx, blockHash); err == nil {
+		n := hexutil.Uint(count)
 		return &n
 	}
 	return nil
@@ -1204,6 +1587,33 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 	return (*hexutil.Uint64)(&nonce), state.Error()
 }
 
+// GetTransactionStatus returns the status of the transaction with the given hash
+// as one of "unknown", "queued", "pending" or "included", without retrieving the
+// transaction itself.
+func (s *PublicTransactionPoolAPI) GetTransactionStatus(ctx context.Context, hash common.Hash) string {
+	return s.b.PoolTransactionStatus(ctx, hash).String()
+}
+
+// GetNonceGap returns the next nonce the transaction pool expects from the given
+// address, along with whether any queued transactions of the address are stuck
+// behind a missing nonce. If they are, lowestMissingNonce is the nonce that needs
+// to be filled for them to become executable.
+func (s *PublicTransactionPoolAPI) GetNonceGap(ctx context.Context, address common.Address) (map[string]interface{}, error) {
+	next, gapped, err := s.b.GetPoolNonceGap(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		"nextNonce":          hexutil.Uint64(next),
+		"hasGap":             gapped,
+		"lowestMissingNonce": nil,
+	}
+	if gapped {
+		fields["lowestMissingNonce"] = hexutil.Uint64(next)
+	}
+	return fields, nil
+}
+
 // GetTransactionByHash returns the transaction for the given hash
 func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
 	// Try to return an already finalized transaction
@@ -1240,6 +1650,79 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	return rlp.EncodeToBytes(tx)
 }
 
+// TransactionInclusion creates a subscription tracking the inclusion of the given
+// transaction. Subscribers are notified with the transaction's receipt once it
+// is included in a canonical block, and with a removal notice if that block is
+// later reorged out. The transaction may afterwards be reported included again.
+func (s *PublicTransactionPoolAPI) TransactionInclusion(ctx context.Context, hash common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan TxInclusionEvent)
+		sub := NewTxInclusionSubscription(s.b, hash, events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				fields := map[string]interface{}{
+					"transactionHash":  ev.TxHash,
+					"blockHash":        ev.BlockHash,
+					"blockNumber":      hexutil.Uint64(ev.BlockNumber),
+					"transactionIndex": hexutil.Uint64(ev.Index),
+					"removed":          ev.Removed,
+				}
+				if !ev.Removed {
+					if receipt, err := s.GetTransactionReceipt(context.Background(), hash); err == nil && receipt != nil {
+						fields["receipt"] = receipt
+					}
+				}
+				notifier.Notify(rpcSub.ID, fields)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// FilteredPendingTransactions creates a subscription that is notified with each
+// transaction entering the pool that matches the given filter, sparing clients
+// interested in e.g. a single contract from receiving the entire pool traffic.
+func (s *PublicTransactionPoolAPI) FilteredPendingTransactions(ctx context.Context, filter PendingTxFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		txs := make(chan core.NewTxsEvent, 128)
+		sub := s.b.SubscribeFilteredTxsEvent(txs, filter)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-txs:
+				for _, tx := range ev.Txs {
+					notifier.Notify(rpcSub.ID, newRPCPendingTransaction(tx))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
@@ -1291,6 +1774,15 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	return fields, nil
 }
 
+// GetReceiptsByTxHashes returns the status, gas used and block number of each of
+// the given transactions, with null entries for the ones not found.
+func (s *PublicTransactionPoolAPI) GetReceiptsByTxHashes(ctx context.Context, hashes []common.Hash) ([]*ReceiptInfo, error) {
+	if len(hashes) > MaxReceiptBatch {
+		return nil, fmt.Errorf("too many transaction hashes: %d, max %d", len(hashes), MaxReceiptBatch)
+	}
+	return s.b.GetReceiptsByTxHashes(ctx, hashes)
+}
+
 // sign is a helper function that signs a transaction with the private key of the given address.
 func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	// Look up the wallet containing the requested signer
@@ -1331,7 +1823,7 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		args.Value = new(hexutil.Big)
 	}
 	if args.Nonce == nil {
-		nonce, err := b.GetPoolNonce(ctx, args.From)
+		nonce, err := b.NextSendableNonce(ctx, args.From)
 		if err != nil {
 			return err
 		}
@@ -1451,6 +1943,24 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// DecodeRawTransaction decodes the given RLP encoded signed transaction without
+// submitting it, returning its fields along with the recovered sender and the
+// transaction hash.
+func (s *PublicTransactionPoolAPI) DecodeRawTransaction(encodedTx hexutil.Bytes) (*RPCTransaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	var signer types.Signer = types.FrontierSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+	if _, err := types.Sender(signer, tx); err != nil {
+		return nil, fmt.Errorf("invalid transaction signature: %v", err)
+	}
+	return newRPCPendingTransaction(tx), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ccmchain Signed Message:\n" + len(message) + message).
 //
@@ -1605,6 +2115,74 @@ func (api *PublicDebugAPI) GetBlockRlp(ctx context.Context, number uint64) (stri
 	return fmt.Sprintf("%x", encoded), nil
 }
 
+// storedHeader retrieves the header of the block identified by the given number,
+// tag or hash. The pending block is rejected as it is never stored.
+func (api *PublicDebugAPI) storedHeader(ctx context.Context, numberOrHash string) (*types.Header, error) {
+	var (
+		header *types.Header
+		err    error
+	)
+	if len(numberOrHash) == 2*common.HashLength+2 {
+		hash, decErr := hexutil.Decode(numberOrHash)
+		if decErr != nil {
+			return nil, decErr
+		}
+		header, err = api.b.HeaderByHash(ctx, common.BytesToHash(hash))
+	} else {
+		var number rpc.BlockNumber
+		if err := number.UnmarshalJSON([]byte(numberOrHash)); err != nil {
+			return nil, err
+		}
+		if number == rpc.PendingBlockNumber {
+			return nil, errors.New("pending block is not stored")
+		}
+		header, err = api.b.HeaderByNumber(ctx, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %s not found", numberOrHash)
+	}
+	return header, nil
+}
+
+// GetHeaderRlp retrieves the RLP encoding of the header of the block identified
+// by the given number, tag or hash, as read from the database. Contrary to
+// GetBlockRlp, the block body is never loaded.
+func (api *PublicDebugAPI) GetHeaderRlp(ctx context.Context, numberOrHash string) (hexutil.Bytes, error) {
+	header, err := api.storedHeader(ctx, numberOrHash)
+	if err != nil {
+		return nil, err
+	}
+	encoded := rawdb.ReadHeaderRLP(api.b.ChainDb(), header.Hash(), header.Number.Uint64())
+	if len(encoded) == 0 {
+		return nil, fmt.Errorf("header of block %s not found", numberOrHash)
+	}
+	return hexutil.Bytes(encoded), nil
+}
+
+// GetRawReceipts retrieves the consensus RLP encodings of the receipts of the
+// block identified by the given number, tag or hash, as read from the database.
+// The receipt root of the block is derived from exactly these encodings.
+func (api *PublicDebugAPI) GetRawReceipts(ctx context.Context, numberOrHash string) ([]hexutil.Bytes, error) {
+	header, err := api.storedHeader(ctx, numberOrHash)
+	if err != nil {
+		return nil, err
+	}
+	receipts := rawdb.ReadRawReceipts(api.b.ChainDb(), header.Hash(), header.Number.Uint64())
+	if receipts == nil {
+		return nil, fmt.Errorf("receipts of block %s not found", numberOrHash)
+	}
+	encoded := make([]hexutil.Bytes, len(receipts))
+	for i, receipt := range receipts {
+		if encoded[i], err = rlp.EncodeToBytes(receipt); err != nil {
+			return nil, err
+		}
+	}
+	return encoded, nil
+}
+
 // TestSignCliqueBlock fetches the given block number, and attempts to sign it as a clique header with the
 // given address, returning the address of the recovered signature
 //
@@ -1665,13 +2243,14 @@ func (api *PublicDebugAPI) SeedHash(ctx context.Context, number uint64) (string,
 // PrivateDebugAPI is the collection of Ccmchain APIs exposed over the private
 // debugging endpoint.
 type PrivateDebugAPI struct {
-	b Backend
+	b          Backend
+	compaction *compactionTracker
 }
 
 // NewPrivateDebugAPI creates a new API definition for the private debug mccmods
 // of the Ccmchain service.
 func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
-	return &PrivateDebugAPI{b: b}
+	return &PrivateDebugAPI{b: b, compaction: new(compactionTracker)}
 }
 
 // ChaindbProperty returns leveldb properties of the key-value database.
@@ -1686,17 +2265,183 @@ func (api *PrivateDebugAPI) ChaindbProperty(property string) (string, error) {
 
 // ChaindbCompact flattens the entire key-value database into a single level,
 // removing all unused slots and merging all keys.
+//
+// Only a single compaction may run at a time, its progress can be followed via
+// ChaindbCompactStatus.
 func (api *PrivateDebugAPI) ChaindbCompact() error {
+	if err := api.compaction.start(255); err != nil {
+		return err
+	}
 	for b := byte(0); b < 255; b++ {
 		log.Info("Compacting chain database", "range", fmt.Sprintf("0x%0.2X-0x%0.2X", b, b+1))
 		if err := api.b.ChainDb().Compact([]byte{b}, []byte{b + 1}); err != nil {
 			log.Error("Database compaction failed", "err", err)
+			api.compaction.finish(err)
 			return err
 		}
+		api.compaction.progress()
 	}
+	api.compaction.finish(nil)
 	return nil
 }
 
+// ChaindbCompactStatus reports whether a database compaction is running, how
+// far it progressed and roughly how long it is expected to take to complete.
+func (api *PrivateDebugAPI) ChaindbCompactStatus() *CompactionStatus {
+	return api.compaction.status()
+}
+
+// maxChaindbIterateKeys is the maximum number of entries a single chaindbIterate
+// call is allowed to return.
+const maxChaindbIterateKeys = 1024
+
+// ChaindbEntry is a single key (and optionally value) of the key-value database.
+type ChaindbEntry struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value,omitempty"`
+}
+
+// ChaindbIterate returns at most maxKeys entries of the key-value database whose
+// keys start with the given prefix, in binary-alphabetical order. Values are
+// only included if requested.
+func (api *PrivateDebugAPI) ChaindbIterate(prefix hexutil.Bytes, maxKeys int, values *bool) ([]ChaindbEntry, error) {
+	if maxKeys <= 0 || maxKeys > maxChaindbIterateKeys {
+		return nil, fmt.Errorf("invalid key limit %d, must be between 1 and %d", maxKeys, maxChaindbIterateKeys)
+	}
+	it := api.b.ChainDb().NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	entries := []ChaindbEntry{}
+	for len(entries) < maxKeys && it.Next() {
+		entry := ChaindbEntry{Key: common.CopyBytes(it.Key())}
+		if values != nil && *values {
+			entry.Value = common.CopyBytes(it.Value())
+		}
+		entries = append(entries, entry)
+	}
+	return entries, it.Error()
+}
+
+// CallBundle executes the given calls one after the other on top of the state of
+// the requested block, each of them seeing the effects of the previous ones, and
+// returns the individual results. The accounts in overrides are replaced in the
+// state before the first call is executed.
+func (api *PrivateDebugAPI) CallBundle(ctx context.Context, args []CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) ([]*BundleResult, error) {
+	ctx, done := api.b.Requests().Track(ctx, "debug_callBundle")
+	defer done()
+
+	msgs := make([]core.Message, len(args))
+	for i := range args {
+		if err := CheckCallDataSize(api.b, args[i]); err != nil {
+			return nil, err
+		}
+		msgs[i] = args[i].ToMessage(api.b, api.b.RPCGasCap())
+	}
+	var diff StateOverride
+	if overrides != nil {
+		diff = *overrides
+	}
+	return api.b.CallBundle(ctx, msgs, blockNr, diff)
+}
+
+// ForkEffect executes the given call on top of the state of the requested block
+// under both the current chain rules and the rules of the next scheduled fork,
+// reporting whether the fork changes its success, gas used or return data.
+func (api *PrivateDebugAPI) ForkEffect(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (*ForkEffect, error) {
+	ctx, done := api.b.Requests().Track(ctx, "debug_forkEffect")
+	defer done()
+
+	if err := CheckCallDataSize(api.b, args); err != nil {
+		return nil, err
+	}
+	return api.b.ForkEffect(ctx, args.ToMessage(api.b, api.b.RPCGasCap()), blockNr)
+}
+
+// DecodeTransaction decodes the input of the given transaction, included or still
+// pooled, against the contract ABI given in JSON form. If the input doesn't match
+// any method of the ABI, the reason is reported in the result. It backs the
+// debug.decodeTransaction console helper and is kept out of the public namespace,
+// as it parses ABIs supplied by the caller.
+func (api *PrivateDebugAPI) DecodeTransaction(ctx context.Context, hash common.Hash, abiJSON string) (*DecodedCall, error) {
+	contract, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %v", err)
+	}
+	tx, _, _, _, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		if tx = api.b.GetPoolTransaction(hash); tx == nil {
+			return nil, fmt.Errorf("transaction %#x not found", hash)
+		}
+	}
+	return DecodeCallData(contract, tx.Data()), nil
+}
+
+// RunningRequests returns the heavy RPC executions (calls, gas estimations and
+// traces) currently in progress, which may be aborted via CancelRequest.
+func (api *PrivateDebugAPI) RunningRequests() []RunningRequest {
+	return api.b.Requests().Running()
+}
+
+// CancelRequest aborts the running RPC execution with the given ID, reporting
+// whether it was still in progress.
+func (api *PrivateDebugAPI) CancelRequest(id hexutil.Uint64) bool {
+	return api.b.Requests().Cancel(uint64(id))
+}
+
+// flattenTxPoolContent merges the pending and queued transactions of the pool into
+// a single list ordered by sender address and nonce, suitable for re-importing.
+func flattenTxPoolContent(pending, queued map[common.Address]types.Transactions) types.Transactions {
+	senders := make([]common.Address, 0, len(pending)+len(queued))
+	for addr := range pending {
+		senders = append(senders, addr)
+	}
+	for addr := range queued {
+		if _, ok := pending[addr]; !ok {
+			senders = append(senders, addr)
+		}
+	}
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+
+	var txs types.Transactions
+	for _, addr := range senders {
+		txs = append(txs, pending[addr]...)
+		txs = append(txs, queued[addr]...)
+	}
+	return txs
+}
+
+// ExportTxPool returns the RLP encoded pending and queued transactions of the
+// pool, which can be fed back via ImportTxPool to set up a known pool state.
+func (api *PrivateDebugAPI) ExportTxPool() (hexutil.Bytes, error) {
+	return rlp.EncodeToBytes(flattenTxPoolContent(api.b.TxPoolContent()))
+}
+
+// ImportTxPool adds the transactions of a previous ExportTxPool call to the pool
+// as local transactions. It returns the number of transactions accepted and the
+// reason of rejection of every other one, keyed by transaction hash.
+func (api *PrivateDebugAPI) ImportTxPool(ctx context.Context, data hexutil.Bytes) (map[string]interface{}, error) {
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(data, &txs); err != nil {
+		return nil, err
+	}
+	errs := api.b.ImportTxPool(ctx, txs)
+	imported, failures := 0, make(map[common.Hash]string)
+	for i, err := range errs {
+		if err != nil {
+			failures[txs[i].Hash()] = err.Error()
+			continue
+		}
+		imported++
+	}
+	return map[string]interface{}{
+		"imported": hexutil.Uint(imported),
+		"errors":   failures,
+	}, nil
+}
+
 // SetHead rewinds the head of the blockchain to a previous block.
 func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) {
 	api.b.SetHead(uint64(number))
@@ -0,0 +1,1487 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ccmchain/go-ccmchain/accounts"
+	"github.com/ccmchain/go-ccmchain/accounts/abi"
+	"github.com/ccmchain/go-ccmchain/accounts/keystore"
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/consensus"
+	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/rawdb"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/core/vm"
+	"github.com/ccmchain/go-ccmchain/crypto"
+	"github.com/ccmchain/go-ccmchain/ccmdb"
+	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/params"
+	"github.com/ccmchain/go-ccmchain/rlp"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// Tests that signatures are recovered regardless of whether their V value uses
+// the yellow paper (27/28) or the raw recovery id (0/1) convention.
+func TestEcRecoverSignatureConventions(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	api := new(PrivateAccountAPI)
+	for _, msg := range []string{"", "hello", "Some data to sign, that is longer than 32 bytes in total"} {
+		raw, err := crypto.Sign(accounts.TextHash([]byte(msg)), key)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		legacy := common.CopyBytes(raw)
+		legacy[64] += 27
+
+		for _, sig := range [][]byte{raw, legacy} {
+			original := common.CopyBytes(sig)
+			have, err := api.EcRecover(context.Background(), []byte(msg), sig)
+			if err != nil {
+				t.Fatalf("message %q, v %d: failed to recover: %v", msg, sig[64], err)
+			}
+			if have != addr {
+				t.Errorf("message %q, v %d: address mismatch: have %x, want %x", msg, sig[64], have, addr)
+			}
+			if !bytes.Equal(sig, original) {
+				t.Errorf("message %q: signature modified during recovery", msg)
+			}
+		}
+	}
+}
+
+// Tests that malformed signatures are rejected.
+func TestEcRecoverMalformedSignatures(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sig, _ := crypto.Sign(accounts.TextHash([]byte("hello")), key)
+
+	api := new(PrivateAccountAPI)
+	tests := map[string][]byte{
+		"short":   sig[:64],
+		"long":    append(common.CopyBytes(sig), 0),
+		"bad v":   append(common.CopyBytes(sig[:64]), 2),
+		"zero r":  append(make([]byte, 32), sig[32:]...),
+		"max s":   append(append(common.CopyBytes(sig[:32]), bytes.Repeat([]byte{0xff}, 32)...), sig[64]),
+		"empty":   nil,
+		"v of 29": append(common.CopyBytes(sig[:64]), 29),
+	}
+	for name, sig := range tests {
+		if _, err := api.EcRecover(context.Background(), []byte("hello"), sig); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// Tests that transaction inputs are decoded against an ABI, with unknown methods
+// and undecodable arguments reported in the result instead of failing.
+func TestDecodeCallData(t *testing.T) {
+	const definition = `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}]`
+	contract, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := contract.Pack("transfer", common.Address{0xaa}, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := DecodeCallData(contract, input)
+	if decoded.Error != "" {
+		t.Fatalf("failed to decode call: %v", decoded.Error)
+	}
+	if decoded.Method != "transfer" || decoded.Signature != "transfer(address,uint256)" {
+		t.Errorf("method mismatch: have %s %s", decoded.Method, decoded.Signature)
+	}
+	if decoded.Call != "transfer(0xaa00...0000, 1000)" {
+		t.Errorf("formatted call mismatch: have %s", decoded.Call)
+	}
+	if decoded.Args["to"] != (common.Address{0xaa}) || decoded.Args["value"] != "0x3e8" {
+		t.Errorf("arguments mismatch: have %v", decoded.Args)
+	}
+	// Unknown selectors should still render the raw call
+	unknown := DecodeCallData(contract, []byte{0xde, 0xad, 0xbe, 0xef})
+	if unknown.Error == "" || unknown.Method != "" || unknown.Call != "0xdeadbeef()" {
+		t.Errorf("unknown selector mismatch: have %+v", unknown)
+	}
+	// Missing and truncated inputs should be reported
+	for _, data := range [][]byte{nil, input[:20]} {
+		if decoded := DecodeCallData(contract, data); decoded.Error == "" {
+			t.Errorf("decoded invalid input %x: %+v", data, decoded)
+		}
+	}
+}
+
+// Tests that the fork schedule is ordered by activation and tracks the head.
+func TestChainConfigFull(t *testing.T) {
+	config := &params.ChainConfig{
+		HomesteadBlock:      big.NewInt(0),
+		EIP150Block:         big.NewInt(10),
+		EIP155Block:         big.NewInt(10),
+		EIP158Block:         big.NewInt(10),
+		ByzantiumBlock:      big.NewInt(20),
+		ConstantinopleBlock: big.NewInt(30),
+	}
+	tests := []struct {
+		head    uint64
+		current string
+		active  int
+	}{
+		{0, "Homestead", 1},
+		{19, "EIP158", 4},
+		{20, "Byzantium", 5},
+		{100, "Petersburg", 7},
+	}
+	for _, tt := range tests {
+		full := NewChainConfigFull(config, tt.head)
+		if full.Current != tt.current {
+			t.Errorf("head %d: current fork mismatch: have %s, want %s", tt.head, full.Current, tt.current)
+		}
+		var names []string
+		active := 0
+		for _, fork := range full.Forks {
+			names = append(names, fork.Name)
+			if fork.Active {
+				active++
+			}
+		}
+		if want := "Homestead,EIP150,EIP155,EIP158,Byzantium,Constantinople,Petersburg"; strings.Join(names, ",") != want {
+			t.Errorf("head %d: schedule mismatch: have %v, want %s", tt.head, names, want)
+		}
+		if active != tt.active {
+			t.Errorf("head %d: active fork count mismatch: have %d, want %d", tt.head, active, tt.active)
+		}
+	}
+	if full := NewChainConfigFull(&params.ChainConfig{}, 100); full.Current != "Frontier" || len(full.Forks) != 0 {
+		t.Errorf("unforked chain mismatch: have %s with %d forks", full.Current, len(full.Forks))
+	}
+}
+
+func TestPendingTxFilter(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.NewEIP155Signer(big.NewInt(1))
+
+	var (
+		contract = common.HexToAddress("0xc0")
+		plain    = common.HexToAddress("0xee")
+	)
+	sign := func(tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	var (
+		call     = sign(types.NewTransaction(0, contract, big.NewInt(0), 50000, big.NewInt(10), []byte{1}))
+		transfer = sign(types.NewTransaction(1, plain, big.NewInt(1), 21000, big.NewInt(1), nil))
+		create   = sign(types.NewContractCreation(2, big.NewInt(0), 100000, big.NewInt(5), []byte{0}))
+	)
+	hasCode := func(addr common.Address) bool { return addr == contract }
+
+	tests := []struct {
+		filter PendingTxFilter
+		want   []bool // call, transfer, create
+	}{
+		{PendingTxFilter{}, []bool{true, true, true}},
+		{PendingTxFilter{To: &contract}, []bool{true, false, false}},
+		{PendingTxFilter{From: &sender}, []bool{true, true, true}},
+		{PendingTxFilter{From: &plain}, []bool{false, false, false}},
+		{PendingTxFilter{MinGasPrice: (*hexutil.Big)(big.NewInt(5))}, []bool{true, false, true}},
+		{PendingTxFilter{Contract: true}, []bool{true, false, true}},
+		{PendingTxFilter{To: &plain, Contract: true}, []bool{false, false, false}},
+	}
+	for i, tt := range tests {
+		for j, tx := range []*types.Transaction{call, transfer, create} {
+			if have := tt.filter.match(tx, signer, hasCode); have != tt.want[j] {
+				t.Errorf("test %d, tx %d: match mismatch: have %v, want %v", i, j, have, tt.want[j])
+			}
+		}
+	}
+}
+
+func TestCompactionTracker(t *testing.T) {
+	tracker := new(compactionTracker)
+	if status := tracker.status(); status.Running || status.Elapsed != "" {
+		t.Fatalf("idle tracker reports progress: %+v", status)
+	}
+	if err := tracker.start(4); err != nil {
+		t.Fatalf("failed to start compaction: %v", err)
+	}
+	if err := tracker.start(4); err != errCompactionRunning {
+		t.Fatalf("concurrent compaction error mismatch: have %v, want %v", err, errCompactionRunning)
+	}
+	tracker.progress()
+	if status := tracker.status(); !status.Running || status.Completed != 1 || status.Total != 4 || status.Remaining == "" {
+		t.Fatalf("running status mismatch: %+v", status)
+	}
+	tracker.finish(errors.New("disk full"))
+	if status := tracker.status(); status.Running || status.Remaining != "" || status.Error != "disk full" {
+		t.Fatalf("finished status mismatch: %+v", status)
+	}
+	if err := tracker.start(4); err != nil {
+		t.Fatalf("failed to restart compaction: %v", err)
+	}
+	if status := tracker.status(); status.Error != "" || status.Completed != 0 {
+		t.Fatalf("restarted status mismatch: %+v", status)
+	}
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	registry := NewRequestRegistry(map[string]time.Duration{
+		RequestClassTrace:         time.Minute,
+		"debug_replayTransaction": 0,
+	})
+	tests := []struct {
+		method string
+		want   time.Duration
+	}{
+		{"ccm_call", DefaultRequestTimeouts[RequestClassCall]},
+		{"debug_callBundle", DefaultRequestTimeouts[RequestClassCall]},
+		{"ccm_estimateGas", 0},
+		{"debug_traceTransaction", time.Minute},
+		{"debug_replayTransaction", 0},
+	}
+	for _, tt := range tests {
+		if have := registry.Timeout(tt.method); have != tt.want {
+			t.Errorf("%s: timeout mismatch: have %v, want %v", tt.method, have, tt.want)
+		}
+	}
+	// Tracked requests must be aborted once their timeout expires
+	registry = NewRequestRegistry(map[string]time.Duration{"ccm_call": time.Millisecond})
+	ctx, done := registry.Track(context.Background(), "ccm_call")
+	defer done()
+
+	select {
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			t.Fatalf("request aborted with %v", ctx.Err())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("request not aborted after its timeout")
+	}
+	ctx, done = registry.Track(context.Background(), "ccm_estimateGas")
+	defer done()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatalf("unbounded request has a deadline")
+	}
+}
+
+func TestEstimateDeployGasInvalidArgs(t *testing.T) {
+	contract, err := abi.JSON(strings.NewReader(`[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := [][]interface{}{
+		nil,
+		{"not a number"},
+		{big.NewInt(1), big.NewInt(2)},
+	}
+	for i, args := range tests {
+		// Packing fails before the backend is touched, so none is needed
+		_, err := EstimateDeployGas(context.Background(), nil, common.Address{}, []byte{0x60, 0x80}, args, contract)
+		if _, ok := err.(*ConstructorArgsError); !ok {
+			t.Errorf("test %d: error mismatch: have %v, want constructor argument error", i, err)
+		}
+	}
+}
+
+// estimateBackend is a chain stub executing calls on top of a fixed state.
+type estimateBackend struct {
+	Backend
+	statedb *state.StateDB
+	header  *types.Header
+}
+
+func (b *estimateBackend) RPCCallDataCap() uint64 { return 0 }
+func (b *estimateBackend) RPCGasCap() *big.Int    { return nil }
+
+func (b *estimateBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.header, nil
+}
+
+func (b *estimateBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	return b.statedb.Copy(), b.header, nil
+}
+
+func (b *estimateBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	state.SetBalance(msg.From(), new(big.Int).Lsh(common.Big1, 128))
+	context := core.NewEVMContext(msg, header, bundleChain{}, nil)
+	return vm.NewEVM(context, state, params.TestChainConfig, vm.Config{}), func() error { return nil }, nil
+}
+
+// Tests that deployment gas is estimated against the backend once the constructor
+// arguments are packed, and that failed estimations aren't mistaken for invalid
+// arguments.
+func TestEstimateDeployGas(t *testing.T) {
+	contract, err := abi.JSON(strings.NewReader(`[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &estimateBackend{
+		statedb: newBundleState(t, nil),
+		header:  &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: 1000000},
+	}
+	// Deploy a contract without runtime code: PUSH1 0, PUSH1 0, RETURN
+	gas, err := EstimateDeployGas(context.Background(), backend, common.Address{}, []byte{0x60, 0x00, 0x60, 0x00, 0xf3}, []interface{}{big.NewInt(1)}, contract)
+	if err != nil {
+		t.Fatalf("failed to estimate deployment gas: %v", err)
+	}
+	if uint64(gas) < params.TxGasContractCreation || uint64(gas) > params.TxGasContractCreation+10000 {
+		t.Errorf("deployment gas out of range: have %d, want just above %d", gas, params.TxGasContractCreation)
+	}
+	// Deploy a contract whose constructor always reverts: PUSH1 0, PUSH1 0, REVERT
+	_, err = EstimateDeployGas(context.Background(), backend, common.Address{}, []byte{0x60, 0x00, 0x60, 0x00, 0xfd}, []interface{}{big.NewInt(1)}, contract)
+	if err == nil {
+		t.Fatalf("reverting deployment estimated")
+	}
+	if _, ok := err.(*ConstructorArgsError); ok {
+		t.Errorf("failed estimation reported as invalid arguments: %v", err)
+	}
+}
+
+// accountBackend is a Backend only serving an account manager.
+type accountBackend struct {
+	Backend
+	am *accounts.Manager
+}
+
+func (b *accountBackend) AccountManager() *accounts.Manager { return b.am }
+
+// Tests that keystore directories can't be registered twice, even if they don't
+// hold any accounts.
+func TestAddKeystoreDuplicate(t *testing.T) {
+	primary, err := ioutil.TempDir("", "ccmapi-keystore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(primary)
+	extra, err := ioutil.TempDir("", "ccmapi-keystore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(extra)
+
+	am := accounts.NewManager(&accounts.Config{}, keystore.NewKeyStore(primary, keystore.LightScryptN, keystore.LightScryptP))
+	defer am.Close()
+	api := NewPrivateAccountAPI(&accountBackend{am: am}, new(AddrLocker))
+
+	if _, err := api.AddKeystore(primary); err == nil {
+		t.Errorf("primary keystore registered again")
+	}
+	if n, err := api.AddKeystore(extra); err != nil || n != 0 {
+		t.Fatalf("failed to register empty keystore: have (%d, %v), want (0, nil)", n, err)
+	}
+	if _, err := api.AddKeystore(extra + string(os.PathSeparator)); err == nil {
+		t.Errorf("empty keystore registered again")
+	}
+	if n := len(am.Backends(keystore.KeyStoreType)); n != 2 {
+		t.Errorf("keystore count mismatch: have %d, want 2", n)
+	}
+}
+
+func TestResolveConfirmedNumber(t *testing.T) {
+	engine := ccmash.NewFaker()
+
+	tests := []struct {
+		head   uint64
+		number rpc.BlockNumber
+		want   rpc.BlockNumber
+	}{
+		{10, rpc.SafeBlockNumber, 8},
+		{10, rpc.FinalizedBlockNumber, 5},
+		{5, rpc.FinalizedBlockNumber, 0},
+		{3, rpc.FinalizedBlockNumber, rpc.EarliestBlockNumber},
+		{1, rpc.SafeBlockNumber, rpc.EarliestBlockNumber},
+		{10, rpc.LatestBlockNumber, rpc.LatestBlockNumber},
+		{10, rpc.PendingBlockNumber, rpc.PendingBlockNumber},
+		{10, 7, 7},
+	}
+	for i, tt := range tests {
+		head := &types.Header{Number: new(big.Int).SetUint64(tt.head)}
+		if have := ResolveConfirmedNumber(engine, nil, head, tt.number, 2, 5); have != tt.want {
+			t.Errorf("test %d: resolved number mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
+
+// txFilterBackend is a pool stub serving a fixed state, counting how often it
+// is resolved.
+type txFilterBackend struct {
+	Backend
+	txs     event.Feed
+	statedb *state.StateDB
+
+	lock   sync.Mutex
+	states int // Number of times the latest state was resolved
+}
+
+func (b *txFilterBackend) ChainConfig() *params.ChainConfig { return params.TestChainConfig }
+
+func (b *txFilterBackend) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+}
+
+func (b *txFilterBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txs.Subscribe(ch)
+}
+
+func (b *txFilterBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.states++
+	return b.statedb, &types.Header{Number: big.NewInt(1)}, nil
+}
+
+// Tests that filtered pending transaction subscriptions resolve the state once
+// per pool event and never block the pool feed, even if not read from.
+func TestFilteredTxsSubscription(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(big.NewInt(1))
+
+	var (
+		contract = common.HexToAddress("0xc0")
+		plain    = common.HexToAddress("0xee")
+	)
+	statedb := newBundleState(t, nil)
+	statedb.SetCode(contract, []byte{0x00})
+
+	backend := &txFilterBackend{statedb: statedb}
+	ch := make(chan core.NewTxsEvent)
+	sub := NewFilteredTxsSubscription(backend, PendingTxFilter{Contract: true}, ch)
+	defer sub.Unsubscribe()
+
+	var txs []*types.Transaction
+	for i, to := range []common.Address{contract, plain, contract, plain} {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), to, big.NewInt(0), 50000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+	// Send a lot more events than the subscription buffers without reading any
+	// of them, the feed must not block on the filtered subscriber
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			backend.txs.Send(core.NewTxsEvent{Txs: txs})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pool feed blocked by filtered subscription")
+	}
+	// Only the contract calls should be delivered, with one state lookup per event
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-ch:
+			if len(ev.Txs) != 2 || ev.Txs[0] != txs[0] || ev.Txs[1] != txs[2] {
+				t.Fatalf("event %d: filtered transactions mismatch: have %v, want contract calls", i, ev.Txs)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d: timeout waiting for filtered transactions", i)
+		}
+	}
+	// The filter might already be working on the next event
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+	if backend.states < 2 || backend.states > 3 {
+		t.Errorf("state lookups mismatch: have %d, want 2 or 3", backend.states)
+	}
+}
+
+// inclusionBackend is a chain stub whose canonical headers and transaction
+// lookup can be rewritten to simulate reorgs.
+type inclusionBackend struct {
+	Backend
+	heads event.Feed
+	sides event.Feed
+
+	lock      sync.Mutex
+	canonical map[uint64]*types.Header
+	txBlock   *types.Header // Block including the watched transaction, if any
+}
+
+func (b *inclusionBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.heads.Subscribe(ch)
+}
+
+func (b *inclusionBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return b.sides.Subscribe(ch)
+}
+
+func (b *inclusionBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.canonical[uint64(number)], nil
+}
+
+func (b *inclusionBackend) GetTransaction(ctx context.Context, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.txBlock == nil {
+		return nil, common.Hash{}, 0, 0, nil
+	}
+	return new(types.Transaction), b.txBlock.Hash(), b.txBlock.Number.Uint64(), 0, nil
+}
+
+// setChain replaces the canonical chain and the block including the transaction,
+// announcing the new head.
+func (b *inclusionBackend) setChain(txBlock *types.Header, headers ...*types.Header) {
+	b.lock.Lock()
+	b.canonical = make(map[uint64]*types.Header)
+	for _, header := range headers {
+		b.canonical[header.Number.Uint64()] = header
+	}
+	b.txBlock = txBlock
+	b.lock.Unlock()
+
+	head := headers[len(headers)-1]
+	b.heads.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(head)})
+}
+
+// Tests that a watched transaction is reported as included, removed when its
+// block is reorged out and included again once it makes it into the new chain.
+func TestTxInclusionSubscription(t *testing.T) {
+	var (
+		txHash   = common.HexToHash("0x01")
+		genesis  = &types.Header{Number: big.NewInt(0)}
+		included = &types.Header{Number: big.NewInt(1), Extra: []byte("a")}
+		reorged  = &types.Header{Number: big.NewInt(1), Extra: []byte("b")}
+		readded  = &types.Header{Number: big.NewInt(2), Extra: []byte("c")}
+	)
+	backend := &inclusionBackend{
+		canonical: map[uint64]*types.Header{0: genesis, 1: included},
+		txBlock:   included,
+	}
+	events := make(chan TxInclusionEvent)
+	sub := NewTxInclusionSubscription(backend, txHash, events)
+	defer sub.Unsubscribe()
+
+	expect := func(header *types.Header, removed bool) {
+		t.Helper()
+		select {
+		case ev := <-events:
+			want := TxInclusionEvent{TxHash: txHash, BlockHash: header.Hash(), BlockNumber: header.Number.Uint64(), Removed: removed}
+			if ev != want {
+				t.Fatalf("event mismatch: have %+v, want %+v", ev, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no event for block %x (removed %v)", header.Hash(), removed)
+		}
+	}
+	expect(included, false)
+
+	backend.setChain(nil, genesis, reorged)
+	expect(included, true)
+
+	backend.setChain(readded, genesis, reorged, readded)
+	expect(readded, false)
+}
+
+// Tests that blocks marshalled with full transactions carry every field of each
+// transaction, resolved at its position in the block.
+func TestRPCMarshalBlockFullTx(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		to     = common.HexToAddress("0x0102")
+		signer = types.NewEIP155Signer(big.NewInt(1))
+	)
+	transfer, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(100), 21000, big.NewInt(2), []byte{0xca, 0xfe}), signer, key)
+	create, _ := types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(3), []byte{0x60, 0x00}), types.HomesteadSigner{}, key)
+
+	block := types.NewBlock(&types.Header{Number: big.NewInt(7)}, []*types.Transaction{transfer, create}, nil, nil)
+	fields, err := RPCMarshalBlock(block, true, true)
+	if err != nil {
+		t.Fatalf("failed to marshal block: %v", err)
+	}
+	txs := fields["transactions"].([]interface{})
+	if len(txs) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want 2", len(txs))
+	}
+	for i, tx := range block.Transactions() {
+		rpcTx := txs[i].(*RPCTransaction)
+		v, r, s := tx.RawSignatureValues()
+		want := &RPCTransaction{
+			BlockHash:        block.Hash(),
+			BlockNumber:      (*hexutil.Big)(block.Number()),
+			From:             from,
+			Gas:              hexutil.Uint64(tx.Gas()),
+			GasPrice:         (*hexutil.Big)(tx.GasPrice()),
+			Hash:             tx.Hash(),
+			Input:            tx.Data(),
+			Nonce:            hexutil.Uint64(tx.Nonce()),
+			To:               tx.To(),
+			TransactionIndex: hexutil.Uint(i),
+			Value:            (*hexutil.Big)(tx.Value()),
+			V:                (*hexutil.Big)(v),
+			R:                (*hexutil.Big)(r),
+			S:                (*hexutil.Big)(s),
+		}
+		have, _ := json.Marshal(rpcTx)
+		exp, _ := json.Marshal(want)
+		if !bytes.Equal(have, exp) {
+			t.Errorf("tx %d: mismatch:\nhave %s\nwant %s", i, have, exp)
+		}
+	}
+}
+
+// headerChain is a header reader serving a fixed canonical chain.
+type headerChain []*types.Header
+
+func (c headerChain) CurrentHeader() *types.Header { return c[len(c)-1] }
+
+func (c headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if number < uint64(len(c)) && c[number].Hash() == hash {
+		return c[number]
+	}
+	return nil
+}
+
+// Tests that gas usage statistics are calculated over the requested number of
+// recent headers, stopping at the genesis block.
+func TestChainGasUsageStats(t *testing.T) {
+	var chain headerChain
+	for i, used := range []uint64{0, 10, 20, 50, 80} {
+		header := &types.Header{Number: big.NewInt(int64(i)), GasLimit: 100, GasUsed: used}
+		if i > 0 {
+			header.ParentHash = chain[i-1].Hash()
+		}
+		chain = append(chain, header)
+	}
+	tests := []struct {
+		blocks int
+		have   int
+		ratio  float64
+		trend  float64
+	}{
+		{1, 1, 0.8, 0},
+		{4, 4, 0.4, 0.5},   // (0.8+0.5+0.2+0.1)/4, (0.8+0.5)/2-(0.2+0.1)/2
+		{10, 5, 0.32, 0.6}, // (0.8+0.5+0.2+0.1+0)/5, (0.8+0.5)/2-(0.1+0)/2
+	}
+	for _, tt := range tests {
+		stats, err := ChainGasUsageStats(chain, tt.blocks)
+		if err != nil {
+			t.Fatalf("%d blocks: failed to calculate stats: %v", tt.blocks, err)
+		}
+		if stats.Number != 4 || stats.GasLimit != 100 || stats.Blocks != tt.have {
+			t.Errorf("%d blocks: stats mismatch: have %+v", tt.blocks, stats)
+		}
+		if math.Abs(stats.Ratio-tt.ratio) > 1e-9 || math.Abs(stats.Trend-tt.trend) > 1e-9 {
+			t.Errorf("%d blocks: ratio/trend mismatch: have %v/%v, want %v/%v", tt.blocks, stats.Ratio, stats.Trend, tt.ratio, tt.trend)
+		}
+	}
+	for _, blocks := range []int{0, MaxGasUsageBlocks + 1} {
+		if _, err := ChainGasUsageStats(chain, blocks); err == nil {
+			t.Errorf("%d blocks: invalid window accepted", blocks)
+		}
+	}
+}
+
+// Tests that tracked requests are listed while running, and that aborting one
+// cancels its context and drops it from the registry.
+func TestRequestRegistry(t *testing.T) {
+	registry := NewRequestRegistry(nil)
+
+	ctx1, done1 := registry.Track(context.Background(), "debug_traceCall")
+	defer done1()
+	ctx2, done2 := registry.Track(context.Background(), "ccm_call")
+
+	running := registry.Running()
+	if len(running) != 2 {
+		t.Fatalf("running request count mismatch: have %d, want 2", len(running))
+	}
+	if running[0].ID != 1 || running[0].Method != "debug_traceCall" {
+		t.Errorf("first request mismatch: have %+v", running[0])
+	}
+	if running[1].ID != 2 || running[1].Method != "ccm_call" {
+		t.Errorf("second request mismatch: have %+v", running[1])
+	}
+	// Abort the first request and ensure only it is affected
+	if !registry.Cancel(1) {
+		t.Fatalf("failed to cancel running request")
+	}
+	if ctx1.Err() != context.Canceled {
+		t.Errorf("aborted request context error mismatch: have %v, want %v", ctx1.Err(), context.Canceled)
+	}
+	if registry.Cancel(1) {
+		t.Errorf("aborted request cancelled twice")
+	}
+	if ctx2.Err() != nil {
+		t.Errorf("unrelated request aborted: %v", ctx2.Err())
+	}
+	if running := registry.Running(); len(running) != 1 || running[0].ID != 2 {
+		t.Errorf("running requests mismatch after abort: have %+v", running)
+	}
+	// Finish the second request and ensure it's released
+	done2()
+	if ctx2.Err() != context.Canceled {
+		t.Errorf("finished request context error mismatch: have %v, want %v", ctx2.Err(), context.Canceled)
+	}
+	if running := registry.Running(); len(running) != 0 {
+		t.Errorf("running requests mismatch after finish: have %+v", running)
+	}
+	if registry.Cancel(2) {
+		t.Errorf("finished request cancelled")
+	}
+}
+
+// Tests that the fee histogram covers the full price range of the transactions,
+// including the degenerate single price ranges and the inclusive upper bound of
+// the top bucket.
+func TestNewFeeHistogram(t *testing.T) {
+	type bucket struct {
+		min, max int64
+		count    int
+	}
+	tests := []struct {
+		prices  []int64
+		buckets int
+		want    []bucket
+	}{
+		// No transactions or buckets yield an empty histogram
+		{nil, 10, nil},
+		{[]int64{1, 2}, 0, nil},
+		// A single transaction or equal prices collapse into a single bucket
+		{[]int64{7}, 4, []bucket{{7, 7, 1}}},
+		{[]int64{5, 5, 5}, 10, []bucket{{5, 5, 3}}},
+		// The maximum price is counted in the top bucket, even if it's narrower
+		{[]int64{10, 19, 20}, 2, []bucket{{10, 15, 1}, {16, 20, 2}}},
+		{[]int64{0, 9, 8}, 3, []bucket{{0, 3, 1}, {4, 7, 0}, {8, 9, 2}}},
+		// Buckets left empty by rounding up the width are dropped
+		{[]int64{0, 4}, 4, []bucket{{0, 1, 1}, {2, 3, 0}, {4, 4, 1}}},
+		// Narrow ranges cap the number of buckets
+		{[]int64{3, 4, 4}, 10, []bucket{{3, 3, 1}, {4, 4, 2}}},
+	}
+	for i, tt := range tests {
+		var txs types.Transactions
+		for nonce, price := range tt.prices {
+			txs = append(txs, types.NewTransaction(uint64(nonce), common.Address{}, new(big.Int), 21000, big.NewInt(price), nil))
+		}
+		histogram := NewFeeHistogram(txs, tt.buckets)
+		if len(histogram) != len(tt.want) {
+			t.Errorf("test %d: bucket count mismatch: have %d, want %d", i, len(histogram), len(tt.want))
+			continue
+		}
+		for j, want := range tt.want {
+			have := histogram[j]
+			if have.MinPrice.ToInt().Int64() != want.min || have.MaxPrice.ToInt().Int64() != want.max || have.Count != want.count {
+				t.Errorf("test %d, bucket %d: mismatch: have [%v, %v] x%d, want [%d, %d] x%d",
+					i, j, have.MinPrice.ToInt(), have.MaxPrice.ToInt(), have.Count, want.min, want.max, want.count)
+			}
+		}
+	}
+}
+
+// Tests that the state readers bound the number of concurrently open states,
+// blocking acquisitions above the limit until states are released.
+func TestStateReaders(t *testing.T) {
+	readers := NewStateReaders(3)
+
+	if _, err := readers.Acquire(context.Background(), 0); err == nil {
+		t.Fatalf("empty acquisition allowed")
+	}
+	if _, err := readers.Acquire(context.Background(), 4); err == nil {
+		t.Fatalf("acquisition above the limit allowed")
+	}
+	release, err := readers.Acquire(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("failed to acquire states: %v", err)
+	}
+	// Acquiring more than the remaining states must block until cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := readers.Acquire(ctx, 2); err != context.DeadlineExceeded {
+		t.Fatalf("acquisition over the limit error mismatch: have %v, want %v", err, context.DeadlineExceeded)
+	}
+	// Without a deadline, the wait is bounded by the acquisition timeout
+	if _, err := readers.Acquire(context.Background(), 2); err != errStatesBusy {
+		t.Fatalf("unbounded acquisition error mismatch: have %v, want %v", err, errStatesBusy)
+	}
+	// Releasing (even repeatedly) must free up only the acquired states
+	release()
+	release()
+
+	if _, err := readers.Acquire(context.Background(), 3); err != nil {
+		t.Fatalf("failed to acquire released states: %v", err)
+	}
+}
+
+// bundleChain is a chain context without ancestors to run bundles against.
+type bundleChain struct{ headerChain }
+
+func (bundleChain) Engine() consensus.Engine { return ccmash.NewFaker() }
+
+// newBundleState creates an empty state with the given accounts funded.
+func newBundleState(t *testing.T, funds map[common.Address]int64) *state.StateDB {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	for addr, balance := range funds {
+		statedb.SetBalance(addr, big.NewInt(balance))
+	}
+	return statedb
+}
+
+// Tests that later messages of a bundle see the value transferred and the gas
+// paid by earlier ones, instead of running with unlimited funds.
+func TestApplyBundleDependentMessages(t *testing.T) {
+	var (
+		alice  = common.HexToAddress("0xa1")
+		bob    = common.HexToAddress("0xb0b")
+		carol  = common.HexToAddress("0xca")
+		header = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: 10000000}
+	)
+	statedb := newBundleState(t, map[common.Address]int64{alice: 1000000})
+
+	msgs := []core.Message{
+		// Alice funds Bob, paying for the gas too
+		types.NewMessage(alice, &bob, 0, big.NewInt(1000), params.TxGas, big.NewInt(1), nil, false),
+		// Bob forwards the received funds to Carol
+		types.NewMessage(bob, &carol, 0, big.NewInt(1000), params.TxGas, big.NewInt(0), nil, false),
+		// Bob has nothing left to send
+		types.NewMessage(bob, &carol, 0, big.NewInt(1), params.TxGas, big.NewInt(0), nil, false),
+	}
+	results, err := ApplyBundle(context.Background(), params.TestChainConfig, bundleChain{}, vm.Config{}, msgs, statedb, header)
+	if err != nil {
+		t.Fatalf("failed to apply bundle: %v", err)
+	}
+	if results[0].Failed || results[1].Failed {
+		t.Fatalf("funded transfers failed: %+v, %+v", results[0], results[1])
+	}
+	if results[2].Error == "" {
+		t.Fatalf("unfunded transfer succeeded")
+	}
+	if have, want := statedb.GetBalance(alice).Int64(), int64(1000000-1000-int64(params.TxGas)); have != want {
+		t.Errorf("sender balance mismatch: have %d, want %d", have, want)
+	}
+	if have := statedb.GetBalance(bob).Int64(); have != 0 {
+		t.Errorf("forwarder balance mismatch: have %d, want 0", have)
+	}
+	if have := statedb.GetBalance(carol).Int64(); have != 1000 {
+		t.Errorf("recipient balance mismatch: have %d, want 1000", have)
+	}
+}
+
+// Tests that a reverting message in the middle of a bundle has its state changes
+// discarded, without affecting the messages around it.
+func TestApplyBundleRevert(t *testing.T) {
+	var (
+		alice  = common.HexToAddress("0xa1")
+		bob    = common.HexToAddress("0xb0b")
+		header = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: 10000000}
+
+		// The contract stores 1 in slot 0, then reverts:
+		// PUSH1 1, PUSH1 0, SSTORE, PUSH1 0, PUSH1 0, REVERT
+		reverter = common.HexToAddress("0xdead")
+		code     = []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x60, 0x00, 0x60, 0x00, 0xfd}
+	)
+	statedb := newBundleState(t, map[common.Address]int64{alice: 1000000})
+	statedb.SetCode(reverter, code)
+
+	msgs := []core.Message{
+		types.NewMessage(alice, &bob, 0, big.NewInt(1000), params.TxGas, big.NewInt(0), nil, false),
+		types.NewMessage(alice, &reverter, 0, big.NewInt(0), 100000, big.NewInt(0), nil, false),
+		types.NewMessage(bob, &alice, 0, big.NewInt(400), params.TxGas, big.NewInt(0), nil, false),
+	}
+	results, err := ApplyBundle(context.Background(), params.TestChainConfig, bundleChain{}, vm.Config{}, msgs, statedb, header)
+	if err != nil {
+		t.Fatalf("failed to apply bundle: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("result count mismatch: have %d, want 3", len(results))
+	}
+	if results[0].Failed || results[2].Failed {
+		t.Fatalf("transfers around the revert failed: %+v, %+v", results[0], results[2])
+	}
+	if !results[1].Failed {
+		t.Fatalf("reverting message succeeded")
+	}
+	if slot := statedb.GetState(reverter, common.Hash{}); slot != (common.Hash{}) {
+		t.Errorf("reverted storage write persisted: %x", slot)
+	}
+	if have := statedb.GetBalance(bob).Int64(); have != 600 {
+		t.Errorf("balance mismatch after revert: have %d, want 600", have)
+	}
+}
+
+// missingBlockBackend is a backend knowing no blocks at all.
+type missingBlockBackend struct {
+	Backend
+}
+
+func (b *missingBlockBackend) RPCCallDataCap() uint64 { return 0 }
+
+func (b *missingBlockBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return nil, nil
+}
+
+// Tests that gas estimations against an unknown block fail cleanly, whether or
+// not the gas allowance is given explicitly.
+func TestEstimateGasMissingBlock(t *testing.T) {
+	var (
+		to  = common.HexToAddress("0x10")
+		gas = hexutil.Uint64(100000)
+	)
+	for _, args := range []CallArgs{{To: &to}, {To: &to, Gas: &gas}} {
+		_, err := DoEstimateGas(context.Background(), new(missingBlockBackend), args, 100, nil)
+		if err == nil || err.Error() != "block 100 not found" {
+			t.Errorf("gas %v: error mismatch: have %v, want %q", args.Gas, err, "block 100 not found")
+		}
+	}
+}
+
+// txPoolBackend is a pool stub serving a fixed content and recording imports.
+type txPoolBackend struct {
+	Backend
+	pending, queued map[common.Address]types.Transactions
+	imported        types.Transactions
+}
+
+func (b *txPoolBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return b.pending, b.queued
+}
+
+func (b *txPoolBackend) ImportTxPool(ctx context.Context, txs types.Transactions) []error {
+	b.imported = txs
+
+	errs := make([]error, len(txs))
+	for i, tx := range txs {
+		if tx.Nonce() > 1 {
+			errs[i] = errors.New("nonce too high")
+		}
+	}
+	return errs
+}
+
+// Tests that an exported transaction pool is imported in sender and nonce order,
+// reporting the transactions rejected by the pool.
+func TestTxPoolExportImport(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+	)
+	tx := func(to common.Address, nonce uint64) *types.Transaction {
+		return types.NewTransaction(nonce, to, big.NewInt(1), params.TxGas, big.NewInt(1), nil)
+	}
+	backend := &txPoolBackend{
+		pending: map[common.Address]types.Transactions{bob: {tx(bob, 0), tx(bob, 1)}, alice: {tx(alice, 0)}},
+		queued:  map[common.Address]types.Transactions{bob: {tx(bob, 3)}},
+	}
+	api := NewPrivateDebugAPI(backend)
+
+	blob, err := api.ExportTxPool()
+	if err != nil {
+		t.Fatalf("failed to export pool: %v", err)
+	}
+	res, err := api.ImportTxPool(context.Background(), blob)
+	if err != nil {
+		t.Fatalf("failed to import pool: %v", err)
+	}
+	want := types.Transactions{backend.pending[alice][0], backend.pending[bob][0], backend.pending[bob][1], backend.queued[bob][0]}
+	if len(backend.imported) != len(want) {
+		t.Fatalf("imported transaction count mismatch: have %d, want %d", len(backend.imported), len(want))
+	}
+	for i, tx := range backend.imported {
+		if tx.Hash() != want[i].Hash() {
+			t.Errorf("transaction %d: hash mismatch: have %x, want %x", i, tx.Hash(), want[i].Hash())
+		}
+	}
+	if imported := res["imported"].(hexutil.Uint); imported != 3 {
+		t.Errorf("imported count mismatch: have %d, want %d", imported, 3)
+	}
+	failures := res["errors"].(map[common.Hash]string)
+	if len(failures) != 1 || failures[want[3].Hash()] != "nonce too high" {
+		t.Errorf("import failures mismatch: have %v", failures)
+	}
+}
+
+// Tests that malformed pool dumps are rejected without reaching the pool.
+func TestTxPoolImportMalformed(t *testing.T) {
+	backend := new(txPoolBackend)
+	api := NewPrivateDebugAPI(backend)
+
+	for i, blob := range []hexutil.Bytes{{0x01}, {0xc2, 0x01}, {0xc1, 0xc0}} {
+		if _, err := api.ImportTxPool(context.Background(), blob); err == nil {
+			t.Errorf("test %d: expected error for malformed input %x", i, blob)
+		}
+		if backend.imported != nil {
+			t.Errorf("test %d: malformed input reached the pool", i)
+		}
+	}
+}
+
+// noDropsBackend is a backend not reporting dropped transactions.
+type noDropsBackend struct {
+	Backend
+}
+
+func (b *noDropsBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) (event.Subscription, error) {
+	return nil, ErrDroppedTxsUnsupported
+}
+
+// Tests that subscribing to dropped transactions fails outright if the backend
+// does not report them, instead of returning a subscription that never fires.
+func TestDroppedTransactionsUnsupported(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("txpool", NewPublicTxPoolAPI(new(noDropsBackend))); err != nil {
+		t.Fatalf("failed to register txpool API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan core.DroppedTx)
+	if _, err := client.Subscribe(context.Background(), "txpool", ch, "droppedTransactions"); err == nil || err.Error() != ErrDroppedTxsUnsupported.Error() {
+		t.Fatalf("subscription error mismatch: have %v, want %v", err, ErrDroppedTxsUnsupported)
+	}
+}
+
+// txPoolStatusBackend is a pool stub with adjustable stats, counting how often
+// they are retrieved.
+type txPoolStatusBackend struct {
+	Backend
+	txs   event.Feed
+	drops event.Feed
+	heads event.Feed
+
+	lock    sync.Mutex
+	pending int
+	queued  int
+	calls   int
+}
+
+func (b *txPoolStatusBackend) Stats() (int, int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.calls++
+	return b.pending, b.queued
+}
+
+func (b *txPoolStatusBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txs.Subscribe(ch)
+}
+
+func (b *txPoolStatusBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) (event.Subscription, error) {
+	return b.drops.Subscribe(ch), nil
+}
+
+func (b *txPoolStatusBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.heads.Subscribe(ch)
+}
+
+// Tests that the pool status subscription is notified of transactions dropped
+// between blocks, without any new transactions or chain heads arriving.
+func TestTxPoolStatusUpdatesOnDrop(t *testing.T) {
+	backend := &txPoolStatusBackend{pending: 2}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("txpool", NewPublicTxPoolAPI(backend)); err != nil {
+		t.Fatalf("failed to register txpool API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	updates := make(chan map[string]hexutil.Uint)
+	sub, err := client.Subscribe(context.Background(), "txpool", updates, "statusUpdates")
+	if err != nil {
+		t.Fatalf("failed to subscribe to status updates: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// Wait for the drop subscription and the initial stats retrieval
+	for backend.drops.Send(core.DroppedTxsEvent{}) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for {
+		backend.lock.Lock()
+		calls := backend.calls
+		backend.lock.Unlock()
+		if calls > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Drop a transaction from the pool and only announce the drop
+	backend.lock.Lock()
+	backend.pending = 1
+	backend.lock.Unlock()
+	backend.drops.Send(core.DroppedTxsEvent{Txs: []core.DroppedTx{{Hash: common.HexToHash("0x01")}}})
+
+	select {
+	case update := <-updates:
+		if update["pending"] != 1 || update["queued"] != 0 {
+			t.Fatalf("status mismatch: have %v, want 1 pending and 0 queued", update)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no status update for dropped transaction")
+	}
+}
+
+// Tests that the latest final block is the confirmation depth behind the head,
+// and that no block is final before the chain reaches that depth.
+func TestFinalizedNumber(t *testing.T) {
+	tests := []struct {
+		head, depth uint64
+		number      uint64
+		final       bool
+	}{
+		{0, 0, 0, true},
+		{5, 0, 5, true},
+		{0, 3, 0, false},
+		{2, 3, 0, false},
+		{3, 3, 0, true},
+		{4, 3, 1, true},
+		{10, 3, 7, true},
+	}
+	for i, tt := range tests {
+		number, final := finalizedNumber(tt.head, tt.depth)
+		if number != tt.number || final != tt.final {
+			t.Errorf("test %d: finalized block mismatch: have %d (%v), want %d (%v)", i, number, final, tt.number, tt.final)
+		}
+	}
+}
+
+// finalityChain is a chain reader serving a fixed canonical chain.
+type finalityChain struct{ headerChain }
+
+func (c finalityChain) Config() *params.ChainConfig { return params.TestChainConfig }
+
+func (c finalityChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(c.headerChain)) {
+		return c.headerChain[number]
+	}
+	return nil
+}
+
+func (c finalityChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, header := range c.headerChain {
+		if header.Hash() == hash {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c finalityChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if header := c.GetHeader(hash, number); header != nil {
+		return types.NewBlockWithHeader(header)
+	}
+	return nil
+}
+
+// Tests that every new head is annotated with the block the confirmation depth
+// behind it, around the depth threshold.
+func TestFinalityHeadSubscription(t *testing.T) {
+	var chain finalityChain
+	for i := 0; i < 6; i++ {
+		chain.headerChain = append(chain.headerChain, &types.Header{Number: big.NewInt(int64(i)), Extra: []byte{byte(i)}})
+	}
+	var (
+		feed  event.Feed
+		heads = make(chan core.FinalityHeadEvent)
+	)
+	subscribe := func(ch chan<- core.ChainHeadEvent) event.Subscription { return feed.Subscribe(ch) }
+	sub := NewFinalityHeadSubscription(subscribe, ccmash.NewFaker(), chain, 3, heads)
+	defer sub.Unsubscribe()
+
+	// Wait for the subscription to be registered on the feed
+	for feed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(chain.headerChain[0])}) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i, header := range chain.headerChain {
+		if i > 0 {
+			feed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(header)})
+		}
+		select {
+		case ev := <-heads:
+			if ev.Header.Hash() != header.Hash() {
+				t.Fatalf("head %d: header mismatch", i)
+			}
+			switch {
+			case i < 3 && ev.Finalized != nil:
+				t.Errorf("head %d: block %d final before reaching the depth", i, ev.Finalized.Number)
+			case i >= 3 && ev.Finalized == nil:
+				t.Errorf("head %d: no final block", i)
+			case i >= 3 && ev.Finalized.Hash() != chain.headerChain[i-3].Hash():
+				t.Errorf("head %d: final block mismatch: have %d, want %d", i, ev.Finalized.Number, i-3)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("head %d: timeout waiting for notification", i)
+		}
+	}
+}
+
+// Tests that the next fork is the earliest one scheduled strictly after the given
+// block, activating every fork scheduled at that same block, without touching
+// the original config.
+func TestNextForkConfig(t *testing.T) {
+	tests := []struct {
+		config *params.ChainConfig
+		number int64
+		fork   int64 // -1 if no fork is pending
+		active []string
+	}{
+		// No forks scheduled at all, or all of them passed already
+		{&params.ChainConfig{}, 5, -1, nil},
+		{&params.ChainConfig{HomesteadBlock: big.NewInt(0), ByzantiumBlock: big.NewInt(5)}, 5, -1, nil},
+		// A single fork scheduled after the block
+		{&params.ChainConfig{HomesteadBlock: big.NewInt(0), EIP150Block: big.NewInt(10)}, 5, 10, []string{"eip150"}},
+		// Several forks scheduled at the same block are all activated
+		{&params.ChainConfig{ByzantiumBlock: big.NewInt(0), ConstantinopleBlock: big.NewInt(10), PetersburgBlock: big.NewInt(10)}, 5, 10, []string{"constantinople", "petersburg"}},
+		// Only the earliest of the pending forks is activated, regardless of order
+		{&params.ChainConfig{ByzantiumBlock: big.NewInt(20), ConstantinopleBlock: big.NewInt(8), PetersburgBlock: big.NewInt(30)}, 5, 8, []string{"constantinople"}},
+	}
+	for i, tt := range tests {
+		original := *tt.config
+		config, fork := NextForkConfig(tt.config, big.NewInt(tt.number))
+		if !reflect.DeepEqual(*tt.config, original) {
+			t.Errorf("test %d: original config modified", i)
+		}
+		if tt.fork < 0 {
+			if config != nil || fork != nil {
+				t.Errorf("test %d: unexpected pending fork at %v", i, fork)
+			}
+			continue
+		}
+		if config == nil || fork == nil || fork.Int64() != tt.fork {
+			t.Errorf("test %d: fork block mismatch: have %v, want %d", i, fork, tt.fork)
+			continue
+		}
+		forks := map[string]*big.Int{
+			"eip150":         config.EIP150Block,
+			"byzantium":      config.ByzantiumBlock,
+			"constantinople": config.ConstantinopleBlock,
+			"petersburg":     config.PetersburgBlock,
+		}
+		var active []string
+		for _, name := range []string{"eip150", "byzantium", "constantinople", "petersburg"} {
+			if block := forks[name]; block != nil && block.Int64() == tt.number {
+				active = append(active, name)
+			}
+		}
+		if !reflect.DeepEqual(active, tt.active) {
+			t.Errorf("test %d: activated forks mismatch: have %v, want %v", i, active, tt.active)
+		}
+	}
+}
+
+// forkBackend is a chain stub executing calls on top of a fixed state under the
+// rules of the given chain config.
+type forkBackend struct {
+	estimateBackend
+	config *params.ChainConfig
+}
+
+func (b *forkBackend) ChainConfig() *params.ChainConfig { return b.config }
+
+func (b *forkBackend) GetEVMWithConfig(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig) (*vm.EVM, func() error, error) {
+	context := core.NewEVMContext(msg, header, bundleChain{}, nil)
+	return vm.NewEVM(context, state, config, vm.Config{}), func() error { return nil }, nil
+}
+
+// Tests that executions are compared under the current and the next fork rules,
+// reporting a change only if the fork affects the message.
+func TestCompareForkRules(t *testing.T) {
+	var (
+		sender  = common.HexToAddress("0xa1")
+		shifter = common.HexToAddress("0x5f")
+		plain   = common.HexToAddress("0xee")
+		header  = &types.Header{Number: big.NewInt(5), Difficulty: big.NewInt(1), GasLimit: 1000000}
+	)
+	statedb := newBundleState(t, map[common.Address]int64{sender: 1000000})
+	// PUSH1 1, PUSH1 1, SHL is only valid from Constantinople on
+	statedb.SetCode(shifter, []byte{0x60, 0x01, 0x60, 0x01, 0x1b})
+
+	config := &params.ChainConfig{
+		ChainID:             big.NewInt(1),
+		HomesteadBlock:      big.NewInt(0),
+		EIP150Block:         big.NewInt(0),
+		EIP155Block:         big.NewInt(0),
+		EIP158Block:         big.NewInt(0),
+		ByzantiumBlock:      big.NewInt(0),
+		ConstantinopleBlock: big.NewInt(10),
+		PetersburgBlock:     big.NewInt(10),
+	}
+	backend := &forkBackend{estimateBackend: estimateBackend{statedb: statedb, header: header}, config: config}
+
+	// A message using an opcode introduced by the fork changes its outcome
+	effect, err := CompareForkRules(context.Background(), backend, types.NewMessage(sender, &shifter, 0, big.NewInt(0), 100000, big.NewInt(0), nil, false), statedb, header)
+	if err != nil {
+		t.Fatalf("failed to compare fork rules: %v", err)
+	}
+	if effect.ForkBlock.ToInt().Int64() != 10 {
+		t.Errorf("fork block mismatch: have %v, want 10", effect.ForkBlock)
+	}
+	if !effect.Current.Failed || effect.Next.Failed || !effect.Changed {
+		t.Errorf("fork effect mismatch: current failed %v, next failed %v, changed %v", effect.Current.Failed, effect.Next.Failed, effect.Changed)
+	}
+	// A plain transfer is unaffected
+	effect, err = CompareForkRules(context.Background(), backend, types.NewMessage(sender, &plain, 0, big.NewInt(1), params.TxGas, big.NewInt(0), nil, false), statedb, header)
+	if err != nil {
+		t.Fatalf("failed to compare fork rules: %v", err)
+	}
+	if effect.Changed {
+		t.Errorf("plain transfer reported as changed: %+v, %+v", effect.Current, effect.Next)
+	}
+	// The state is left untouched by either execution
+	if balance := statedb.GetBalance(plain); balance.Sign() != 0 {
+		t.Errorf("state modified by comparison: balance %v", balance)
+	}
+	// Without any pending fork there is nothing to compare
+	backend.config = params.TestChainConfig
+	if _, err := CompareForkRules(context.Background(), backend, types.NewMessage(sender, &plain, 0, big.NewInt(1), params.TxGas, big.NewInt(0), nil, false), statedb, header); err != errNoPendingFork {
+		t.Errorf("error mismatch without pending fork: have %v, want %v", err, errNoPendingFork)
+	}
+}
+
+// Tests that raw transactions are decoded along with their recovered sender for
+// both replay protected and unprotected signatures, and that malformed ones are
+// rejected.
+func TestDecodeRawTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	api := NewPublicTransactionPoolAPI(nil, nil)
+	for _, signer := range []types.Signer{types.HomesteadSigner{}, types.NewEIP155Signer(big.NewInt(5))} {
+		tx, _ := types.SignTx(types.NewTransaction(7, common.Address{0x01}, big.NewInt(9), params.TxGas, big.NewInt(1), []byte{0xca, 0xfe}), signer, key)
+		blob, _ := rlp.EncodeToBytes(tx)
+
+		res, err := api.DecodeRawTransaction(blob)
+		if err != nil {
+			t.Fatalf("%T: failed to decode transaction: %v", signer, err)
+		}
+		if res.From != sender || res.Hash != tx.Hash() || uint64(res.Nonce) != 7 || *res.To != (common.Address{0x01}) || res.BlockHash != (common.Hash{}) {
+			t.Errorf("%T: decoded transaction mismatch: have %+v", signer, res)
+		}
+	}
+	if _, err := api.DecodeRawTransaction([]byte{0xc0, 0x01}); err == nil {
+		t.Errorf("malformed encoding: expected error")
+	}
+	unsigned, _ := types.NewTransaction(0, common.Address{}, nil, 0, nil, nil).WithSignature(types.HomesteadSigner{}, make([]byte, 65))
+	blob, _ := rlp.EncodeToBytes(unsigned)
+	if _, err := api.DecodeRawTransaction(blob); err == nil || !strings.Contains(err.Error(), "invalid transaction signature") {
+		t.Errorf("invalid signature error mismatch: have %v", err)
+	}
+}
+
+// chaindbBackend is a stub serving a fixed key-value database.
+type chaindbBackend struct {
+	Backend
+	db ccmdb.Database
+}
+
+func (b *chaindbBackend) ChainDb() ccmdb.Database { return b.db }
+
+// Tests that database iteration is limited to the prefix and the key limit, and
+// only returns values on request.
+func TestChaindbIterate(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	for _, key := range []string{"a1", "b1", "b2", "b3", "c1"} {
+		db.Put([]byte(key), []byte("v"+key))
+	}
+	api := NewPrivateDebugAPI(&chaindbBackend{db: db})
+
+	entries, err := api.ChaindbIterate([]byte("b"), 2, nil)
+	if err != nil {
+		t.Fatalf("failed to iterate database: %v", err)
+	}
+	if len(entries) != 2 || string(entries[0].Key) != "b1" || string(entries[1].Key) != "b2" || entries[0].Value != nil {
+		t.Errorf("limited iteration mismatch: have %+v, want keys b1 and b2 without values", entries)
+	}
+	values := true
+	if entries, err = api.ChaindbIterate([]byte("b"), 10, &values); err != nil {
+		t.Fatalf("failed to iterate database: %v", err)
+	}
+	if len(entries) != 3 || string(entries[2].Key) != "b3" || string(entries[2].Value) != "vb3" {
+		t.Errorf("prefix iteration mismatch: have %+v, want b1 to b3 with values", entries)
+	}
+	if entries, err = api.ChaindbIterate([]byte("d"), 10, nil); err != nil || entries == nil || len(entries) != 0 {
+		t.Errorf("empty iteration mismatch: have %v, %v, want empty list", entries, err)
+	}
+	for _, limit := range []int{0, maxChaindbIterateKeys + 1} {
+		if _, err := api.ChaindbIterate(nil, limit, nil); err == nil {
+			t.Errorf("limit %d: expected error", limit)
+		}
+	}
+}
+
+// Tests that keystore accounts are listed along with their key file path and
+// modification time.
+func TestAccountsInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ccmapi-keystore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	am := accounts.NewManager(&accounts.Config{}, ks)
+	defer am.Close()
+	api := NewPrivateAccountAPI(&accountBackend{am: am}, new(AddrLocker))
+
+	if infos := api.AccountsInfo(); infos == nil || len(infos) != 0 {
+		t.Fatalf("empty keystore accounts mismatch: have %v, want empty list", infos)
+	}
+	account, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	// Wallet arrivals are delivered to the manager asynchronously
+	var infos []accountInfo
+	for deadline := time.Now().Add(5 * time.Second); len(infos) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("account not listed in time")
+		}
+		infos = api.AccountsInfo()
+	}
+	info := infos[0]
+	if len(infos) != 1 || info.Address != account.Address || info.Type != "keystore" || info.URL != account.URL.String() {
+		t.Fatalf("account info mismatch: have %+v", infos)
+	}
+	if info.Path != account.URL.Path || !strings.HasPrefix(info.Path, dir) {
+		t.Errorf("key file path mismatch: have %s, want %s", info.Path, account.URL.Path)
+	}
+	if stat, err := os.Stat(info.Path); err != nil || info.Modified == nil || !info.Modified.Equal(stat.ModTime()) {
+		t.Errorf("modification time mismatch: have %v, want key file's", info.Modified)
+	}
+}
@@ -19,6 +19,7 @@ package ccmapi
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/ccmchain/go-ccmchain/accounts"
@@ -35,6 +36,22 @@ import (
 	"github.com/ccmchain/go-ccmchain/rpc"
 )
 
+// MaxLogsInRange is the maximum number of logs a single ranged log query served
+// by the backend may return before it is aborted.
+const MaxLogsInRange = 10000
+
+// MaxBalanceBatch is the maximum number of accounts whose balances may be queried
+// in a single request.
+const MaxBalanceBatch = 1024
+
+// ErrRPCUnderpriced is returned if a transaction submitted through the RPC APIs
+// has a gas price below the minimum configured by the node operator.
+var ErrRPCUnderpriced = errors.New("gas price below RPC minimum")
+
+// ErrDroppedTxsUnsupported is returned by backends whose transaction pool does not
+// report the transactions dropped without being mined.
+var ErrDroppedTxsUnsupported = errors.New("dropped transaction notifications not supported")
+
 // Backend interface provides the common API services (that are provided by
 // both full and light clients) with access to necessary functions.
 type Backend interface {
@@ -42,45 +59,77 @@ type Backend interface {
 	Downloader() *downloader.Downloader
 	ProtocolVersion() int
 	SuggestPrice(ctx context.Context) (*big.Int, error)
+	SuggestPriorityFee(ctx context.Context) (*big.Int, error)
+	SuggestPriceTier(ctx context.Context, tier string) (*big.Int, error)
+	GasPriceFiltering() bool // whether the gas price oracle skips non-market transactions
 	ChainDb() ccmdb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
-	RPCGasCap() *big.Int // global gas cap for ccm_call over rpc: DoS protection
+	RPCGasCap() *big.Int    // global gas cap for ccm_call over rpc: DoS protection
+	TraceGasCap() *big.Int  // global gas cap for debug tracing, nil means block gas limit
+	RPCCallDataCap() uint64 // maximum input data size for ccm_call over rpc, zero means no limit
+	Requests() *RequestRegistry
 
 	// Blockchain API
 	SetHead(number uint64)
+	BlockRange() (lowest uint64, highest uint64) // range of blocks whose full data is available
 	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
 	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
 	StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+	StatesAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber, n int) ([]*state.StateDB, *types.Header, func(), error) // n independent states, bounded until released
+	GetCodeSize(ctx context.Context, addr common.Address, number rpc.BlockNumber) (int, error)
+	BalancesAt(ctx context.Context, addrs []common.Address, number rpc.BlockNumber) ([]*big.Int, error)
+	GetAccount(ctx context.Context, addr common.Address, number rpc.BlockNumber) (*AccountInfo, error)
 	GetHeader(ctx context.Context, hash common.Hash) *types.Header
+	GasUsageStats(ctx context.Context, blocks int) (*GasUsageStats, error)
 	GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error)
+	GetUncle(ctx context.Context, blockHash common.Hash, index int) (*types.Header, error)
+	BlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) (int, error)
+	BlockTransactionCount(ctx context.Context, hash common.Hash) (int, error)
 	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	GetReceiptsByTxHashes(ctx context.Context, hashes []common.Hash) ([]*ReceiptInfo, error)
 	GetTd(hash common.Hash) *big.Int
-	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error)
+	CurrentTd() *big.Int
+	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error)
+	GetEVMWithConfig(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig) (*vm.EVM, func() error, error)
+	CallBundle(ctx context.Context, msgs []core.Message, number rpc.BlockNumber, overrides StateOverride) ([]*BundleResult, error)
+	ForkEffect(ctx context.Context, msg core.Message, number rpc.BlockNumber) (*ForkEffect, error)
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	SubscribeFinalityHeadEvent(ch chan<- core.FinalityHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
+	PendingFeeHistogram(buckets int) ([]*FeeBucket, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
-	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
+	HasPoolTransaction(txHash common.Hash) bool
+	PoolTransactionStatus(ctx context.Context, txHash common.Hash) core.TxStatus
+	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)                          // may point past a gap on light clients only
+	GetPoolNonceGap(ctx context.Context, addr common.Address) (next uint64, gapped bool, err error) // gapped reports queued txs blocked at nonce next
+	NextSendableNonce(ctx context.Context, addr common.Address) (uint64, error)                     // next immediately executable nonce, filling any gap
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
+	ImportTxPool(ctx context.Context, txs types.Transactions) []error
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	SubscribeFilteredTxsEvent(ch chan<- core.NewTxsEvent, filter PendingTxFilter) event.Subscription // only txs matching the filter
+	SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) (event.Subscription, error)             // txs removed from the pool unmined
 
 	// Filter API
 	BloomStatus() (uint64, uint64)
 	GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error)
+	GetLogsInRange(ctx context.Context, from, to uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
+	SubscribeAllLogsEvent(ch chan<- []*types.Log) event.Subscription // added and removed logs, in canonical order
 
 	ChainConfig() *params.ChainConfig
+	ChainConfigFull() *ChainConfigFull
 	CurrentBlock() *types.Block
 }
 
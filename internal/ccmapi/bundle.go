@@ -0,0 +1,150 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ccmchain/go-ccmchain/accounts/abi"
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/core/vm"
+	"github.com/ccmchain/go-ccmchain/params"
+)
+
+// MaxBundleSize is the maximum number of messages a single bundle may contain.
+const MaxBundleSize = 256
+
+// OverrideAccount specifies the fields of an account to replace in the state
+// before executing a call bundle.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64             `json:"nonce"`
+	Code      *hexutil.Bytes              `json:"code"`
+	Balance   *hexutil.Big                `json:"balance"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the specified accounts in the given state.
+func (diff StateOverride) Apply(state *state.StateDB) {
+	for addr, account := range diff {
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(account.Balance))
+		}
+		for key, value := range account.StateDiff {
+			state.SetState(addr, key, value)
+		}
+	}
+}
+
+// BundleResult is the outcome of a single message executed within a bundle.
+type BundleResult struct {
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	ReturnData   hexutil.Bytes  `json:"returnData"`
+	Logs         []*types.Log   `json:"logs"`
+	Failed       bool           `json:"failed"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// ApplyBundle executes the given messages one after the other on top of the
+// provided state, each of them seeing the effects of the previous ones. Contrary
+// to single calls, senders aren't granted unlimited funds, so value transfers and
+// gas spent carry over between messages. The execution is aborted if ctx is
+// cancelled.
+func ApplyBundle(ctx context.Context, config *params.ChainConfig, chain core.ChainContext, vmCfg vm.Config, msgs []core.Message, statedb *state.StateDB, header *types.Header) ([]*BundleResult, error) {
+	if len(msgs) == 0 || len(msgs) > MaxBundleSize {
+		return nil, fmt.Errorf("invalid bundle size %d, must be between 1 and %d", len(msgs), MaxBundleSize)
+	}
+	// Create a single EVM over the shared state, only swapping the message
+	// specific fields of its context between executions
+	evm := vm.NewEVM(core.NewEVMContext(msgs[0], header, chain, nil), statedb, config, vmCfg)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+
+	results := make([]*BundleResult, 0, len(msgs))
+	for i, msg := range msgs {
+		// Logs are keyed by transaction hash, use the message index instead
+		statedb.Prepare(common.BigToHash(big.NewInt(int64(i))), header.Hash(), i)
+
+		evm.Origin = msg.From()
+		evm.GasPrice = new(big.Int).Set(msg.GasPrice())
+
+		ret, gas, failed, err := core.ApplyMessage(evm, msg, gp)
+		if evm.Cancelled() {
+			return nil, errors.New("bundle execution aborted")
+		}
+		if err := statedb.Error(); err != nil {
+			return nil, err
+		}
+		result := &BundleResult{
+			GasUsed:    hexutil.Uint64(gas),
+			ReturnData: ret,
+			Logs:       statedb.GetLogs(common.BigToHash(big.NewInt(int64(i)))),
+			Failed:     failed,
+		}
+		for _, log := range result.Logs {
+			log.TxHash = common.Hash{}
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if failed {
+			result.RevertReason = unpackRevertReason(ret)
+		}
+		results = append(results, result)
+
+		// Finalise the state so the next message sees a clean journal
+		statedb.Finalise(config.IsEIP158(header.Number))
+	}
+	return results, nil
+}
+
+// unpackRevertReason decodes the Error(string) or Panic(uint256) revert reason
+// contained in the return data of a reverted execution, returning an empty string
+// if there's none.
+func unpackRevertReason(ret []byte) string {
+	reason, err := abi.UnpackRevert(ret)
+	if err != nil {
+		return ""
+	}
+	return reason
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errCompactionRunning is returned if a database compaction is requested while
+// another one is still in progress.
+var errCompactionRunning = errors.New("database compaction already running")
+
+// CompactionStatus reports the progress of the current or last database
+// compaction. The database is compacted in equally sized key ranges, so the
+// remaining time is only a rough estimate: ranges can differ wildly in size.
+type CompactionStatus struct {
+	Running   bool   `json:"running"`
+	Completed int    `json:"completed"`           // Number of key ranges compacted
+	Total     int    `json:"total"`               // Number of key ranges to compact
+	Elapsed   string `json:"elapsed,omitempty"`   // Time spent compacting so far
+	Remaining string `json:"remaining,omitempty"` // Estimated time left, if running
+	Error     string `json:"error,omitempty"`     // Failure of the last compaction
+}
+
+// compactionTracker keeps track of the progress of database compactions.
+type compactionTracker struct {
+	running   bool
+	completed int
+	total     int
+	started   time.Time
+	elapsed   time.Duration // Duration of the last finished compaction
+	err       error
+	lock      sync.Mutex
+}
+
+// start marks a compaction of the given number of key ranges as running, failing
+// if another one already is.
+func (t *compactionTracker) start(total int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.running {
+		return errCompactionRunning
+	}
+	t.running, t.completed, t.total = true, 0, total
+	t.started, t.elapsed, t.err = time.Now(), 0, nil
+	return nil
+}
+
+// progress marks another key range as compacted.
+func (t *compactionTracker) progress() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.completed++
+}
+
+// finish marks the running compaction as done, recording its failure if any.
+func (t *compactionTracker) finish(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.running, t.elapsed, t.err = false, time.Since(t.started), err
+}
+
+// status returns the progress of the current or last compaction.
+func (t *compactionTracker) status() *CompactionStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := &CompactionStatus{Running: t.running, Completed: t.completed, Total: t.total}
+	if t.err != nil {
+		status.Error = t.err.Error()
+	}
+	switch {
+	case t.running:
+		elapsed := time.Since(t.started)
+		status.Elapsed = elapsed.Round(time.Second).String()
+		if t.completed > 0 {
+			remaining := elapsed * time.Duration(t.total-t.completed) / time.Duration(t.completed)
+			status.Remaining = remaining.Round(time.Second).String()
+		}
+	case !t.started.IsZero():
+		status.Elapsed = t.elapsed.Round(time.Second).String()
+	}
+	return status
+}
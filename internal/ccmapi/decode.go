@@ -0,0 +1,58 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"github.com/ccmchain/go-ccmchain/accounts/abi"
+)
+
+// DecodedCall is the input of a transaction decoded against a contract ABI. If
+// the input cannot be decoded, Error holds the reason and Call, if possible, the
+// raw selector and arguments.
+type DecodedCall struct {
+	Method    string                 `json:"method,omitempty"`
+	Signature string                 `json:"signature,omitempty"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+	Call      string                 `json:"call,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// DecodeCallData decodes the given transaction input against the contract ABI.
+// Failures are reported in the result rather than as an error, so that a call
+// of a method missing from the ABI is still rendered as far as possible.
+func DecodeCallData(contract abi.ABI, data []byte) *DecodedCall {
+	decoded := new(DecodedCall)
+	if len(data) == 0 {
+		decoded.Error = "transaction carries no call data"
+		return decoded
+	}
+	if call, err := contract.FormatCall(data); err == nil {
+		decoded.Call = call
+	}
+	method, err := contract.MethodById(data)
+	if err != nil {
+		decoded.Error = err.Error()
+		return decoded
+	}
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMapHex(args, data[4:]); err != nil {
+		decoded.Error = err.Error()
+		return decoded
+	}
+	decoded.Method, decoded.Signature, decoded.Args = method.Name, method.Sig(), args
+	return decoded
+}
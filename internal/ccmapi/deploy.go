@@ -0,0 +1,52 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ccmchain/go-ccmchain/accounts/abi"
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// ConstructorArgsError is returned by EstimateDeployGas if the constructor
+// arguments can't be packed, to tell invalid input apart from failed estimations.
+type ConstructorArgsError struct {
+	Err error
+}
+
+func (e *ConstructorArgsError) Error() string {
+	return fmt.Sprintf("invalid constructor arguments: %v", e.Err)
+}
+
+// EstimateDeployGas estimates the gas needed to deploy a contract from the given
+// account against the pending state. The constructor arguments are validated
+// against the contract ABI and appended to the bytecode before estimating.
+func EstimateDeployGas(ctx context.Context, b Backend, from common.Address, bytecode []byte, constructorArgs []interface{}, contract abi.ABI) (hexutil.Uint64, error) {
+	data, err := contract.PackConstructorWithCode(bytecode, constructorArgs...)
+	if err != nil {
+		return 0, &ConstructorArgsError{Err: err}
+	}
+	args := CallArgs{
+		From: &from,
+		Data: (*hexutil.Bytes)(&data),
+	}
+	return DoEstimateGas(ctx, b, args, rpc.PendingBlockNumber, b.RPCGasCap())
+}
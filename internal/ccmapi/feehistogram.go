@@ -0,0 +1,90 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"math/big"
+
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core/types"
+)
+
+const (
+	// DefaultFeeHistogramBuckets is the number of buckets the pending fee
+	// histogram is split into if the caller doesn't specify one.
+	DefaultFeeHistogramBuckets = 10
+
+	// MaxFeeHistogramBuckets is the maximum number of buckets the pending fee
+	// histogram may be split into.
+	MaxFeeHistogramBuckets = 100
+)
+
+// FeeBucket is a gas price range of the pending fee histogram along with the
+// number of pending transactions paying a price within it.
+type FeeBucket struct {
+	MinPrice *hexutil.Big `json:"minPrice"` // Inclusive lower bound of the range
+	MaxPrice *hexutil.Big `json:"maxPrice"` // Inclusive upper bound of the range
+	Count    int          `json:"count"`
+}
+
+// NewFeeHistogram splits the gas price range of the given transactions into the
+// requested number of equally wide buckets and counts the transactions in each.
+// Fewer buckets may be returned if the price range can't be split evenly into
+// the requested number, and none if there are no transactions.
+func NewFeeHistogram(txs types.Transactions, buckets int) []*FeeBucket {
+	if len(txs) == 0 || buckets < 1 {
+		return []*FeeBucket{}
+	}
+	min, max := txs[0].GasPrice(), txs[0].GasPrice()
+	for _, tx := range txs[1:] {
+		if price := tx.GasPrice(); price.Cmp(min) < 0 {
+			min = price
+		} else if price.Cmp(max) > 0 {
+			max = price
+		}
+	}
+	// Calculate the bucket width, rounding up so the last bucket includes max
+	span := new(big.Int).Sub(max, min)
+	span.Add(span, big.NewInt(1))
+	if span.Cmp(big.NewInt(int64(buckets))) < 0 {
+		buckets = int(span.Int64())
+	}
+	width := new(big.Int).Add(span, big.NewInt(int64(buckets-1)))
+	width.Div(width, big.NewInt(int64(buckets)))
+
+	// Drop the trailing buckets left empty by the rounding
+	needed := new(big.Int).Add(span, new(big.Int).Sub(width, big.NewInt(1)))
+	buckets = int(needed.Div(needed, width).Int64())
+
+	histogram := make([]*FeeBucket, buckets)
+	for i := range histogram {
+		lower := new(big.Int).Mul(width, big.NewInt(int64(i)))
+		lower.Add(lower, min)
+		upper := new(big.Int).Add(lower, width)
+		upper.Sub(upper, big.NewInt(1))
+		if upper.Cmp(max) > 0 {
+			upper.Set(max)
+		}
+		histogram[i] = &FeeBucket{MinPrice: (*hexutil.Big)(lower), MaxPrice: (*hexutil.Big)(upper)}
+	}
+	for _, tx := range txs {
+		index := new(big.Int).Sub(tx.GasPrice(), min)
+		index.Div(index, width)
+		histogram[index.Int64()].Count++
+	}
+	return histogram
+}
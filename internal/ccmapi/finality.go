@@ -0,0 +1,104 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"github.com/ccmchain/go-ccmchain/consensus"
+	"github.com/ccmchain/go-ccmchain/consensus/clique"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/log"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// FinalityDepth returns the number of blocks that need to be built on top of a
+// block for it to be considered final, as seen from the given head. On clique
+// chains a block is final once more than half of the signers have sealed blocks
+// on top of it; for every other engine the configured depth is used.
+func FinalityDepth(engine consensus.Engine, chain consensus.ChainReader, head *types.Header, depth uint64) uint64 {
+	if engine, ok := engine.(*clique.Clique); ok {
+		signers, err := engine.Signers(chain, head)
+		if err == nil {
+			return uint64(len(signers)/2 + 1)
+		}
+		log.Debug("Failed to retrieve clique signers", "number", head.Number, "err", err)
+	}
+	return depth
+}
+
+// ResolveConfirmedNumber converts the safe and finalized block tags into the number
+// of the block considered as such, as seen from the given head. Safe blocks are
+// the given safe depth behind the head, whereas finalized ones are resolved with
+// FinalityDepth. Any other block number is returned unchanged.
+func ResolveConfirmedNumber(engine consensus.Engine, chain consensus.ChainReader, head *types.Header, number rpc.BlockNumber, safeDepth, finalityDepth uint64) rpc.BlockNumber {
+	var depth uint64
+	switch number {
+	case rpc.SafeBlockNumber:
+		depth = safeDepth
+	case rpc.FinalizedBlockNumber:
+		depth = FinalityDepth(engine, chain, head, finalityDepth)
+	default:
+		return number
+	}
+	if current := head.Number.Uint64(); current > depth {
+		return rpc.BlockNumber(current - depth)
+	}
+	return rpc.EarliestBlockNumber
+}
+
+// finalizedNumber returns the number of the latest block made final by a head at
+// the given number, the confirmation depth behind it, and whether the chain is
+// deep enough for any block to be final at all.
+func finalizedNumber(head, depth uint64) (uint64, bool) {
+	if head < depth {
+		return 0, false
+	}
+	return head - depth, true
+}
+
+// NewFinalityHeadSubscription creates a subscription delivering every new chain
+// head announced by subscribeHeads, annotated with the latest final block.
+func NewFinalityHeadSubscription(subscribeHeads func(chan<- core.ChainHeadEvent) event.Subscription, engine consensus.Engine, chain consensus.ChainReader, depth uint64, ch chan<- core.FinalityHeadEvent) event.Subscription {
+	// Subscribe before returning, so no head announced meanwhile is missed
+	heads := make(chan core.ChainHeadEvent, 10)
+	sub := subscribeHeads(heads)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-heads:
+				head := ev.Block.Header()
+				finality := core.FinalityHeadEvent{Header: head}
+				if number, ok := finalizedNumber(head.Number.Uint64(), FinalityDepth(engine, chain, head, depth)); ok {
+					finality.Finalized = chain.GetHeaderByNumber(number)
+				}
+				select {
+				case ch <- finality:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
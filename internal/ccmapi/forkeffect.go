@@ -0,0 +1,145 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/params"
+)
+
+// errNoPendingFork is returned if the chain config doesn't schedule any fork
+// after the requested block.
+var errNoPendingFork = errors.New("no pending fork scheduled")
+
+// ForkExecution is the outcome of executing a message under a set of chain rules.
+type ForkExecution struct {
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Failed     bool           `json:"failed"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// ForkEffect compares the execution of a message under the current chain rules
+// with its execution under the rules of the next scheduled fork.
+type ForkEffect struct {
+	ForkBlock *hexutil.Big   `json:"forkBlock"`
+	Current   *ForkExecution `json:"current"`
+	Next      *ForkExecution `json:"next"`
+	Changed   bool           `json:"changed"`
+}
+
+// NextForkConfig returns a copy of config in which the earliest fork scheduled
+// after number is already active at number, along with the original block of
+// that fork. The DAO fork is ignored as it only alters state, not the rules.
+func NextForkConfig(config *params.ChainConfig, number *big.Int) (*params.ChainConfig, *big.Int) {
+	next := *config
+	forks := []**big.Int{
+		&next.HomesteadBlock,
+		&next.EIP150Block,
+		&next.EIP155Block,
+		&next.EIP158Block,
+		&next.ByzantiumBlock,
+		&next.ConstantinopleBlock,
+		&next.PetersburgBlock,
+	}
+	var forkBlock *big.Int
+	for _, fork := range forks {
+		if *fork != nil && (*fork).Cmp(number) > 0 && (forkBlock == nil || (*fork).Cmp(forkBlock) < 0) {
+			forkBlock = *fork
+		}
+	}
+	if forkBlock == nil {
+		return nil, nil
+	}
+	// Activate every fork scheduled at the same block
+	for _, fork := range forks {
+		if *fork != nil && (*fork).Cmp(forkBlock) == 0 {
+			*fork = new(big.Int).Set(number)
+		}
+	}
+	return &next, forkBlock
+}
+
+// CompareForkRules executes the message on top of the given state both under the
+// current chain rules and under the rules of the next scheduled fork, reporting
+// any difference in success, gas used or return data. The state is not modified.
+func CompareForkRules(ctx context.Context, b Backend, msg core.Message, statedb *state.StateDB, header *types.Header) (*ForkEffect, error) {
+	config, forkBlock := NextForkConfig(b.ChainConfig(), header.Number)
+	if config == nil {
+		return nil, errNoPendingFork
+	}
+	current, err := executeWithConfig(ctx, b, msg, statedb.Copy(), header, b.ChainConfig())
+	if err != nil {
+		return nil, err
+	}
+	next, err := executeWithConfig(ctx, b, msg, statedb.Copy(), header, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ForkEffect{
+		ForkBlock: (*hexutil.Big)(forkBlock),
+		Current:   current,
+		Next:      next,
+		Changed: current.Failed != next.Failed || current.GasUsed != next.GasUsed ||
+			!bytes.Equal(current.ReturnData, next.ReturnData),
+	}, nil
+}
+
+// executeWithConfig applies the message on top of the given state using an EVM
+// configured with the given chain rules.
+func executeWithConfig(ctx context.Context, b Backend, msg core.Message, statedb *state.StateDB, header *types.Header, config *params.ChainConfig) (*ForkExecution, error) {
+	evm, vmError, err := b.GetEVMWithConfig(ctx, msg, statedb, header, config)
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	ret, gas, failed, err := core.ApplyMessage(evm, msg, gp)
+	close(done)
+
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if evm.Cancelled() {
+		return nil, errors.New("execution aborted")
+	}
+	result := &ForkExecution{
+		GasUsed:    hexutil.Uint64(gas),
+		ReturnData: ret,
+		Failed:     failed,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
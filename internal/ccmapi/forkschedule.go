@@ -0,0 +1,91 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/params"
+)
+
+// ForkActivation is a single scheduled fork of the chain.
+type ForkActivation struct {
+	Name   string       `json:"name"`
+	Block  *hexutil.Big `json:"block"`
+	Active bool         `json:"active"`
+}
+
+// ChainConfigFull is the chain configuration along with its fork schedule, as
+// seen from the current head of the chain.
+type ChainConfigFull struct {
+	Config  *params.ChainConfig `json:"config"`
+	Forks   []ForkActivation    `json:"forks"`   // Scheduled forks, ordered by activation block
+	Current string              `json:"current"` // Latest active fork, Frontier if none
+}
+
+// NewChainConfigFull computes the fork schedule of the given chain configuration,
+// marking the forks active at the given head block.
+func NewChainConfigFull(config *params.ChainConfig, head uint64) *ChainConfigFull {
+	// Petersburg is implicitly activated along with Constantinople if unset
+	petersburg := config.PetersburgBlock
+	if petersburg == nil {
+		petersburg = config.ConstantinopleBlock
+	}
+	forks := []struct {
+		name  string
+		block *big.Int
+	}{
+		{"Homestead", config.HomesteadBlock},
+		{"DAO", config.DAOForkBlock},
+		{"EIP150", config.EIP150Block},
+		{"EIP155", config.EIP155Block},
+		{"EIP158", config.EIP158Block},
+		{"Byzantium", config.ByzantiumBlock},
+		{"Constantinople", config.ConstantinopleBlock},
+		{"Petersburg", petersburg},
+		{"EWASM", config.EWASMBlock},
+	}
+	full := &ChainConfigFull{
+		Config:  config,
+		Forks:   []ForkActivation{},
+		Current: "Frontier",
+	}
+	number := new(big.Int).SetUint64(head)
+	for _, fork := range forks {
+		if fork.block == nil {
+			continue
+		}
+		full.Forks = append(full.Forks, ForkActivation{
+			Name:   fork.name,
+			Block:  (*hexutil.Big)(new(big.Int).Set(fork.block)),
+			Active: fork.block.Cmp(number) <= 0,
+		})
+	}
+	// Forks are listed in protocol order, which may only differ from the order of
+	// activation on misconfigured chains. Keep protocol order for ties.
+	sort.SliceStable(full.Forks, func(i, j int) bool {
+		return full.Forks[i].Block.ToInt().Cmp(full.Forks[j].Block.ToInt()) < 0
+	})
+	for _, fork := range full.Forks {
+		if fork.Active {
+			full.Current = fork.Name
+		}
+	}
+	return full
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"fmt"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core/types"
+)
+
+const (
+	// DefaultGasUsageBlocks is the number of blocks gas usage statistics are
+	// calculated over if the caller doesn't specify a window.
+	DefaultGasUsageBlocks = 20
+
+	// MaxGasUsageBlocks is the maximum number of blocks gas usage statistics
+	// may be calculated over in a single request.
+	MaxGasUsageBlocks = 1024
+)
+
+// GasUsageStats summarises how full the recent blocks of the chain are.
+type GasUsageStats struct {
+	Number   hexutil.Uint64 `json:"number"`   // Number of the newest block in the window
+	GasLimit hexutil.Uint64 `json:"gasLimit"` // Gas limit of the newest block
+	Blocks   int            `json:"blocks"`   // Number of blocks the statistics were calculated over
+	Ratio    float64        `json:"ratio"`    // Average gas used / gas limit over the window
+	Trend    float64        `json:"trend"`    // Average ratio of the newer half minus that of the older half
+}
+
+// HeaderReader is the subset of the chain methods needed to walk the locally
+// stored canonical headers.
+type HeaderReader interface {
+	CurrentHeader() *types.Header
+	GetHeader(hash common.Hash, number uint64) *types.Header
+}
+
+// ChainGasUsageStats calculates the gas usage statistics over the given number of
+// most recent canonical blocks, walking the headers backwards from the head.
+func ChainGasUsageStats(chain HeaderReader, blocks int) (*GasUsageStats, error) {
+	if blocks <= 0 || blocks > MaxGasUsageBlocks {
+		return nil, fmt.Errorf("invalid number of blocks %d, must be between 1 and %d", blocks, MaxGasUsageBlocks)
+	}
+	header := chain.CurrentHeader()
+	headers := make([]*types.Header, 0, blocks)
+	for header != nil && len(headers) < blocks {
+		headers = append(headers, header)
+		if header.Number.Sign() == 0 {
+			break
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return NewGasUsageStats(headers), nil
+}
+
+// NewGasUsageStats calculates the gas usage statistics over the given headers,
+// which must be ordered from the newest to the oldest and be non-empty.
+func NewGasUsageStats(headers []*types.Header) *GasUsageStats {
+	ratios := make([]float64, len(headers))
+	for i, header := range headers {
+		if header.GasLimit > 0 {
+			ratios[i] = float64(header.GasUsed) / float64(header.GasLimit)
+		}
+	}
+	stats := &GasUsageStats{
+		Number:   hexutil.Uint64(headers[0].Number.Uint64()),
+		GasLimit: hexutil.Uint64(headers[0].GasLimit),
+		Blocks:   len(headers),
+		Ratio:    averageRatio(ratios),
+	}
+	if half := len(ratios) / 2; half > 0 {
+		stats.Trend = averageRatio(ratios[:half]) - averageRatio(ratios[len(ratios)-half:])
+	}
+	return stats
+}
+
+// averageRatio returns the arithmetic mean of the given ratios.
+func averageRatio(ratios []float64) float64 {
+	var sum float64
+	for _, ratio := range ratios {
+		sum += ratio
+	}
+	return sum / float64(len(ratios))
+}
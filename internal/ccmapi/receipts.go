@@ -0,0 +1,78 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core/types"
+)
+
+// MaxReceiptBatch is the maximum number of transaction hashes whose receipts may
+// be queried in a single request.
+const MaxReceiptBatch = 1024
+
+// ReceiptInfo is the minimal outcome of an included transaction.
+type ReceiptInfo struct {
+	TxHash      common.Hash     `json:"transactionHash"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	GasUsed     hexutil.Uint64  `json:"gasUsed"`
+	Status      *hexutil.Uint64 `json:"status,omitempty"` // nil for pre-Byzantium receipts carrying a post state
+}
+
+// LookupReceiptInfos retrieves the receipt info of every given transaction via
+// the transaction index, leaving nil entries for unknown transactions. Receipts
+// are fetched once per block, however many of its transactions are queried.
+func LookupReceiptInfos(ctx context.Context, b Backend, hashes []common.Hash) ([]*ReceiptInfo, error) {
+	var (
+		infos  = make([]*ReceiptInfo, len(hashes))
+		blocks = make(map[common.Hash]types.Receipts)
+	)
+	for i, hash := range hashes {
+		tx, blockHash, blockNumber, index, err := b.GetTransaction(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if tx == nil {
+			continue
+		}
+		receipts, ok := blocks[blockHash]
+		if !ok {
+			if receipts, err = b.GetReceipts(ctx, blockHash); err != nil {
+				return nil, err
+			}
+			blocks[blockHash] = receipts
+		}
+		if len(receipts) <= int(index) {
+			continue
+		}
+		receipt := receipts[index]
+
+		infos[i] = &ReceiptInfo{
+			TxHash:      hash,
+			BlockNumber: hexutil.Uint64(blockNumber),
+			GasUsed:     hexutil.Uint64(receipt.GasUsed),
+		}
+		if len(receipt.PostState) == 0 {
+			status := hexutil.Uint64(receipt.Status)
+			infos[i].Status = &status
+		}
+	}
+	return infos, nil
+}
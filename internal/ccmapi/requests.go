@@ -0,0 +1,162 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/log"
+)
+
+// RunningRequest describes a heavy RPC execution currently tracked by a
+// RequestRegistry.
+type RunningRequest struct {
+	ID      hexutil.Uint64 `json:"id"`
+	Method  string         `json:"method"`
+	Started time.Time      `json:"started"`
+}
+
+// trackedRequest is a running request along with the means to abort it.
+type trackedRequest struct {
+	info   RunningRequest
+	cancel context.CancelFunc
+}
+
+// Classes of heavy RPC executions whose timeouts can be configured as a group.
+const (
+	RequestClassCall     = "call"     // ccm_call and other single or bundled calls
+	RequestClassEstimate = "estimate" // gas estimations
+	RequestClassTrace    = "trace"    // transaction, call and replay traces
+)
+
+// DefaultRequestTimeouts are the timeouts applied to heavy RPC executions if not
+// configured otherwise. Estimations and traces are unbounded, the latter being
+// limited by the timeout of JavaScript tracers instead.
+var DefaultRequestTimeouts = map[string]time.Duration{
+	RequestClassCall: 5 * time.Second,
+}
+
+// RequestClass returns the timeout class the given RPC method belongs to.
+func RequestClass(method string) string {
+	switch {
+	case method == "ccm_estimateGas":
+		return RequestClassEstimate
+	case strings.HasPrefix(method, "debug_trace"), strings.HasPrefix(method, "debug_standardTrace"), method == "debug_replayTransaction":
+		return RequestClassTrace
+	default:
+		return RequestClassCall
+	}
+}
+
+// RequestRegistry keeps track of in-flight heavy RPC executions (calls, gas
+// estimations, traces), assigning each an ID through which it can be aborted.
+type RequestRegistry struct {
+	running  map[uint64]*trackedRequest
+	timeouts map[string]time.Duration
+	nextID   uint64
+	lock     sync.Mutex
+}
+
+// NewRequestRegistry creates an empty registry of running requests, enforcing
+// the given timeouts on them on top of DefaultRequestTimeouts. The timeouts are
+// keyed by method name or request class, with method names taking precedence.
+// A zero timeout lifts the limit.
+func NewRequestRegistry(timeouts map[string]time.Duration) *RequestRegistry {
+	merged := make(map[string]time.Duration, len(DefaultRequestTimeouts)+len(timeouts))
+	for key, timeout := range DefaultRequestTimeouts {
+		merged[key] = timeout
+	}
+	for key, timeout := range timeouts {
+		merged[key] = timeout
+	}
+	return &RequestRegistry{
+		running:  make(map[uint64]*trackedRequest),
+		timeouts: merged,
+	}
+}
+
+// Timeout returns the time the given method may execute for before it's aborted,
+// zero meaning no limit.
+func (r *RequestRegistry) Timeout(method string) time.Duration {
+	if timeout, ok := r.timeouts[method]; ok {
+		return timeout
+	}
+	return r.timeouts[RequestClass(method)]
+}
+
+// Track registers a new execution of the given method, returning a context
+// derived from ctx that is cancelled if the request is aborted or exceeds the
+// timeout of the method, along with a function the caller must invoke once the
+// execution finished.
+func (r *RequestRegistry) Track(ctx context.Context, method string) (context.Context, func()) {
+	var cancel context.CancelFunc
+	if timeout := r.Timeout(method); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	r.lock.Lock()
+	r.nextID++
+	id := r.nextID
+	r.running[id] = &trackedRequest{
+		info:   RunningRequest{ID: hexutil.Uint64(id), Method: method, Started: time.Now()},
+		cancel: cancel,
+	}
+	r.lock.Unlock()
+
+	log.Debug("Tracking RPC execution", "id", id, "method", method)
+	return ctx, func() {
+		r.lock.Lock()
+		delete(r.running, id)
+		r.lock.Unlock()
+		cancel()
+	}
+}
+
+// Cancel aborts the running request with the given ID, reporting whether such
+// a request was found.
+func (r *RequestRegistry) Cancel(id uint64) bool {
+	r.lock.Lock()
+	req, ok := r.running[id]
+	delete(r.running, id)
+	r.lock.Unlock()
+
+	if ok {
+		log.Info("Cancelled RPC execution", "id", id, "method", req.info.Method, "elapsed", time.Since(req.info.Started))
+		req.cancel()
+	}
+	return ok
+}
+
+// Running returns the requests currently being executed, ordered by ID.
+func (r *RequestRegistry) Running() []RunningRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	requests := make([]RunningRequest, 0, len(r.running))
+	for _, req := range r.running {
+		requests = append(requests, req.info)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].ID < requests[j].ID })
+	return requests
+}
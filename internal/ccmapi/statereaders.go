@@ -0,0 +1,93 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxConcurrentStates is the maximum number of independent states that may be
+// open at once for running calls in parallel.
+const MaxConcurrentStates = 64
+
+// stateAcquireTimeout is the maximum time to wait for states to be released if
+// the limit of open states is reached.
+const stateAcquireTimeout = time.Second
+
+// errStatesBusy is returned if no states could be acquired in time.
+var errStatesBusy = errors.New("too many concurrent states open, try again later")
+
+// StateReaders bounds the number of independent read-only states opened by the
+// backend at the same time. Every state pins its own object cache in memory, so
+// without a limit a burst of parallel executions could exhaust the node.
+type StateReaders struct {
+	slots     chan struct{} // Semaphore with one slot per open state
+	acquiring chan struct{} // Serializes multi-slot acquisitions to avoid deadlocks
+}
+
+// NewStateReaders creates a limiter allowing at most limit states to be open
+// concurrently.
+func NewStateReaders(limit int) *StateReaders {
+	return &StateReaders{
+		slots:     make(chan struct{}, limit),
+		acquiring: make(chan struct{}, 1),
+	}
+}
+
+// Acquire reserves n states, blocking until enough are released, the context is
+// cancelled or a short timeout elapses. The returned function must be invoked
+// once the states are no longer used.
+func (r *StateReaders) Acquire(ctx context.Context, n int) (func(), error) {
+	if n <= 0 || n > cap(r.slots) {
+		return nil, fmt.Errorf("invalid number of states %d, must be between 1 and %d", n, cap(r.slots))
+	}
+	timeout := time.NewTimer(stateAcquireTimeout)
+	defer timeout.Stop()
+
+	select {
+	case r.acquiring <- struct{}{}:
+		defer func() { <-r.acquiring }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeout.C:
+		return nil, errStatesBusy
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case r.slots <- struct{}{}:
+		case <-ctx.Done():
+			r.release(i)
+			return nil, ctx.Err()
+		case <-timeout.C:
+			r.release(i)
+			return nil, errStatesBusy
+		}
+	}
+	var once sync.Once
+	return func() { once.Do(func() { r.release(n) }) }, nil
+}
+
+// release frees up n previously acquired states.
+func (r *StateReaders) release(n int) {
+	for i := 0; i < n; i++ {
+		<-r.slots
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/state"
+	"github.com/ccmchain/go-ccmchain/core/types"
+	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/log"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// PendingTxFilter selects the pending transactions delivered to a filtered
+// transaction subscription. Unset fields match any transaction, set fields must
+// all match for a transaction to be delivered.
+type PendingTxFilter struct {
+	From        *common.Address `json:"from"`        // Sender of the transaction
+	To          *common.Address `json:"to"`          // Recipient of the transaction
+	MinGasPrice *hexutil.Big    `json:"minGasPrice"` // Lowest gas price accepted
+	Contract    bool            `json:"contract"`    // Only contract creations and calls of accounts with code
+}
+
+// match reports whether the transaction satisfies the filter. The signer is used
+// to derive the sender and hasCode to check whether the recipient is a contract,
+// both only if the respective criteria are set.
+func (f *PendingTxFilter) match(tx *types.Transaction, signer types.Signer, hasCode func(common.Address) bool) bool {
+	to := tx.To()
+	if f.To != nil && (to == nil || *to != *f.To) {
+		return false
+	}
+	if f.MinGasPrice != nil && tx.GasPrice().Cmp(f.MinGasPrice.ToInt()) < 0 {
+		return false
+	}
+	if f.From != nil {
+		if from, err := types.Sender(signer, tx); err != nil || from != *f.From {
+			return false
+		}
+	}
+	if f.Contract && to != nil && !hasCode(*to) {
+		return false
+	}
+	return true
+}
+
+// txFilterTimeout is the maximum time spent retrieving the code of recipients
+// while filtering a single batch of pool transactions.
+const txFilterTimeout = 5 * time.Second
+
+// maxQueuedTxEvents is the number of pool events buffered for filtering before
+// the oldest ones are dropped.
+const maxQueuedTxEvents = 1024
+
+// NewFilteredTxsSubscription creates a subscription delivering the transactions
+// entering the pool that match the given filter. Events without any matching
+// transaction are dropped altogether.
+//
+// Filtering may retrieve contract code, which is slow on light clients, so it is
+// done on a dedicated goroutine while the pool events are queued up, never
+// holding up the delivery of the pool's feed to other subscribers.
+func NewFilteredTxsSubscription(b Backend, filter PendingTxFilter, ch chan<- core.NewTxsEvent) event.Subscription {
+	// Subscribe to the pool right away, not to miss events sent before the
+	// subscription goroutine gets scheduled
+	txs := make(chan core.NewTxsEvent, 128)
+	txsSub := b.SubscribeNewTxsEvent(txs)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer txsSub.Unsubscribe()
+
+		work := make(chan core.NewTxsEvent)
+		go func() {
+			for {
+				select {
+				case ev := <-work:
+					matched := filterTxs(b, &filter, ev.Txs)
+					if len(matched) == 0 {
+						continue
+					}
+					select {
+					case ch <- core.NewTxsEvent{Txs: matched}:
+					case <-quit:
+						return
+					}
+				case <-quit:
+					return
+				}
+			}
+		}()
+		var queue []core.NewTxsEvent
+		for {
+			// Only offer work to the filter if there is any queued up
+			var (
+				next   core.NewTxsEvent
+				workCh chan core.NewTxsEvent
+			)
+			if len(queue) > 0 {
+				next, workCh = queue[0], work
+			}
+			select {
+			case ev := <-txs:
+				if len(queue) >= maxQueuedTxEvents {
+					log.Debug("Dropping filtered pending transactions", "txs", len(queue[0].Txs))
+					queue = queue[1:]
+				}
+				queue = append(queue, ev)
+			case workCh <- next:
+				queue = queue[1:]
+			case err := <-txsSub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// filterTxs returns the transactions matching the filter. The latest state is
+// resolved at most once and the code size of every recipient looked up at most
+// once, all within a bounded time.
+func filterTxs(b Backend, filter *PendingTxFilter, txs []*types.Transaction) []*types.Transaction {
+	ctx, cancel := context.WithTimeout(context.Background(), txFilterTimeout)
+	defer cancel()
+
+	var (
+		statedb  *state.StateDB
+		stateErr error
+		codes    = make(map[common.Address]bool)
+	)
+	hasCode := func(addr common.Address) bool {
+		if known, ok := codes[addr]; ok {
+			return known
+		}
+		if statedb == nil && stateErr == nil {
+			statedb, _, stateErr = b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+			if statedb == nil && stateErr == nil {
+				stateErr = errors.New("latest state unavailable")
+			}
+		}
+		known := stateErr == nil && statedb.GetCodeSize(addr) > 0
+		codes[addr] = known
+		return known
+	}
+	signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
+
+	var matched []*types.Transaction
+	for _, tx := range txs {
+		if filter.match(tx, signer, hasCode) {
+			matched = append(matched, tx)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The go-ccmchain Authors
+// This file is part of the go-ccmchain library.
+//
+// The go-ccmchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ccmchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ccmchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ccmapi
+
+import (
+	"context"
+
+	"github.com/ccmchain/go-ccmchain/common"
+	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/rpc"
+)
+
+// TxInclusionEvent is posted when a watched transaction is included in a
+// canonical block, or when the block including it is reorged out of the chain.
+type TxInclusionEvent struct {
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Removed     bool
+}
+
+// NewTxInclusionSubscription creates a subscription tracking the inclusion of a
+// single transaction in the canonical chain. An event is delivered whenever the
+// transaction gets included in a block, and a removal event whenever that block
+// is subsequently reorged out. A transaction may thus be reported as included,
+// removed and included again (possibly in a different block).
+func NewTxInclusionSubscription(b Backend, txHash common.Hash, ch chan<- TxInclusionEvent) event.Subscription {
+	// Subscribe to the chain right away, not to miss a block included before the
+	// subscription goroutine gets scheduled
+	heads := make(chan core.ChainHeadEvent, 10)
+	headsSub := b.SubscribeChainHeadEvent(heads)
+
+	sides := make(chan core.ChainSideEvent, 10)
+	sidesSub := b.SubscribeChainSideEvent(sides)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer headsSub.Unsubscribe()
+		defer sidesSub.Unsubscribe()
+
+		var (
+			ctx      = context.Background()
+			included *TxInclusionEvent
+		)
+		send := func(ev TxInclusionEvent) bool {
+			select {
+			case ch <- ev:
+				return true
+			case <-quit:
+				return false
+			}
+		}
+		canonical := func(hash common.Hash, number uint64) bool {
+			header, _ := b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			return header != nil && header.Hash() == hash
+		}
+		// update re-evaluates the inclusion status of the transaction, reporting
+		// any change. It returns false if the subscription was torn down.
+		update := func() bool {
+			if included != nil {
+				if canonical(included.BlockHash, included.BlockNumber) {
+					return true
+				}
+				removed := *included
+				removed.Removed, included = true, nil
+				if !send(removed) {
+					return false
+				}
+			}
+			tx, blockHash, number, index, err := b.GetTransaction(ctx, txHash)
+			if err != nil || tx == nil || !canonical(blockHash, number) {
+				return true
+			}
+			included = &TxInclusionEvent{TxHash: txHash, BlockHash: blockHash, BlockNumber: number, Index: index}
+			return send(*included)
+		}
+		if !update() {
+			return nil
+		}
+		for {
+			select {
+			case <-heads:
+				if !update() {
+					return nil
+				}
+			case ev := <-sides:
+				if included != nil && ev.Block.Hash() == included.BlockHash && !update() {
+					return nil
+				}
+			case err := <-headsSub.Err():
+				return err
+			case err := <-sidesSub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
@@ -200,6 +200,14 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'pauseSync',
+			call: 'admin_pauseSync'
+		}),
+		new web3._extend.Method({
+			name: 'resumeSync',
+			call: 'admin_resumeSync'
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -210,10 +218,26 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerChainInfo',
+			getter: 'admin_peerChainInfo'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'enode',
+			getter: 'admin_enode'
+		}),
+		new web3._extend.Property({
+			name: 'enr',
+			getter: 'admin_enr'
+		}),
+		new web3._extend.Property({
+			name: 'syncPaused',
+			getter: 'admin_syncPaused'
+		}),
 	]
 });
 `
@@ -232,6 +256,17 @@ web3._extend({
 			call: 'debug_getBlockRlp',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getHeaderRlp',
+			call: 'debug_getHeaderRlp',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRawReceipts',
+			call: 'debug_getRawReceipts',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'testSignCliqueBlock',
 			call: 'debug_testSignCliqueBlock',
@@ -259,10 +294,59 @@ web3._extend({
 			params: 1,
 			outputFormatter: console.log
 		}),
+		new web3._extend.Method({
+			name: 'exportTxPool',
+			call: 'debug_exportTxPool',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'importTxPool',
+			call: 'debug_importTxPool',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'chaindbIterate',
+			call: 'debug_chaindbIterate',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'chaindbCompact',
 			call: 'debug_chaindbCompact',
 		}),
+		new web3._extend.Method({
+			name: 'chaindbCompactStatus',
+			call: 'debug_chaindbCompactStatus',
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'debug_callBundle',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'forkEffect',
+			call: 'debug_forkEffect',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'decodeTransaction',
+			call: 'debug_decodeTransaction',
+			params: 2,
+			outputFormatter: function(decoded) {
+				if (decoded.error) {
+					return decoded.call ? decoded.call + ' (' + decoded.error + ')' : decoded.error;
+				}
+				return decoded.call;
+			}
+		}),
+		new web3._extend.Method({
+			name: 'cancelRequest',
+			call: 'debug_cancelRequest',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
 		new web3._extend.Method({
 			name: 'verbosity',
 			call: 'debug_verbosity',
@@ -417,12 +501,50 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCall',
+			call: 'debug_traceCall',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'replayTransaction',
+			call: 'debug_replayTransaction',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'rebuildBloomBits',
+			call: 'debug_rebuildBloomBits',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'verifyBlock',
+			call: function(args) {
+				return (web3._extend.utils.isString(args[0]) && args[0].indexOf('0x') === 0 && args[0].length === 66) ? 'debug_verifyBlockByHash' : 'debug_verifyBlock';
+			},
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'stateDiff',
+			call: 'debug_stateDiff',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getTrieNode',
+			call: 'debug_getTrieNode',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'getBadBlocks',
 			call: 'debug_getBadBlocks',
@@ -446,7 +568,12 @@ web3._extend({
 			inputFormatter:[null, null],
 		}),
 	],
-	properties: []
+	properties: [
+		new web3._extend.Property({
+			name: 'runningRequests',
+			getter: 'debug_runningRequests'
+		}),
+	]
 });
 `
 
@@ -459,6 +586,18 @@ web3._extend({
 			call: 'ccm_chainId',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'gasPriceTier',
+			call: 'ccm_gasPriceTier',
+			params: 1,
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+		new web3._extend.Method({
+			name: 'maxPriorityFeePerGas',
+			call: 'ccm_maxPriorityFeePerGas',
+			params: 0,
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'ccm_sign',
@@ -508,6 +647,17 @@ web3._extend({
 			call: 'ccm_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getBalances',
+			call: 'ccm_getBalances',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getReceiptsByTxHashes',
+			call: 'ccm_getReceiptsByTxHashes',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -516,6 +666,53 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
 		}),
+		new web3._extend.Method({
+			name: 'balanceHistory',
+			call: 'ccm_balanceHistory',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex, web3._extend.utils.toHex, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getStorageRangeAt',
+			call: 'ccm_getStorageRangeAt',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'decodeRawTransaction',
+			call: 'ccm_decodeRawTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'ccm_getTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'gasUsageStats',
+			call: 'ccm_gasUsageStats',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getCodeSize',
+			call: 'ccm_getCodeSize',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Method({
+			name: 'getNonceGap',
+			call: 'ccm_getNonceGap',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccount',
+			call: 'ccm_getAccount',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getProof',
 			call: 'ccm_getProof',
@@ -536,6 +733,23 @@ web3._extend({
 				return formatted;
 			}
 		}),
+		new web3._extend.Property({
+			name: 'forkSchedule',
+			getter: 'ccm_forkSchedule'
+		}),
+		new web3._extend.Property({
+			name: 'availableBlockRange',
+			getter: 'ccm_availableBlockRange'
+		}),
+		new web3._extend.Property({
+			name: 'gasPriceFiltering',
+			getter: 'ccm_gasPriceFiltering'
+		}),
+		new web3._extend.Property({
+			name: 'totalDifficulty',
+			getter: 'ccm_totalDifficulty',
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
 	]
 });
 `
@@ -576,6 +790,20 @@ web3._extend({
 			call: 'miner_setRecommitInterval',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'setMaxUncles',
+			call: 'miner_setMaxUncles',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'setWorkSubmissionTimeout',
+			call: 'miner_setWorkSubmissionTimeout',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'staleWorkCount',
+			call: 'miner_staleWorkCount'
+		}),
 		new web3._extend.Method({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
@@ -607,6 +835,11 @@ web3._extend({
 			call: 'personal_importRawKey',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'addKeystore',
+			call: 'personal_addKeystore',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'personal_sign',
@@ -650,6 +883,10 @@ web3._extend({
 			name: 'listWallets',
 			getter: 'personal_listWallets'
 		}),
+		new web3._extend.Property({
+			name: 'accountsInfo',
+			getter: 'personal_accountsInfo'
+		}),
 	]
 })
 `
@@ -714,7 +951,14 @@ web3._extend({
 const TxpoolJs = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'pendingFeeHistogram',
+			call: 'txpool_pendingFeeHistogram',
+			params: 1,
+			inputFormatter: [null]
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	"github.com/ccmchain/go-ccmchain/common/hexutil"
+	"github.com/ccmchain/go-ccmchain/ccm"
 )
 
 var (
@@ -27,6 +28,23 @@ var (
 	errNotActivated = errors.New("checkpoint registrar is not activated")
 )
 
+// PrivateLightAdminAPI is the collection of administrative APIs a light client
+// offers on top of the node's own.
+type PrivateLightAdminAPI struct {
+	les *LightCcmchain
+}
+
+// NewPrivateLightAdminAPI creates a new administrative API for the light client.
+func NewPrivateLightAdminAPI(les *LightCcmchain) *PrivateLightAdminAPI {
+	return &PrivateLightAdminAPI{les: les}
+}
+
+// PeerChainInfo returns the announced head, total difficulty and negotiated
+// protocol version of every connected server.
+func (api *PrivateLightAdminAPI) PeerChainInfo() []*ccm.PeerChainInfo {
+	return api.les.PeerInfo()
+}
+
 // PrivateLightAPI provides an API to access the LES light server or light client.
 type PrivateLightAPI struct {
 	backend *lesCommons
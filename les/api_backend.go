@@ -19,6 +19,7 @@ package les
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ccmchain/go-ccmchain/accounts"
@@ -31,9 +32,11 @@ import (
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/core/vm"
 	"github.com/ccmchain/go-ccmchain/ccm/downloader"
+	"github.com/ccmchain/go-ccmchain/ccm/filters"
 	"github.com/ccmchain/go-ccmchain/ccm/gasprice"
 	"github.com/ccmchain/go-ccmchain/ccmdb"
 	"github.com/ccmchain/go-ccmchain/event"
+	"github.com/ccmchain/go-ccmchain/internal/ccmapi"
 	"github.com/ccmchain/go-ccmchain/light"
 	"github.com/ccmchain/go-ccmchain/params"
 	"github.com/ccmchain/go-ccmchain/rpc"
@@ -43,12 +46,18 @@ type LesApiBackend struct {
 	extRPCEnabled bool
 	ccm           *LightCcmchain
 	gpo           *gasprice.Oracle
+	requests      *ccmapi.RequestRegistry
+	states        *ccmapi.StateReaders
 }
 
 func (b *LesApiBackend) ChainConfig() *params.ChainConfig {
 	return b.ccm.chainConfig
 }
 
+func (b *LesApiBackend) ChainConfigFull() *ccmapi.ChainConfigFull {
+	return ccmapi.NewChainConfigFull(b.ChainConfig(), b.ccm.BlockChain().CurrentHeader().Number.Uint64())
+}
+
 func (b *LesApiBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.ccm.BlockChain().CurrentHeader())
 }
@@ -58,7 +67,18 @@ func (b *LesApiBackend) SetHead(number uint64) {
 	b.ccm.blockchain.SetHead(number)
 }
 
+// resolveConfirmed converts the safe and finalized block tags into the number of
+// the canonical block they currently refer to.
+func (b *LesApiBackend) resolveConfirmed(number rpc.BlockNumber) rpc.BlockNumber {
+	if number != rpc.SafeBlockNumber && number != rpc.FinalizedBlockNumber {
+		return number
+	}
+	head := b.ccm.blockchain.CurrentHeader()
+	return ccmapi.ResolveConfirmedNumber(b.ccm.engine, b.ccm.blockchain.HeaderChain(), head, number, b.ccm.config.SafeDepth, b.ccm.config.FinalityDepth)
+}
+
 func (b *LesApiBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	number = b.resolveConfirmed(number)
 	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
 		return b.ccm.blockchain.CurrentHeader(), nil
 	}
@@ -88,6 +108,72 @@ func (b *LesApiBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	return light.NewState(ctx, header, b.ccm.odr), header, nil
 }
 
+// StatesAndHeaderByNumber opens n independent on-demand states of the same block,
+// which can be used concurrently. The states count against the limit of open
+// states until released.
+func (b *LesApiBackend) StatesAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber, n int) ([]*state.StateDB, *types.Header, func(), error) {
+	release, err := b.states.Acquire(ctx, n)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	header, err := b.HeaderByNumber(ctx, number)
+	if err == nil && header == nil {
+		err = errors.New("header not found")
+	}
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	states := make([]*state.StateDB, n)
+	for i := range states {
+		states[i] = light.NewState(ctx, header, b.ccm.odr)
+	}
+	return states, header, release, nil
+}
+
+// GetCodeSize returns the size of the code deployed at the given address. Note,
+// light clients need to retrieve the code itself to learn its size.
+func (b *LesApiBackend) GetCodeSize(ctx context.Context, addr common.Address, number rpc.BlockNumber) (int, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	size := state.GetCodeSize(addr)
+	return size, state.Error()
+}
+
+// BlockRange returns the lowest and highest block numbers whose full block data
+// is available. Light clients retrieve block bodies from the network on demand,
+// so all blocks up to the head are reported as available.
+func (b *LesApiBackend) BlockRange() (uint64, uint64) {
+	return 0, b.ccm.blockchain.CurrentHeader().Number.Uint64()
+}
+
+// BalancesAt returns the balances of the given accounts in the state of the
+// requested block, resolving the state only once for all of them. Note,
+// light clients still retrieve the proof of every account on demand.
+func (b *LesApiBackend) BalancesAt(ctx context.Context, addrs []common.Address, number rpc.BlockNumber) ([]*big.Int, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		balances[i] = state.GetBalance(addr)
+	}
+	return balances, state.Error()
+}
+
+// GetAccount returns the balance, nonce, code hash, code size and storage root of
+// the given account, all read from a single state of the given block.
+func (b *LesApiBackend) GetAccount(ctx context.Context, addr common.Address, number rpc.BlockNumber) (*ccmapi.AccountInfo, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return ccmapi.ReadAccountInfo(state, addr)
+}
+
 func (b *LesApiBackend) GetHeader(ctx context.Context, hash common.Hash) *types.Header {
 	return b.ccm.blockchain.GetHeaderByHash(hash)
 }
@@ -96,6 +182,50 @@ func (b *LesApiBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.
 	return b.ccm.blockchain.GetBlockByHash(ctx, hash)
 }
 
+// GetUncle returns the uncle header at the given index within the block with the
+// given hash, or nil if either the block or the uncle doesn't exist.
+func (b *LesApiBackend) GetUncle(ctx context.Context, blockHash common.Hash, index int) (*types.Header, error) {
+	body, err := b.ccm.blockchain.GetBody(ctx, blockHash)
+	if body == nil || err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(body.Uncles) {
+		return nil, nil
+	}
+	return body.Uncles[index], nil
+}
+
+// GasUsageStats calculates the gas usage statistics over the given number of most
+// recent canonical blocks. The light client stores all headers locally, so this
+// doesn't require any network retrievals.
+func (b *LesApiBackend) GasUsageStats(ctx context.Context, blocks int) (*ccmapi.GasUsageStats, error) {
+	return ccmapi.ChainGasUsageStats(b.ccm.blockchain, blocks)
+}
+
+// BlockTransactionCountByNumber returns the number of transactions in the block
+// with the given number, counting them in the RLP body without decoding it.
+func (b *LesApiBackend) BlockTransactionCountByNumber(ctx context.Context, number rpc.BlockNumber) (int, error) {
+	header, err := b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return 0, fmt.Errorf("block #%d not found", number)
+	}
+	return b.BlockTransactionCount(ctx, header.Hash())
+}
+
+// BlockTransactionCount returns the number of transactions in the block with
+// the given hash, counting them in the RLP body without decoding it.
+func (b *LesApiBackend) BlockTransactionCount(ctx context.Context, hash common.Hash) (int, error) {
+	number := rawdb.ReadHeaderNumber(b.ccm.chainDb, hash)
+	if number == nil {
+		return 0, fmt.Errorf("block %#x not found", hash)
+	}
+	body, err := light.GetBodyRLP(ctx, b.ccm.odr, hash, *number)
+	if err != nil {
+		return 0, err
+	}
+	return rawdb.CountBodyTxs(body)
+}
+
 func (b *LesApiBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	if number := rawdb.ReadHeaderNumber(b.ccm.chainDb, hash); number != nil {
 		return light.GetBlockReceipts(ctx, b.ccm.odr, hash, *number)
@@ -103,6 +233,12 @@ func (b *LesApiBackend) GetReceipts(ctx context.Context, hash common.Hash) (type
 	return nil, nil
 }
 
+// GetReceiptsByTxHashes returns the minimal receipt info of each transaction,
+// with nil entries for the ones not found in the transaction index.
+func (b *LesApiBackend) GetReceiptsByTxHashes(ctx context.Context, hashes []common.Hash) ([]*ccmapi.ReceiptInfo, error) {
+	return ccmapi.LookupReceiptInfos(ctx, b, hashes)
+}
+
 func (b *LesApiBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
 	if number := rawdb.ReadHeaderNumber(b.ccm.chainDb, hash); number != nil {
 		return light.GetBlockLogs(ctx, b.ccm.odr, hash, *number)
@@ -110,17 +246,79 @@ func (b *LesApiBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*typ
 	return nil, nil
 }
 
+func (b *LesApiBackend) GetLogsInRange(ctx context.Context, from, to uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from, to)
+	}
+	filter := filters.NewRangeFilter(b, int64(from), int64(to), addresses, topics)
+	filter.SetLimit(ccmapi.MaxLogsInRange)
+
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
 func (b *LesApiBackend) GetTd(hash common.Hash) *big.Int {
 	return b.ccm.blockchain.GetTdByHash(hash)
 }
 
-func (b *LesApiBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
+// CurrentTd returns the total difficulty of the current head header.
+func (b *LesApiBackend) CurrentTd() *big.Int {
+	head := b.ccm.blockchain.CurrentHeader()
+	return b.ccm.blockchain.GetTd(head.Hash(), head.Number.Uint64())
+}
+
+// GetEVM creates an EVM executing the message on top of the given state, with
+// the default VM configuration unless vmConfig overrides it.
+func (b *LesApiBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	if vmConfig == nil {
+		vmConfig = new(vm.Config)
+	}
+	return b.newEVM(msg, state, header, b.ccm.chainConfig, *vmConfig)
+}
+
+// GetEVMWithConfig creates an EVM executing the message under the rules of the
+// given chain config instead of the ones of the local chain.
+func (b *LesApiBackend) GetEVMWithConfig(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig) (*vm.EVM, func() error, error) {
+	return b.newEVM(msg, state, header, config, vm.Config{})
+}
+
+// newEVM creates an EVM for the message, funding the sender so that unpriced
+// calls don't run out of balance.
+func (b *LesApiBackend) newEVM(msg core.Message, state *state.StateDB, header *types.Header, config *params.ChainConfig, vmConfig vm.Config) (*vm.EVM, func() error, error) {
 	state.SetBalance(msg.From(), math.MaxBig256)
 	context := core.NewEVMContext(msg, header, b.ccm.blockchain, nil)
-	return vm.NewEVM(context, state, b.ccm.chainConfig, vm.Config{}), state.Error, nil
+	return vm.NewEVM(context, state, config, vmConfig), state.Error, nil
+}
+
+// CallBundle executes the given messages sequentially on top of the state of the
+// requested block, after applying the state overrides. Every message sees the
+// state changes of the ones before it.
+func (b *LesApiBackend) CallBundle(ctx context.Context, msgs []core.Message, number rpc.BlockNumber, overrides ccmapi.StateOverride) ([]*ccmapi.BundleResult, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	overrides.Apply(state)
+	return ccmapi.ApplyBundle(ctx, b.ChainConfig(), b.ccm.blockchain, vm.Config{}, msgs, state, header)
+}
+
+// ForkEffect executes the message on top of the state of the requested block
+// under both the current chain rules and those of the next scheduled fork.
+func (b *LesApiBackend) ForkEffect(ctx context.Context, msg core.Message, number rpc.BlockNumber) (*ccmapi.ForkEffect, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, number)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return ccmapi.CompareForkRules(ctx, b, msg, state, header)
 }
 
 func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if min := b.ccm.config.RPCMinGasPrice; min != nil && signedTx.GasPrice().Cmp(min) < 0 {
+		return ccmapi.ErrRPCUnderpriced
+	}
 	return b.ccm.txPool.Add(ctx, signedTx)
 }
 
@@ -132,10 +330,37 @@ func (b *LesApiBackend) GetPoolTransactions() (types.Transactions, error) {
 	return b.ccm.txPool.GetTransactions()
 }
 
+// PendingFeeHistogram buckets the pending transactions of the light pool by
+// their gas price, providing a live view of the competing prices.
+func (b *LesApiBackend) PendingFeeHistogram(buckets int) ([]*ccmapi.FeeBucket, error) {
+	txs, err := b.ccm.txPool.GetTransactions()
+	if err != nil {
+		return nil, err
+	}
+	return ccmapi.NewFeeHistogram(txs, buckets), nil
+}
+
 func (b *LesApiBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
 	return b.ccm.txPool.GetTransaction(txHash)
 }
 
+func (b *LesApiBackend) HasPoolTransaction(txHash common.Hash) bool {
+	return b.ccm.txPool.GetTransaction(txHash) != nil
+}
+
+// PoolTransactionStatus returns the status of a transaction, reporting locally
+// pooled ones as pending and asking the servers about anything else.
+func (b *LesApiBackend) PoolTransactionStatus(ctx context.Context, txHash common.Hash) core.TxStatus {
+	if b.HasPoolTransaction(txHash) {
+		return core.TxStatusPending
+	}
+	r := &light.TxStatusRequest{Hashes: []common.Hash{txHash}}
+	if err := b.ccm.odr.Retrieve(ctx, r); err != nil || len(r.Status) == 0 {
+		return core.TxStatusUnknown
+	}
+	return r.Status[0].Status
+}
+
 func (b *LesApiBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
 	return light.GetTransaction(ctx, b.ccm.odr, txHash)
 }
@@ -144,6 +369,18 @@ func (b *LesApiBackend) GetPoolNonce(ctx context.Context, addr common.Address) (
 	return b.ccm.txPool.GetNonce(ctx, addr)
 }
 
+// GetPoolNonceGap returns the state nonce advanced over the contiguous pending
+// transactions as next, and whether later pending ones are stranded past it as gapped.
+func (b *LesApiBackend) GetPoolNonceGap(ctx context.Context, addr common.Address) (uint64, bool, error) {
+	return b.ccm.txPool.NonceGap(ctx, addr)
+}
+
+// NextSendableNonce returns the nonce a new transaction needs to be immediately
+// executable. Contrary to the pool nonce, it fills any gap in the pending set.
+func (b *LesApiBackend) NextSendableNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return b.ccm.txPool.SendableNonce(ctx, addr)
+}
+
 func (b *LesApiBackend) Stats() (pending int, queued int) {
 	return b.ccm.txPool.Stats(), 0
 }
@@ -152,10 +389,33 @@ func (b *LesApiBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.ccm.txPool.Content()
 }
 
+// ImportTxPool adds the transactions to the pool, returning the error of each
+// one.
+func (b *LesApiBackend) ImportTxPool(ctx context.Context, txs types.Transactions) []error {
+	errs := make([]error, len(txs))
+	for i, tx := range txs {
+		errs[i] = b.ccm.txPool.Add(ctx, tx)
+	}
+	return errs
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.ccm.txPool.SubscribeNewTxsEvent(ch)
 }
 
+// SubscribeFilteredTxsEvent subscribes to the transactions entering the pool that
+// match the filter. Note, checking for contract recipients retrieves their code
+// on demand from the network.
+func (b *LesApiBackend) SubscribeFilteredTxsEvent(ch chan<- core.NewTxsEvent, filter ccmapi.PendingTxFilter) event.Subscription {
+	return ccmapi.NewFilteredTxsSubscription(b, filter, ch)
+}
+
+// SubscribeDroppedTxsEvent is not supported by light clients, the light pool has
+// no eviction rules and does not report dropped transactions.
+func (b *LesApiBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) (event.Subscription, error) {
+	return nil, ccmapi.ErrDroppedTxsUnsupported
+}
+
 func (b *LesApiBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
 	return b.ccm.blockchain.SubscribeChainEvent(ch)
 }
@@ -164,6 +424,10 @@ func (b *LesApiBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) e
 	return b.ccm.blockchain.SubscribeChainHeadEvent(ch)
 }
 
+func (b *LesApiBackend) SubscribeFinalityHeadEvent(ch chan<- core.FinalityHeadEvent) event.Subscription {
+	return ccmapi.NewFinalityHeadSubscription(b.SubscribeChainHeadEvent, b.ccm.engine, b.ccm.blockchain.HeaderChain(), b.ccm.config.FinalityDepth, ch)
+}
+
 func (b *LesApiBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
 	return b.ccm.blockchain.SubscribeChainSideEvent(ch)
 }
@@ -176,6 +440,10 @@ func (b *LesApiBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEven
 	return b.ccm.blockchain.SubscribeRemovedLogsEvent(ch)
 }
 
+func (b *LesApiBackend) SubscribeAllLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.ccm.blockchain.SubscribeAllLogsEvent(ch)
+}
+
 func (b *LesApiBackend) Downloader() *downloader.Downloader {
 	return b.ccm.Downloader()
 }
@@ -188,6 +456,18 @@ func (b *LesApiBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *LesApiBackend) SuggestPriorityFee(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestPriorityFee(ctx)
+}
+
+func (b *LesApiBackend) SuggestPriceTier(ctx context.Context, tier string) (*big.Int, error) {
+	return b.gpo.SuggestPriceTier(ctx, tier)
+}
+
+func (b *LesApiBackend) GasPriceFiltering() bool {
+	return b.gpo.Filtering()
+}
+
 func (b *LesApiBackend) ChainDb() ccmdb.Database {
 	return b.ccm.chainDb
 }
@@ -208,6 +488,18 @@ func (b *LesApiBackend) RPCGasCap() *big.Int {
 	return b.ccm.config.RPCGasCap
 }
 
+func (b *LesApiBackend) TraceGasCap() *big.Int {
+	return b.ccm.config.TraceGasCap
+}
+
+func (b *LesApiBackend) RPCCallDataCap() uint64 {
+	return b.ccm.config.RPCCallDataCap
+}
+
+func (b *LesApiBackend) Requests() *ccmapi.RequestRegistry {
+	return b.requests
+}
+
 func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	if b.ccm.bloomIndexer == nil {
 		return 0, 0
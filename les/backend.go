@@ -19,6 +19,8 @@ package les
 
 import (
 	"fmt"
+	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -141,7 +143,7 @@ func New(ctx *node.ServiceContext, config *ccm.Config) (*LightCcmchain, error) {
 	}
 
 	lccm.txPool = light.NewTxPool(lccm.chainConfig, lccm.blockchain, lccm.relay)
-	lccm.ApiBackend = &LesApiBackend{ctx.ExtRPCEnabled(), lccm, nil}
+	lccm.ApiBackend = &LesApiBackend{ctx.ExtRPCEnabled(), lccm, nil, ccmapi.NewRequestRegistry(config.RPCTimeouts), ccmapi.NewStateReaders(ccmapi.MaxConcurrentStates)}
 
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
@@ -226,6 +228,11 @@ func (s *LightCcmchain) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateLightAPI(&s.lesCommons, s.protocolManager.reg),
 			Public:    false,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateLightAdminAPI(s),
+			Public:    false,
 		},
 	}...)
 }
@@ -241,6 +248,34 @@ func (s *LightCcmchain) LesVersion() int                    { return int(ClientP
 func (s *LightCcmchain) Downloader() *downloader.Downloader { return s.protocolManager.downloader }
 func (s *LightCcmchain) EventMux() *event.TypeMux           { return s.eventMux }
 
+// PeerInfo returns the announced chain head of every connected server, along with
+// its negotiated protocol version and how it relates to the local chain.
+func (s *LightCcmchain) PeerInfo() []*ccm.PeerChainInfo {
+	head := s.blockchain.CurrentHeader()
+	return peerChainInfos(s.peers.AllPeers(), s.blockchain.GetTd(head.Hash(), head.Number.Uint64()))
+}
+
+// peerChainInfos summarizes the heads announced by the given peers, relative to
+// the local total difficulty.
+func peerChainInfos(peers []*peer, localTd *big.Int) []*ccm.PeerChainInfo {
+	infos := make([]*ccm.PeerChainInfo, 0, len(peers))
+	for _, p := range peers {
+		head := p.headBlockInfo()
+		number := head.Number
+		infos = append(infos, &ccm.PeerChainInfo{
+			ID:         p.ID().String(),
+			Name:       p.Name(),
+			Version:    p.version,
+			Head:       head.Hash,
+			HeadNumber: (*hexutil.Uint64)(&number),
+			Difficulty: head.Td,
+			Ahead:      localTd != nil && head.Td.Cmp(localTd) > 0,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *LightCcmchain) Protocols() []p2p.Protocol {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/forkid"
 	"github.com/ccmchain/go-ccmchain/ccm"
 	"github.com/ccmchain/go-ccmchain/ccmdb"
 	"github.com/ccmchain/go-ccmchain/light"
@@ -48,6 +49,7 @@ type NodeInfo struct {
 	Config     *params.ChainConfig      `json:"config"`     // Chain configuration for the fork rules
 	Head       common.Hash              `json:"head"`       // SHA3 hash of the host's best owned block
 	CHT        params.TrustedCheckpoint `json:"cht"`        // Trused CHT checkpoint for fast catchup
+	ForkID     ccm.NodeForkID           `json:"forkId"`     // Fork identifier currently advertised (EIP-2124)
 }
 
 // makeProtocols creates protocol descriptors for the given LES versions.
@@ -79,6 +81,7 @@ func (c *lesCommons) nodeInfo() interface{} {
 	chain := c.protocolManager.blockchain
 	head := chain.CurrentHeader()
 	hash := head.Hash()
+	id := forkid.NewIDFromHead(chain.Config(), chain.Genesis().Hash(), head.Number.Uint64())
 	return &NodeInfo{
 		Network:    c.config.NetworkId,
 		Difficulty: chain.GetTd(hash, head.Number.Uint64()),
@@ -86,6 +89,7 @@ func (c *lesCommons) nodeInfo() interface{} {
 		Config:     chain.Config(),
 		Head:       chain.CurrentHeader().Hash(),
 		CHT:        c.latestLocalCheckpoint(),
+		ForkID:     ccm.NodeForkID{Hash: id.Hash[:], Next: id.Next},
 	}
 }
 
@@ -17,6 +17,7 @@
 package les
 
 import (
+	"bytes"
 	"encoding/binary"
 	"math/big"
 	"math/rand"
@@ -27,9 +28,11 @@ import (
 	"github.com/ccmchain/go-ccmchain/common/mclock"
 	"github.com/ccmchain/go-ccmchain/consensus/ccmash"
 	"github.com/ccmchain/go-ccmchain/core"
+	"github.com/ccmchain/go-ccmchain/core/forkid"
 	"github.com/ccmchain/go-ccmchain/core/rawdb"
 	"github.com/ccmchain/go-ccmchain/core/types"
 	"github.com/ccmchain/go-ccmchain/crypto"
+	"github.com/ccmchain/go-ccmchain/ccm"
 	"github.com/ccmchain/go-ccmchain/ccm/downloader"
 	"github.com/ccmchain/go-ccmchain/light"
 	"github.com/ccmchain/go-ccmchain/p2p"
@@ -633,3 +636,23 @@ func TestStopResumeLes3(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the light protocol node info advertises the same fork identifier as
+// the one computed from the served chain.
+func TestNodeInfoForkID(t *testing.T) {
+	server, tearDown := newServerEnv(t, 4, 2, nil)
+	defer tearDown()
+
+	commons := &lesCommons{
+		config:           &ccm.Config{NetworkId: NetworkId},
+		protocolManager:  server.pm,
+		chtIndexer:       server.chtIndexer,
+		bloomTrieIndexer: server.bloomTrieIndexer,
+	}
+	info := commons.nodeInfo().(*NodeInfo)
+
+	want := forkid.NewID(server.pm.blockchain.(*core.BlockChain))
+	if !bytes.Equal(info.ForkID.Hash, want.Hash[:]) || info.ForkID.Next != want.Next {
+		t.Fatalf("fork id mismatch: have %x/%d, want %x/%d", info.ForkID.Hash, info.ForkID.Next, want.Hash, want.Next)
+	}
+}
@@ -17,11 +17,13 @@
 package les
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/ccmchain/go-ccmchain/common"
 	"github.com/ccmchain/go-ccmchain/common/mclock"
+	"github.com/ccmchain/go-ccmchain/ccm"
 	"github.com/ccmchain/go-ccmchain/les/flowcontrol"
 	"github.com/ccmchain/go-ccmchain/p2p"
 	"github.com/ccmchain/go-ccmchain/rlp"
@@ -304,3 +306,43 @@ func (s *rwStub) WriteMsg(m p2p.Msg) error {
 
 	return nil
 }
+
+// Tests that the chain summaries of the connected servers report their announced
+// heads, sorted by id, and whether they are ahead of the local chain.
+func TestPeerChainInfos(t *testing.T) {
+	var peers []*peer
+	for i, td := range []int64{200, 100, 50} {
+		peers = append(peers, &peer{
+			Peer:     p2p.NewPeer(newNodeID(t).ID(), fmt.Sprintf("server %d", i), nil),
+			version:  protocol_version,
+			headInfo: &announceData{Hash: common.BytesToHash([]byte{byte(i)}), Number: uint64(i), Td: big.NewInt(td)},
+		})
+	}
+	infos := peerChainInfos(peers, big.NewInt(100))
+	if len(infos) != len(peers) {
+		t.Fatalf("peer count mismatch: have %d, want %d", len(infos), len(peers))
+	}
+	for i, info := range infos {
+		if i > 0 && infos[i-1].ID >= info.ID {
+			t.Errorf("peers not sorted by id: %s before %s", infos[i-1].ID, info.ID)
+		}
+	}
+	for _, p := range peers {
+		var info *ccm.PeerChainInfo
+		for _, candidate := range infos {
+			if candidate.ID == p.ID().String() {
+				info = candidate
+			}
+		}
+		if info == nil {
+			t.Fatalf("peer %s missing", p.ID())
+		}
+		head := p.headBlockInfo()
+		if info.Name != p.Name() || info.Version != protocol_version || info.Head != head.Hash || uint64(*info.HeadNumber) != head.Number || info.Difficulty.Cmp(head.Td) != 0 {
+			t.Errorf("peer %s: chain info mismatch: %+v", p.ID(), info)
+		}
+		if want := head.Td.Cmp(big.NewInt(100)) > 0; info.Ahead != want {
+			t.Errorf("peer %s: ahead mismatch: have %v, want %v", p.ID(), info.Ahead, want)
+		}
+	}
+}
@@ -206,6 +206,10 @@ func (lc *LightChain) ResetWithGenesisBlock(genesis *types.Block) {
 // Engine retrieves the light chain's consensus engine.
 func (lc *LightChain) Engine() consensus.Engine { return lc.engine }
 
+// HeaderChain returns the underlying header chain, which can act as the chain
+// reader of the consensus engine.
+func (lc *LightChain) HeaderChain() *core.HeaderChain { return lc.hc }
+
 // Genesis returns the genesis block
 func (lc *LightChain) Genesis() *types.Block {
 	return lc.genesisBlock
@@ -531,6 +535,12 @@ func (lc *LightChain) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent)
 	return lc.scope.Track(new(event.Feed).Subscribe(ch))
 }
 
+// SubscribeAllLogsEvent implements the interface of ccmapi.Backend
+// LightChain does not send logs events, so return an empty subscription.
+func (lc *LightChain) SubscribeAllLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return lc.scope.Track(new(event.Feed).Subscribe(ch))
+}
+
 // DisableCheckFreq disables header validation. This is used for ultralight mode.
 func (lc *LightChain) DisableCheckFreq() {
 	atomic.StoreInt32(&lc.disableCheckFreq, 1)
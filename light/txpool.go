@@ -133,6 +133,49 @@ func (pool *TxPool) GetNonce(ctx context.Context, addr common.Address) (uint64,
 	return nonce, nil
 }
 
+// SendableNonce returns the nonce a new transaction of the account needs to be
+// immediately executable: the state nonce advanced over the contiguous pending
+// transactions. Unlike GetNonce, it never points past a nonce gap.
+func (pool *TxPool) SendableNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	next, _, err := pool.NonceGap(ctx, addr)
+	return next, err
+}
+
+// NonceGap returns the sendable nonce of an account, along with whether any of
+// its pending transactions are blocked by a gap at that nonce.
+func (pool *TxPool) NonceGap(ctx context.Context, addr common.Address) (uint64, bool, error) {
+	state := pool.currentState(ctx)
+	nonce := state.GetNonce(addr)
+	if state.Error() != nil {
+		return 0, false, state.Error()
+	}
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	nonces := pool.pendingNonces(addr)
+	for nonces[nonce] {
+		nonce++
+	}
+	for pending := range nonces {
+		if pending > nonce {
+			return nonce, true, nil
+		}
+	}
+	return nonce, false, nil
+}
+
+// pendingNonces returns the set of nonces of the account's pending transactions.
+// It assumes that the pool lock is held by the caller.
+func (pool *TxPool) pendingNonces(addr common.Address) map[uint64]bool {
+	nonces := make(map[uint64]bool)
+	for _, tx := range pool.pending {
+		if from, _ := types.Sender(pool.signer, tx); from == addr {
+			nonces[tx.Nonce()] = true
+		}
+	}
+	return nonces
+}
+
 // txStateChanges stores the recent changes between pending/mined states of
 // transactions. True means mined, false means rolled back, no entry means no change
 type txStateChanges map[common.Hash]bool
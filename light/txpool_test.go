@@ -141,3 +141,55 @@ func TestTxPool(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the sendable nonce fills gaps in the pending transactions, whereas
+// the pool nonce points past the highest one, and that such gaps are reported.
+func TestTxPoolSendableNonce(t *testing.T) {
+	var (
+		sdb   = rawdb.NewMemoryDatabase()
+		ldb   = rawdb.NewMemoryDatabase()
+		gspec = core.Genesis{Alloc: core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}}}
+	)
+	gspec.MustCommit(sdb)
+	gspec.MustCommit(ldb)
+
+	odr := &testOdr{sdb: sdb, ldb: ldb, indexerConfig: TestClientIndexerConfig}
+	relay := &testTxRelay{
+		send:    make(chan int, 3),
+		discard: make(chan int, 1),
+		mined:   make(chan int, 1),
+	}
+	lightchain, _ := NewLightChain(odr, params.TestChainConfig, ccmash.NewFullFaker(), nil)
+	pool := NewTxPool(params.TestChainConfig, lightchain, relay)
+	defer pool.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	add := func(nonce uint64) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, acc1Addr, big.NewInt(10000), params.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+		if err := pool.Add(ctx, tx); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	check := func(pooled, sendable uint64, gapped bool) {
+		t.Helper()
+		if nonce, err := pool.GetNonce(ctx, testBankAddress); err != nil || nonce != pooled {
+			t.Errorf("pool nonce mismatch: have %d (%v), want %d", nonce, err, pooled)
+		}
+		if nonce, err := pool.SendableNonce(ctx, testBankAddress); err != nil || nonce != sendable {
+			t.Errorf("sendable nonce mismatch: have %d (%v), want %d", nonce, err, sendable)
+		}
+		if _, gap, err := pool.NonceGap(ctx, testBankAddress); err != nil || gap != gapped {
+			t.Errorf("nonce gap mismatch: have %v (%v), want %v", gap, err, gapped)
+		}
+	}
+	add(0)
+	check(1, 1, false)
+
+	add(2)
+	check(3, 1, true)
+
+	add(1)
+	check(3, 3, false)
+}
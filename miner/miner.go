@@ -51,8 +51,17 @@ type Config struct {
 	GasPrice  *big.Int       // Minimum gas price for mining a transaction
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 	Noverify  bool           // Disable remote mining solution verification(only useful in ccmash).
+	MaxUncles int            // Maximum number of uncles to include in mined blocks (0 = default of 2, NoUncles = none).
 }
 
+// maxUncles is the maximum number of uncles a block may reference according to
+// the consensus rules.
+const maxUncles = 2
+
+// NoUncles is the MaxUncles value disabling the inclusion of uncles in mined
+// blocks, since the zero value stands for the protocol default.
+const NoUncles = -1
+
 // Miner creates blocks and searches for proof-of-work values.
 type Miner struct {
 	mux      *event.TypeMux
@@ -159,6 +168,17 @@ func (self *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+// SetMaxUncles sets the maximum number of uncles to include in mined blocks.
+// Unlike in the configuration, the count is taken literally, zero disabling the
+// inclusion of uncles.
+func (self *Miner) SetMaxUncles(count int) error {
+	if count < 0 || count > maxUncles {
+		return fmt.Errorf("uncle count %d out of range [0, %d]", count, maxUncles)
+	}
+	self.worker.setMaxUncles(count)
+	return nil
+}
+
 // SetRecommitInterval sets the interval for sealing work resubmitting.
 func (self *Miner) SetRecommitInterval(interval time.Duration) {
 	self.worker.setRecommitInterval(interval)
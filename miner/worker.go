@@ -151,9 +151,10 @@ type worker struct {
 	remoteUncles map[common.Hash]*types.Block // A set of side blocks as the possible uncle blocks.
 	unconfirmed  *unconfirmedBlocks           // A set of locally mined blocks pending canonicalness confirmations.
 
-	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
-	coinbase common.Address
-	extra    []byte
+	mu        sync.RWMutex // The lock used to protect the coinbase, extra and uncle limit fields
+	coinbase  common.Address
+	extra     []byte
+	maxUncles int
 
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
@@ -206,6 +207,18 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	worker.chainHeadSub = ccm.BlockChain().SubscribeChainHeadEvent(worker.chainHeadCh)
 	worker.chainSideSub = ccm.BlockChain().SubscribeChainSideEvent(worker.chainSideCh)
 
+	// Sanitize the uncle limit if the user-specified one is outside consensus bounds.
+	switch {
+	case config.MaxUncles == 0:
+		worker.maxUncles = maxUncles
+	case config.MaxUncles < 0:
+		worker.maxUncles = 0
+	case config.MaxUncles > maxUncles:
+		log.Warn("Sanitizing miner uncle limit", "provided", config.MaxUncles, "updated", maxUncles)
+		worker.maxUncles = maxUncles
+	default:
+		worker.maxUncles = config.MaxUncles
+	}
 	// Sanitize recommit interval if the user-specified one is too short.
 	recommit := worker.config.Recommit
 	if recommit < minRecommitInterval {
@@ -238,6 +251,20 @@ func (w *worker) setExtra(extra []byte) {
 	w.extra = extra
 }
 
+// setMaxUncles sets the maximum number of uncles included in mined blocks.
+func (w *worker) setMaxUncles(count int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.maxUncles = count
+}
+
+// uncleLimit returns the maximum number of uncles included in mined blocks.
+func (w *worker) uncleLimit() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.maxUncles
+}
+
 // setRecommitInterval updates the interval for miner sealing work recommitting.
 func (w *worker) setRecommitInterval(interval time.Duration) {
 	w.resubmitIntervalCh <- interval
@@ -421,9 +448,9 @@ func (w *worker) mainLoop() {
 			} else {
 				w.remoteUncles[ev.Block.Hash()] = ev.Block
 			}
-			// If our mining block contains less than 2 uncle blocks,
+			// If our mining block contains less uncle blocks than allowed,
 			// add the new uncle block if valid and regenerate a mining block.
-			if w.isRunning() && w.current != nil && w.current.uncles.Cardinality() < 2 {
+			if w.isRunning() && w.current != nil && w.current.uncles.Cardinality() < w.uncleLimit() {
 				start := time.Now()
 				if err := w.commitUncle(w.current, ev.Block.Header()); err == nil {
 					var uncles []*types.Header
@@ -891,7 +918,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		misc.ApplyDAOHardFork(env.state)
 	}
 	// Accumulate the uncles for the current block
-	uncles := make([]*types.Header, 0, 2)
+	uncles := make([]*types.Header, 0, w.maxUncles)
 	commitUncles := func(blocks map[common.Hash]*types.Block) {
 		// Clean up stale uncle blocks first
 		for hash, uncle := range blocks {
@@ -900,7 +927,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 			}
 		}
 		for hash, uncle := range blocks {
-			if len(uncles) == 2 {
+			if len(uncles) >= w.maxUncles {
 				break
 			}
 			if err := w.commitUncle(env, uncle.Header()); err != nil {
@@ -293,6 +293,103 @@ func TestStreamUncleBlock(t *testing.T) {
 	}
 }
 
+func TestStreamUncleBlockDisabled(t *testing.T) {
+	ccmash := ccmash.NewFaker()
+	defer ccmash.Close()
+
+	w, b := newTestWorker(t, ccmashChainConfig, ccmash, 1)
+	defer w.close()
+
+	miner := &Miner{worker: w}
+	if err := miner.SetMaxUncles(0); err != nil {
+		t.Fatalf("failed to disable uncles: %v", err)
+	}
+	var taskCh = make(chan struct{}, 3)
+	w.newTaskHook = func(task *task) {
+		if task.block.NumberU64() == 2 {
+			if uncles := len(task.block.Uncles()); uncles != 0 {
+				t.Errorf("uncle count mismatch: have %d, want 0", uncles)
+			}
+			taskCh <- struct{}{}
+		}
+	}
+	w.skipSealHook = func(task *task) bool {
+		return true
+	}
+	w.fullTaskHook = func() {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Ensure worker has finished initialization
+	for {
+		b := w.pendingBlock()
+		if b != nil && b.NumberU64() == 2 {
+			break
+		}
+	}
+	w.start()
+
+	// Ignore the first two works
+	for i := 0; i < 2; i += 1 {
+		select {
+		case <-taskCh:
+		case <-time.NewTimer(time.Second).C:
+			t.Error("new task timeout")
+		}
+	}
+	// Uncles are disabled, so the side block must not trigger a new task
+	b.PostChainEvents([]interface{}{core.ChainSideEvent{Block: b.uncleBlock}})
+
+	select {
+	case <-taskCh:
+		t.Error("new task created for uncle block")
+	case <-time.NewTimer(500 * time.Millisecond).C:
+	}
+}
+
+func TestMaxUnclesBounds(t *testing.T) {
+	tests := []struct {
+		config int
+		want   int
+	}{
+		{0, 2},
+		{NoUncles, 0},
+		{-5, 0},
+		{1, 1},
+		{2, 2},
+		{3, 2},
+	}
+	for i, tt := range tests {
+		backend := newTestWorkerBackend(t, ccmashChainConfig, ccmash.NewFaker(), 0)
+
+		config := *testConfig
+		config.MaxUncles = tt.config
+		w := newWorker(&config, ccmashChainConfig, ccmash.NewFaker(), backend, new(event.TypeMux), nil)
+		if have := w.uncleLimit(); have != tt.want {
+			t.Errorf("test %d: uncle limit mismatch: have %d, want %d", i, have, tt.want)
+		}
+		w.close()
+	}
+	// Runtime updates are taken literally, but must stay within consensus bounds
+	w, _ := newTestWorker(t, ccmashChainConfig, ccmash.NewFaker(), 0)
+	defer w.close()
+
+	miner := &Miner{worker: w}
+	for _, count := range []int{-1, 3} {
+		if err := miner.SetMaxUncles(count); err == nil {
+			t.Errorf("uncle count %d: expected error", count)
+		}
+	}
+	for _, count := range []int{0, 1, 2} {
+		if err := miner.SetMaxUncles(count); err != nil {
+			t.Errorf("uncle count %d: failed to set: %v", count, err)
+		}
+		if have := w.uncleLimit(); have != count {
+			t.Errorf("uncle count %d: limit mismatch: have %d", count, have)
+		}
+	}
+}
+
 func TestRegenerateMiningBlockEthash(t *testing.T) {
 	testRegenerateMiningBlock(t, ccmashChainConfig, ccmash.NewFaker())
 }
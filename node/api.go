@@ -290,6 +290,25 @@ func (api *PublicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
 	return server.NodeInfo(), nil
 }
 
+// Enode retrieves the enode URL of the host node, advertising the ports the
+// listeners are actually bound to.
+func (api *PublicAdminAPI) Enode() (string, error) {
+	server := api.node.Server()
+	if server == nil {
+		return "", ErrNodeStopped
+	}
+	return server.Self().URLv4(), nil
+}
+
+// Enr retrieves the textual (base64 encoded) node record of the host node.
+func (api *PublicAdminAPI) Enr() (string, error) {
+	server := api.node.Server()
+	if server == nil {
+		return "", ErrNodeStopped
+	}
+	return server.Self().String(), nil
+}
+
 // Datadir retrieves the current data directory the node is using.
 func (api *PublicAdminAPI) Datadir() string {
 	return api.node.DataDir()
@@ -162,6 +162,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// RPCMaxSubscriptions is the maximum number of subscriptions a single websocket
+	// or IPC connection may hold open at the same time. Zero means no limit.
+	RPCMaxSubscriptions int `toml:",omitempty"`
+
 	// GraphQLHost is the host interface on which to start the GraphQL server. If this
 	// field is empty, no GraphQL API endpoint will be started.
 	GraphQLHost string `toml:",omitempty"`
@@ -26,6 +26,7 @@ import (
 
 	"github.com/ccmchain/go-ccmchain/crypto"
 	"github.com/ccmchain/go-ccmchain/p2p"
+	"github.com/ccmchain/go-ccmchain/p2p/enode"
 	"github.com/ccmchain/go-ccmchain/rpc"
 )
 
@@ -597,3 +598,51 @@ func TestAPIGather(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the enode URL and node record reported by the admin API advertise
+// the actual listening port, even if the node was configured with port 0.
+func TestAdminEnodeAndEnr(t *testing.T) {
+	config := testNodeConfig()
+	config.P2P.ListenAddr = "127.0.0.1:0"
+	config.P2P.NoDiscovery = true
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	defer stack.Close()
+
+	api := NewPublicAdminAPI(stack)
+	if _, err := api.Enode(); err != ErrNodeStopped {
+		t.Fatalf("enode error mismatch on stopped node: have %v, want %v", err, ErrNodeStopped)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	defer stack.Stop()
+
+	url, err := api.Enode()
+	if err != nil {
+		t.Fatalf("failed to retrieve enode: %v", err)
+	}
+	record, err := api.Enr()
+	if err != nil {
+		t.Fatalf("failed to retrieve enr: %v", err)
+	}
+	port := stack.Server().Self().TCP()
+	if port == 0 {
+		t.Fatalf("local node advertises port 0")
+	}
+	for _, text := range []string{url, record} {
+		node, err := enode.Parse(enode.ValidSchemes, text)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", text, err)
+		}
+		if node.ID() != stack.Server().Self().ID() {
+			t.Errorf("node ID mismatch in %q", text)
+		}
+		if node.TCP() != port {
+			t.Errorf("port mismatch in %q: have %d, want %d", text, node.TCP(), port)
+		}
+	}
+}
@@ -1055,6 +1055,7 @@ type NodeInfo struct {
 		Listener  int `json:"listener"`  // TCP listening port for RLPx
 	} `json:"ports"`
 	ListenAddr string                 `json:"listenAddr"`
+	Caps       []string               `json:"caps"` // Enabled sub-protocols with their versions (e.g. ccm/64)
 	Protocols  map[string]interface{} `json:"protocols"`
 }
 
@@ -1076,6 +1077,7 @@ func (srv *Server) NodeInfo() *NodeInfo {
 
 	// Gather all the running protocol infos (only once per protocol type)
 	for _, proto := range srv.Protocols {
+		info.Caps = append(info.Caps, Cap{proto.Name, proto.Version}.String())
 		if _, ok := info.Protocols[proto.Name]; !ok {
 			nodeInfo := interface{}("unknown")
 			if query := proto.NodeInfo; query != nil {
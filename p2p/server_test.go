@@ -724,3 +724,30 @@ func (l *fakeAddrListener) Accept() (net.Conn, error) {
 func (c *fakeAddrConn) RemoteAddr() net.Addr {
 	return c.remoteAddr
 }
+
+// Tests that the node info lists the capabilities of every enabled protocol
+// version, even if several versions of a protocol share the same name.
+func TestServerNodeInfoCaps(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			Name:       "test",
+			MaxPeers:   10,
+			ListenAddr: "127.0.0.1:0",
+			PrivateKey: newkey(),
+			Protocols:  []Protocol{{Name: "ccm", Version: 64}, {Name: "ccm", Version: 63}, {Name: "les", Version: 2}},
+			Logger:     testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	info := srv.NodeInfo()
+	if want := []string{"ccm/64", "ccm/63", "les/2"}; !reflect.DeepEqual(info.Caps, want) {
+		t.Errorf("capabilities mismatch: have %v, want %v", info.Caps, want)
+	}
+	if len(info.Protocols) != 2 {
+		t.Errorf("protocol count mismatch: have %d, want 2", len(info.Protocols))
+	}
+}
@@ -61,9 +61,11 @@ type handler struct {
 	conn           jsonWriter                     // where responses will be sent
 	log            log.Logger
 	allowSubscribe bool
+	maxSubs        int // maximum number of server subscriptions, 0 = unlimited
 
-	subLock    sync.Mutex
-	serverSubs map[ID]*Subscription
+	subLock     sync.Mutex
+	serverSubs  map[ID]*Subscription
+	pendingSubs int // subscriptions being set up, counted against maxSubs
 }
 
 type callProc struct {
@@ -197,6 +199,9 @@ func (h *handler) addSubscriptions(nn []*Notifier) {
 	h.subLock.Lock()
 	defer h.subLock.Unlock()
 
+	// Every notifier holds a slot reserved by handleSubscribe, release them
+	// whether or not the subscription was actually created.
+	h.pendingSubs -= len(nn)
 	for _, n := range nn {
 		if sub := n.takeSubscription(); sub != nil {
 			h.serverSubs[sub.ID] = sub
@@ -347,7 +352,6 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
-
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
 	args, err := parsePositionalArguments(msg.Params, argTypes)
@@ -356,6 +360,12 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	}
 	args = args[1:]
 
+	// Reserve a slot for the subscription, released by addSubscriptions.
+	if !h.reserveSubscription() {
+		h.log.Debug("Rejected subscription, limit reached", "limit", h.maxSubs)
+		return msg.errorResponse(ErrTooManySubscriptions)
+	}
+
 	// Install notifier in context so the subscription handler can find it.
 	n := &Notifier{h: h, namespace: namespace}
 	cp.notifiers = append(cp.notifiers, n)
@@ -364,6 +374,21 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	return h.runMethod(ctx, msg, callb, args)
 }
 
+// reserveSubscription counts a new subscription against the limit before it is
+// set up. Calls run concurrently and only register their subscriptions once
+// answered, so checking the active ones alone would let pipelined requests slip
+// past the limit.
+func (h *handler) reserveSubscription() bool {
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	if h.maxSubs > 0 && len(h.serverSubs)+h.pendingSubs >= h.maxSubs {
+		return false
+	}
+	h.pendingSubs++
+	return true
+}
+
 // runMethod runs the Go callback for an RPC method.
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
 	result, err := callb.call(ctx, msg.Method, args)
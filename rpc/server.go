@@ -46,6 +46,7 @@ type Server struct {
 	idgen    func() ID
 	run      int32
 	codecs   mapset.Set
+	maxSubs  int32 // maximum number of subscriptions per connection, 0 = unlimited
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -66,6 +67,13 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetMaxSubscriptions limits the number of subscriptions a single connection may
+// hold open at the same time. Zero disables the limit. The limit only applies to
+// connections established after the call.
+func (s *Server) SetMaxSubscriptions(limit int) {
+	atomic.StoreInt32(&s.maxSubs, int32(limit))
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -83,7 +91,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	c := initClient(codec, s.idgen, &s.services, int(atomic.LoadInt32(&s.maxSubs)))
 	<-codec.Closed()
 	c.Close()
 }
@@ -37,6 +37,8 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrTooManySubscriptions is returned when a connection exceeds its subscription limit
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
 )
 
 var globalGen = randomIDGenerator()
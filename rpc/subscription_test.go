@@ -168,6 +168,88 @@ func TestServerUnsubscribe(t *testing.T) {
 	}
 }
 
+// This test checks that the per-connection subscription limit is enforced.
+func TestServerSubscriptionLimit(t *testing.T) {
+	server := newTestServer()
+	server.SetMaxSubscriptions(2)
+	service := &notificationTestService{}
+	server.RegisterName("nftest", service)
+	p1, p2 := net.Pipe()
+	go server.ServeCodec(NewJSONCodec(p1), OptionMethodInvocation|OptionSubscriptions)
+	defer p2.Close()
+
+	p2.SetDeadline(time.Now().Add(10 * time.Second))
+
+	resps := make(chan subConfirmation)
+	notifications := make(chan subscriptionResult)
+	errors := make(chan error)
+	go waitForMessages(json.NewDecoder(p2), resps, notifications, errors)
+
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(p2, `{"jsonrpc":"2.0","id":%d,"method":"nftest_subscribe","params":["someSubscription",0,10]}`, i)
+		select {
+		case <-resps:
+			if i > 2 {
+				t.Fatalf("subscription %d accepted above the limit", i)
+			}
+		case err := <-errors:
+			if i <= 2 {
+				t.Fatalf("subscription %d rejected: %v", i, err)
+			}
+			if err.Error() != ErrTooManySubscriptions.Error() {
+				t.Fatalf("wrong error: have %q, want %q", err, ErrTooManySubscriptions)
+			}
+		}
+	}
+}
+
+// This test checks that the subscription limit holds for subscribe requests that
+// are pipelined without waiting for the answers, and thus run concurrently.
+func TestServerSubscriptionLimitConcurrent(t *testing.T) {
+	const (
+		limit    = 2
+		requests = 20
+	)
+	server := newTestServer()
+	server.SetMaxSubscriptions(limit)
+	service := &notificationTestService{}
+	server.RegisterName("nftest", service)
+	p1, p2 := net.Pipe()
+	go server.ServeCodec(NewJSONCodec(p1), OptionMethodInvocation|OptionSubscriptions)
+	defer p2.Close()
+
+	p2.SetDeadline(time.Now().Add(10 * time.Second))
+
+	resps := make(chan subConfirmation)
+	notifications := make(chan subscriptionResult)
+	errors := make(chan error)
+	go waitForMessages(json.NewDecoder(p2), resps, notifications, errors)
+
+	// Send all the requests before reading any answers
+	go func() {
+		for i := 1; i <= requests; i++ {
+			fmt.Fprintf(p2, `{"jsonrpc":"2.0","id":%d,"method":"nftest_subscribe","params":["someSubscription",0,10]}`, i)
+		}
+	}()
+	var accepted, rejected int
+	for accepted+rejected < requests {
+		select {
+		case <-resps:
+			accepted++
+		case err := <-errors:
+			if err.Error() != ErrTooManySubscriptions.Error() {
+				t.Fatalf("wrong error: have %q, want %q", err, ErrTooManySubscriptions)
+			}
+			rejected++
+		case <-notifications:
+			// drop notifications
+		}
+	}
+	if accepted != limit {
+		t.Fatalf("accepted subscriptions mismatch: have %d, want %d", accepted, limit)
+	}
+}
+
 type subConfirmation struct {
 	reqid int
 	subid ID
@@ -51,6 +51,18 @@ type rulesetUI struct {
 	next    core.UIClientAPI // The next handler, for manual processing
 	storage storage.Storage
 	jsRules string // The rules to use
+
+	manualDeployments bool // Whether contract deployments bypass the rules
+}
+
+// manualDeploymentReason is the reason attached to contract deployments which are
+// forwarded for manual approval without consulting the ruleset.
+const manualDeploymentReason = "Contract deployments require manual approval"
+
+// isDeployment reports whether the transaction creates a contract, as opposed to
+// a regular transfer or contract call.
+func isDeployment(tx *core.SendTxArgs) bool {
+	return tx.To == nil
 }
 
 func NewRuleEvaluator(next core.UIClientAPI, jsbackend storage.Storage) (*rulesetUI, error) {
@@ -70,6 +82,13 @@ func (r *rulesetUI) Init(javascriptRules string) error {
 	r.jsRules = javascriptRules
 	return nil
 }
+
+// RequireManualDeployments sets whether contract deployment transactions should
+// always be forwarded for manual approval, regardless of the javascript rules.
+func (r *rulesetUI) RequireManualDeployments(enabled bool) {
+	r.manualDeployments = enabled
+}
+
 func (r *rulesetUI) execute(jsfunc string, jsarg interface{}) (otto.Value, error) {
 
 	// Instantiate a fresh vm engine every time
@@ -156,6 +175,11 @@ func (r *rulesetUI) checkApproval(jsfunc string, jsarg []byte, err error) (bool,
 }
 
 func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	if r.manualDeployments && request != nil && isDeployment(&request.Transaction) {
+		log.Info("Rule-based approval skipped, going to manual", "reason", manualDeploymentReason)
+		request.Callinfo = append(request.Callinfo, core.ValidationInfo{Typ: core.WARN, Message: manualDeploymentReason})
+		return r.next.ApproveTx(request)
+	}
 	jsonreq, err := json.Marshal(request)
 	approved, err := r.checkApproval("ApproveTx", jsonreq, err)
 	if err != nil {
@@ -274,6 +274,48 @@ func TestForwarding(t *testing.T) {
 
 }
 
+// TestManualDeployments tests that contract deployments are forwarded for manual
+// approval when requested, while regular transactions still hit the rules.
+func TestManualDeployments(t *testing.T) {
+	js := `function ApproveTx(r){ return "Approve" }`
+
+	ui := &dummyUI{make([]string, 0)}
+	r, err := NewRuleEvaluator(ui, storage.NewEphemeralStorage())
+	if err != nil {
+		t.Fatalf("Failed to create js engine: %v", err)
+	}
+	if err = r.Init(js); err != nil {
+		t.Fatalf("Failed to load bootstrap js: %v", err)
+	}
+	r.RequireManualDeployments(true)
+
+	from, _ := mixAddr("0000000000000000000000000000000000001337")
+	to, _ := mixAddr("000000000000000000000000000000000000dead")
+
+	// Regular transactions should be approved by the ruleset
+	resp, err := r.ApproveTx(&core.SignTxRequest{Transaction: core.SendTxArgs{From: *from, To: to}})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !resp.Approved {
+		t.Errorf("Expected transfer to be approved by the rules")
+	}
+	if len(ui.calls) != 0 {
+		t.Errorf("Expected no forwarded calls, got %s", strings.Join(ui.calls, ","))
+	}
+	// Deployments should be forwarded to the next UI with the reason attached
+	request := &core.SignTxRequest{Transaction: core.SendTxArgs{From: *from}}
+	if resp, _ = r.ApproveTx(request); resp.Approved {
+		t.Errorf("Expected deployment not to be approved by the rules")
+	}
+	if len(ui.calls) != 1 || ui.calls[0] != "ApproveTx" {
+		t.Errorf("Expected deployment to be forwarded, got %s", strings.Join(ui.calls, ","))
+	}
+	if len(request.Callinfo) != 1 || request.Callinfo[0].Message != manualDeploymentReason {
+		t.Errorf("Expected deployment reason to be attached, got %v", request.Callinfo)
+	}
+}
+
 func TestMissingFunc(t *testing.T) {
 	r, err := initRuleEngine(JS)
 	if err != nil {